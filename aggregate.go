@@ -0,0 +1,168 @@
+package geoindex
+
+import "math"
+
+// Aggregator accumulates the items of an Aggregate query.
+type Aggregator interface {
+	// Add adds an item that intersects the query.
+	Add(min, max [2]float64, data interface{})
+}
+
+// NodeSummary is the metadata of a node that's fully inside of an Aggregate
+// query, which comes from the CountedChildren, Counter, or NodeAggregator
+// interfaces of the tree.
+type NodeSummary struct {
+	Min, Max [2]float64 // the box of the node
+	Count    int        // the number of items under the node
+	Sum      [2]float64 // the sum of the centers of the items, when HasSum
+	HasSum   bool
+}
+
+// NodeAdder is an optional interface for an Aggregator that can add all of
+// the items under a node at once, from the metadata of the node.
+type NodeAdder interface {
+	// AddNode adds all of the items under a node. Returns false when the
+	// summary isn't enough, in which case the items of the node are added
+	// one at a time instead.
+	AddNode(node NodeSummary) bool
+}
+
+// Aggregate adds all items that intersect the rect param to the aggregator
+// in a single traversal. When the aggregator is a NodeAdder and the wrapped
+// tree keeps the number of items under its nodes, then the nodes that are
+// fully inside of the rect are added without visiting their items.
+func (index *Index) Aggregate(min, max [2]float64, agg Aggregator) {
+	defer index.stats.end(opSearch, index.stats.begin())
+	adder, _ := agg.(NodeAdder)
+	counted, isCounted := index.tree.(CountedChildren)
+	counter, isCounter := index.tree.(Counter)
+	nodeAgg, isNodeAgg := index.tree.(NodeAggregator)
+	if adder == nil || (!isCounted && !isCounter && !isNodeAgg) {
+		index.tree.Search(min, max,
+			func(min, max [2]float64, data interface{}) bool {
+				agg.Add(min, max, data)
+				return true
+			},
+		)
+		return
+	}
+	var walk func(parent interface{})
+	walk = func(parent interface{}) {
+		buf := getChildren()
+		var counts []int
+		if isCounted {
+			*buf, counts = counted.CountedChildren(parent, *buf, nil)
+		} else {
+			*buf = index.tree.Children(parent, *buf)
+		}
+		for i, c := range *buf {
+			if !intersects(min, max, c.Min, c.Max) {
+				continue
+			}
+			if c.Item {
+				agg.Add(c.Min, c.Max, c.Data)
+				continue
+			}
+			if contains(min, max, c.Min, c.Max) {
+				node := NodeSummary{Min: c.Min, Max: c.Max}
+				switch {
+				case isNodeAgg:
+					node.Count, node.Sum = nodeAgg.NodeAggregate(c.Data)
+					node.HasSum = true
+				case isCounted:
+					node.Count = counts[i]
+				default:
+					node.Count = counter.NodeCount(c.Data)
+				}
+				if adder.AddNode(node) {
+					continue
+				}
+			}
+			walk(c.Data)
+		}
+		putChildren(buf)
+	}
+	walk(nil)
+}
+
+// CountAggregator is an Aggregator for the number of items.
+type CountAggregator struct {
+	Count int
+}
+
+// Add adds an item.
+func (agg *CountAggregator) Add(min, max [2]float64, data interface{}) {
+	agg.Count++
+}
+
+// AddNode adds the items under a node.
+func (agg *CountAggregator) AddNode(node NodeSummary) bool {
+	agg.Count += node.Count
+	return true
+}
+
+// CentroidAggregator is an Aggregator for the mean position of the items,
+// where the position of an item is the center of its box.
+type CentroidAggregator struct {
+	Count int
+	Sum   [2]float64
+}
+
+// Add adds an item.
+func (agg *CentroidAggregator) Add(min, max [2]float64, data interface{}) {
+	agg.Count++
+	agg.Sum[0] += (min[0] + max[0]) / 2
+	agg.Sum[1] += (min[1] + max[1]) / 2
+}
+
+// AddNode adds the items under a node when the summary has the sum of their
+// centers.
+func (agg *CentroidAggregator) AddNode(node NodeSummary) bool {
+	if !node.HasSum {
+		return false
+	}
+	agg.Count += node.Count
+	agg.Sum[0] += node.Sum[0]
+	agg.Sum[1] += node.Sum[1]
+	return true
+}
+
+// Centroid returns the mean position. Returns false when there are no items.
+func (agg *CentroidAggregator) Centroid() (center [2]float64, ok bool) {
+	if agg.Count == 0 {
+		return center, false
+	}
+	n := float64(agg.Count)
+	return [2]float64{agg.Sum[0] / n, agg.Sum[1] / n}, true
+}
+
+// BoundsAggregator is an Aggregator for the bounding box of the items.
+// OK is false when there are no items.
+type BoundsAggregator struct {
+	Min, Max [2]float64
+	OK       bool
+}
+
+// Add adds an item.
+func (agg *BoundsAggregator) Add(min, max [2]float64, data interface{}) {
+	if !agg.OK {
+		agg.Min, agg.Max, agg.OK = min, max, true
+		return
+	}
+	agg.Min[0] = math.Min(agg.Min[0], min[0])
+	agg.Min[1] = math.Min(agg.Min[1], min[1])
+	agg.Max[0] = math.Max(agg.Max[0], max[0])
+	agg.Max[1] = math.Max(agg.Max[1], max[1])
+}
+
+// SumAggregator is an Aggregator for the sum of a value of the items, where
+// the Value function returns the value of an item.
+type SumAggregator struct {
+	Value func(min, max [2]float64, data interface{}) float64
+	Sum   float64
+}
+
+// Add adds an item.
+func (agg *SumAggregator) Add(min, max [2]float64, data interface{}) {
+	agg.Sum += agg.Value(min, max, data)
+}
@@ -0,0 +1,79 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// multiAggregator adds each item to all of its aggregators
+type multiAggregator []Aggregator
+
+func (aggs multiAggregator) Add(min, max [2]float64, data interface{}) {
+	for _, agg := range aggs {
+		agg.Add(min, max, data)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	boxes := randBoxes(10000)
+	agg := &aggTree{RTree: &internal.RTree{}}
+	counter := &countTree{Interface: struct{ Interface }{&internal.RTree{}}}
+	for _, tr := range []Interface{
+		&internal.RTree{}, struct{ Interface }{&internal.RTree{}}, agg, counter,
+	} {
+		index := Wrap(tr)
+		for i, box := range boxes {
+			index.Insert(box.min, box.max, i)
+		}
+		for _, r := range [][2][2]float64{
+			{{-180, -90}, {180, 90}},
+			{{-50, -50}, {50, 50}},
+			{{200, 200}, {300, 300}},
+		} {
+			var expect multiAggregator = []Aggregator{
+				&CountAggregator{}, &CentroidAggregator{}, &BoundsAggregator{},
+				&SumAggregator{Value: func(min, max [2]float64,
+					data interface{}) float64 {
+					return float64(data.(int))
+				}},
+			}
+			index.Search(r[0], r[1],
+				func(min, max [2]float64, data interface{}) bool {
+					expect.Add(min, max, data)
+					return true
+				},
+			)
+			var count CountAggregator
+			var centroid CentroidAggregator
+			var bounds BoundsAggregator
+			sum := SumAggregator{Value: func(min, max [2]float64,
+				data interface{}) float64 {
+				return float64(data.(int))
+			}}
+			for _, agg := range []Aggregator{&count, &centroid, &bounds, &sum} {
+				index.Aggregate(r[0], r[1], agg)
+			}
+			if count != *expect[0].(*CountAggregator) {
+				t.Fatalf("%v: expected %v, got %v", r, expect[0], count)
+			}
+			ec, eok := expect[1].(*CentroidAggregator).Centroid()
+			c, ok := centroid.Centroid()
+			if ok != eok || math.Abs(c[0]-ec[0]) > 1e-9 ||
+				math.Abs(c[1]-ec[1]) > 1e-9 {
+				t.Fatalf("%v: expected %v, got %v", r, ec, c)
+			}
+			if bounds != *expect[2].(*BoundsAggregator) {
+				t.Fatalf("%v: expected %v, got %v", r, expect[2], bounds)
+			}
+			if sum.Sum != expect[3].(*SumAggregator).Sum {
+				t.Fatalf("%v: expected %v, got %v", r,
+					expect[3].(*SumAggregator).Sum, sum.Sum)
+			}
+		}
+	}
+	if agg.calls == 0 || counter.calls == 0 {
+		t.Fatal("expected the node metadata to be used")
+	}
+}
@@ -69,3 +69,27 @@ func intersects(aMin, aMax, bMin, bMax [2]float64) bool {
 	}
 	return true
 }
+
+// BoxMaxDistCalc returns the squared distance between the points of
+// rectangle A and rectangle B that are farthest from each other, which is
+// never smaller than the distance from A to any point in B.
+func BoxMaxDistCalc(aMin, aMax, bMin, bMax [2]float64) float64 {
+	dx := mmax(aMax[0]-bMin[0], bMax[0]-aMin[0])
+	dy := mmax(aMax[1]-bMin[1], bMax[1]-aMin[1])
+	return dx*dx + dy*dy
+}
+
+// Farthest returns an algo for Index.Farthest that orders the items by the
+// box distance from the target, like Box, from the largest to the smallest.
+// The distance of a node is BoxMaxDistCalc, which is never smaller than the
+// distance of any item in the node.
+func Farthest(targetMin, targetMax [2]float64) (
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) {
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		if item {
+			return BoxDistCalc(targetMin, targetMax, min, max, false)
+		}
+		return BoxMaxDistCalc(targetMin, targetMax, min, max)
+	}
+}
@@ -25,3 +25,21 @@ func TestBoxDist(t *testing.T) {
 		t.Fatalf("unexpected results")
 	}
 }
+
+func TestBoxMaxDist(t *testing.T) {
+	a := [2][2]float64{{0, 0}, {1, 1}}
+	b := [2][2]float64{{3, -1}, {4, 0}}
+	if d := BoxMaxDistCalc(a[0], a[1], b[0], b[1]); d != 4*4+2*2 {
+		t.Fatalf("expected %v, got %v", 20, d)
+	}
+	if d := BoxMaxDistCalc(a[0], a[1], a[0], a[1]); d != 2 {
+		t.Fatalf("expected %v, got %v", 2, d)
+	}
+	algo := Farthest([2]float64{0, 0}, [2]float64{0, 0})
+	if d := algo(b[0], b[1], nil, true); d != 9 {
+		t.Fatalf("expected %v, got %v", 9, d)
+	}
+	if d := algo(b[0], b[1], nil, false); d != 17 {
+		t.Fatalf("expected %v, got %v", 17, d)
+	}
+}
@@ -0,0 +1,62 @@
+package algo
+
+import "math"
+
+// Haversine returns the great-circle distance in meters between two lon,lat
+// points in degrees on a spherical Earth.
+func Haversine(a, b [2]float64) float64 {
+	lat1, lat2 := radians(a[1]), radians(b[1])
+	dlat := lat2 - lat1
+	dlon := radians(b[0] - a[0])
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// HaversineBox returns the great-circle distance in meters from a lon,lat
+// point to the nearest point of a lon,lat box, or zero when the point is
+// inside the box. The edges of the box follow the meridians and parallels,
+// and the distance is correct across the antimeridian and near the poles.
+func HaversineBox(p, min, max [2]float64) float64 {
+	if p[0] >= min[0] && p[0] <= max[0] {
+		// the closest point is straight north or south
+		lat := math.Max(min[1], math.Min(max[1], p[1]))
+		return Haversine(p, [2]float64{p[0], lat})
+	}
+	// Otherwise the closest point is on the meridian edge with the smallest
+	// difference in longitude, going either way around the Earth.
+	dmin := math.Mod(min[0]-p[0]+720, 360)
+	dmax := math.Mod(p[0]-max[0]+720, 360)
+	lon, dlon := min[0], dmin
+	if dmax < dmin {
+		lon, dlon = max[0], dmax
+	}
+	if dlon >= 90 {
+		// The distance along the meridian has its maximum between the poles,
+		// so the closest point is one of the corners.
+		return math.Min(Haversine(p, [2]float64{lon, min[1]}),
+			Haversine(p, [2]float64{lon, max[1]}))
+	}
+	// The distance along the meridian has its minimum at this latitude
+	lat := degrees(math.Atan(math.Tan(radians(p[1])) /
+		math.Cos(radians(dlon))))
+	lat = math.Max(min[1], math.Min(max[1], lat))
+	return Haversine(p, [2]float64{lon, lat})
+}
+
+// Geodesic returns a kNN algo for the great-circle distance in meters from a
+// target point, so that Nearby results are ordered by the real world distance
+// rather than the distance in degrees. The distance of a node is the distance
+// to the nearest point of its box, which is never larger than the distance of
+// any item in the node.
+func Geodesic(targetLon, targetLat float64) (
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) {
+	target := [2]float64{targetLon, targetLat}
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		if min == max {
+			return Haversine(target, min)
+		}
+		return HaversineBox(target, min, max)
+	}
+}
@@ -0,0 +1,80 @@
+package algo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHaversine(t *testing.T) {
+	// a quarter of the equator
+	expect := math.Pi / 2 * EarthRadius
+	if d := Haversine([2]float64{0, 0}, [2]float64{90, 0}); math.Abs(
+		d-expect) > 1e-6 {
+		t.Fatalf("expected %v, got %v", expect, d)
+	}
+	// a degree of longitude is much shorter near the poles
+	d1 := Haversine([2]float64{0, 0}, [2]float64{1, 0})
+	d2 := Haversine([2]float64{0, 80}, [2]float64{1, 80})
+	if d2 >= d1/5 {
+		t.Fatalf("expected %v < %v", d2, d1/5)
+	}
+	// across the antimeridian
+	if d := Haversine([2]float64{179.5, 0}, [2]float64{-179.5, 0}); math.Abs(
+		d-d1) > 1e-6 {
+		t.Fatalf("expected %v, got %v", d1, d)
+	}
+}
+
+func TestHaversineBox(t *testing.T) {
+	min, max := [2]float64{10, 10}, [2]float64{20, 20}
+	if d := HaversineBox([2]float64{15, 15}, min, max); d != 0 {
+		t.Fatalf("expected %v, got %v", 0, d)
+	}
+	if d := HaversineBox([2]float64{15, 25}, min, max); math.Abs(
+		d-Haversine([2]float64{15, 25}, [2]float64{15, 20})) > 1e-6 {
+		t.Fatalf("unexpected %v", d)
+	}
+	// brute force against points on the box edges
+	for i := 0; i < 250; i++ {
+		p := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		bmin := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		bmax := [2]float64{
+			math.Min(180, bmin[0]+rand.Float64()*60),
+			math.Min(90, bmin[1]+rand.Float64()*60),
+		}
+		d := HaversineBox(p, bmin, bmax)
+		best := math.Inf(1)
+		for j := 0; j <= 2000; j++ {
+			f := float64(j) / 2000
+			lon := bmin[0] + (bmax[0]-bmin[0])*f
+			lat := bmin[1] + (bmax[1]-bmin[1])*f
+			for _, q := range [][2]float64{
+				{lon, bmin[1]}, {lon, bmax[1]}, {bmin[0], lat}, {bmax[0], lat},
+			} {
+				best = math.Min(best, Haversine(p, q))
+			}
+		}
+		if d > best+1e-6 {
+			t.Fatalf("%v %v %v: distance %v is larger than %v",
+				p, bmin, bmax, d, best)
+		}
+		// the samples are up to 0.015 degrees from the nearest point
+		if best-d > 2000 && !(p[0] >= bmin[0] && p[0] <= bmax[0] &&
+			p[1] >= bmin[1] && p[1] <= bmax[1]) {
+			t.Fatalf("%v %v %v: distance %v is much smaller than %v",
+				p, bmin, bmax, d, best)
+		}
+	}
+}
+
+func TestGeodesic(t *testing.T) {
+	algo := Geodesic(0, 0)
+	p := [2]float64{3, 4}
+	if d := algo(p, p, nil, true); d != Haversine([2]float64{0, 0}, p) {
+		t.Fatalf("unexpected %v", d)
+	}
+	if d := algo([2]float64{-1, -1}, [2]float64{1, 1}, nil, false); d != 0 {
+		t.Fatalf("expected %v, got %v", 0, d)
+	}
+}
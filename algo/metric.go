@@ -0,0 +1,109 @@
+package algo
+
+import (
+	"sort"
+	"sync"
+)
+
+// Metric is a distance function that can be used for kNN-type operations.
+type Metric interface {
+	// BoxDist returns the distance from the target point to the nearest
+	// point of the box, which must never be larger than the PointDist of
+	// any point in the box.
+	BoxDist(target, min, max [2]float64) float64
+	// PointDist returns the distance between two points.
+	PointDist(a, b [2]float64) float64
+	// Spherical returns true when the coordinates are lon,lat degrees on a
+	// sphere, and false when they are planar.
+	Spherical() bool
+}
+
+type planarMetric struct{}
+
+func (planarMetric) BoxDist(target, min, max [2]float64) float64 {
+	return BoxDistCalc(target, target, min, max, false)
+}
+
+func (planarMetric) PointDist(a, b [2]float64) float64 {
+	return BoxDistCalc(a, a, b, b, false)
+}
+
+func (planarMetric) Spherical() bool { return false }
+
+type geodesicMetric struct{}
+
+func (geodesicMetric) BoxDist(target, min, max [2]float64) float64 {
+	return HaversineBox(target, min, max)
+}
+
+func (geodesicMetric) PointDist(a, b [2]float64) float64 {
+	return Haversine(a, b)
+}
+
+func (geodesicMetric) Spherical() bool { return true }
+
+var (
+	// PlanarMetric is the planar squared distance, the same as BoxDistCalc.
+	// It's registered as "planar".
+	PlanarMetric Metric = planarMetric{}
+	// GeodesicMetric is the great-circle distance in meters, the same as
+	// Geodesic. It's registered as "geodesic".
+	GeodesicMetric Metric = geodesicMetric{}
+)
+
+var metrics = struct {
+	sync.RWMutex
+	m map[string]Metric
+}{m: map[string]Metric{
+	"planar":   PlanarMetric,
+	"geodesic": GeodesicMetric,
+}}
+
+// Register makes a metric available by name, such as for choosing it from
+// a configuration. Register panics when the metric is nil or when a metric
+// has already been registered with the same name.
+func Register(name string, m Metric) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	if m == nil {
+		panic("algo: Register metric is nil")
+	}
+	if _, dup := metrics.m[name]; dup {
+		panic("algo: Register called twice for metric " + name)
+	}
+	metrics.m[name] = m
+}
+
+// Lookup returns the metric that was registered with the name.
+func Lookup(name string) (m Metric, ok bool) {
+	metrics.RLock()
+	defer metrics.RUnlock()
+	m, ok = metrics.m[name]
+	return m, ok
+}
+
+// Names returns the sorted names of the registered metrics.
+func Names() []string {
+	metrics.RLock()
+	defer metrics.RUnlock()
+	names := make([]string, 0, len(metrics.m))
+	for name := range metrics.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FromMetric returns a kNN algo for the distance from a target point using a
+// metric. The PointDist is used for items that are points, and the BoxDist
+// for everything else.
+func FromMetric(m Metric, target [2]float64) (
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) {
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		if item && min == max {
+			return m.PointDist(target, min)
+		}
+		return m.BoxDist(target, min, max)
+	}
+}
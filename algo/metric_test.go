@@ -0,0 +1,68 @@
+package algo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// manhattan is a metric for the taxicab distance on projected coordinates
+type manhattan struct{}
+
+func (manhattan) BoxDist(target, min, max [2]float64) float64 {
+	var dist float64
+	for i := 0; i < 2; i++ {
+		dist += math.Max(0, math.Max(min[i]-target[i], target[i]-max[i]))
+	}
+	return dist
+}
+
+func (m manhattan) PointDist(a, b [2]float64) float64 {
+	return m.BoxDist(a, b, b)
+}
+
+func (manhattan) Spherical() bool { return false }
+
+func TestMetric(t *testing.T) {
+	if names := Names(); !reflect.DeepEqual(names,
+		[]string{"geodesic", "planar"}) {
+		t.Fatalf("unexpected %v", names)
+	}
+	Register("manhattan", manhattan{})
+	m, ok := Lookup("manhattan")
+	if !ok || m.Spherical() {
+		t.Fatal("expected manhattan")
+	}
+	if _, ok := Lookup("nope"); ok {
+		t.Fatal("expected no metric")
+	}
+	target := [2]float64{0, 0}
+	algo := FromMetric(m, target)
+	if dist := algo([2]float64{3, 4}, [2]float64{3, 4}, nil, true); dist != 7 {
+		t.Fatalf("expected %v, got %v", 7, dist)
+	}
+	if dist := algo([2]float64{1, -1}, [2]float64{2, 1}, nil, false); dist != 1 {
+		t.Fatalf("expected %v, got %v", 1, dist)
+	}
+	p, q := [2]float64{170, 10}, [2]float64{-170, 10}
+	if dist := FromMetric(GeodesicMetric, p)(q, q, nil, true); dist !=
+		Haversine(p, q) {
+		t.Fatalf("expected %v, got %v", Haversine(p, q), dist)
+	}
+	if dist := FromMetric(PlanarMetric, p)(q, q, nil, true); dist != 340*340 {
+		t.Fatalf("expected %v, got %v", 340*340, dist)
+	}
+	for _, fn := range []func(){
+		func() { Register("manhattan", manhattan{}) },
+		func() { Register("nil", nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
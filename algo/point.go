@@ -0,0 +1,18 @@
+package algo
+
+// Point returns a kNN algo for a lon,lat target point. When spherical is
+// false the distance is the planar squared distance in degrees, with the
+// longitude wrapped at the antimeridian, in the same way as Box with wrapX.
+// When spherical is true the distance is the great-circle distance in meters,
+// in the same way as Geodesic.
+func Point(lon, lat float64, spherical bool) (
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) {
+	if spherical {
+		return Geodesic(lon, lat)
+	}
+	target := [2]float64{lon, lat}
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		return BoxDistCalc(target, target, min, max, true)
+	}
+}
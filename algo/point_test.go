@@ -0,0 +1,31 @@
+package algo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPoint(t *testing.T) {
+	planar := Point(179, 10, false)
+	// across the antimeridian
+	p := [2]float64{-179, 11}
+	if dist := planar(p, p, nil, true); math.Abs(dist-5) > 1e-9 {
+		t.Fatalf("expected %v, got %v", 5, dist)
+	}
+	min, max := [2]float64{-180, -10}, [2]float64{-170, 10}
+	if dist := planar(min, max, nil, false); math.Abs(dist-1) > 1e-9 {
+		t.Fatalf("expected %v, got %v", 1, dist)
+	}
+	spherical := Point(179, 10, true)
+	if dist, expect := spherical(p, p, nil, true),
+		Haversine([2]float64{179, 10}, p); dist != expect {
+		t.Fatalf("expected %v, got %v", expect, dist)
+	}
+	if dist, expect := spherical(min, max, nil, false),
+		HaversineBox([2]float64{179, 10}, min, max); dist != expect {
+		t.Fatalf("expected %v, got %v", expect, dist)
+	}
+	if dist := spherical(min, max, nil, false); dist > 120000 {
+		t.Fatalf("expected less than %v, got %v", 120000, dist)
+	}
+}
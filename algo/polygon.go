@@ -0,0 +1,139 @@
+package algo
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth in meters.
+const EarthRadius = 6371008.8
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// openRing returns the ring without the closing point, if any
+func openRing(ring [][2]float64) [][2]float64 {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		return ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// ringArea returns the area of a ring on the unit sphere
+func ringArea(ring [][2]float64) float64 {
+	ring = openRing(ring)
+	if len(ring) < 3 {
+		return 0
+	}
+	// The signed area of each edge is the spherical excess of the triangle
+	// that's formed with the south pole.
+	var area float64
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		dlon := radians(b[0] - a[0])
+		k := math.Sin(radians(a[1])/2+math.Pi/4) *
+			math.Sin(radians(b[1])/2+math.Pi/4)
+		u := math.Cos(radians(a[1])/2+math.Pi/4)*
+			math.Cos(radians(b[1])/2+math.Pi/4) + k*math.Cos(dlon)
+		v := k * math.Sin(dlon)
+		area += math.Atan2(v, u)
+	}
+	area = math.Abs(area * 2)
+	if area > 2*math.Pi {
+		// the ring was wound around the other side of the sphere
+		area = 4*math.Pi - area
+	}
+	return area
+}
+
+// PolygonArea returns the area in square meters of a polygon on a spherical
+// Earth. The rings are lon,lat points in degrees with edges that are great
+// circle arcs, and they may be open or closed. The holes are subtracted from
+// the area of the exterior.
+func PolygonArea(exterior [][2]float64, holes ...[][2]float64) float64 {
+	area := ringArea(exterior)
+	for _, hole := range holes {
+		area -= ringArea(hole)
+	}
+	return area * EarthRadius * EarthRadius
+}
+
+func toVector(p [2]float64) [3]float64 {
+	lon, lat := radians(p[0]), radians(p[1])
+	return [3]float64{
+		math.Cos(lat) * math.Cos(lon),
+		math.Cos(lat) * math.Sin(lon),
+		math.Sin(lat),
+	}
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func norm(a [3]float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+// ringMoment returns the first moment of the area of a ring on the unit
+// sphere, which is the integral of the position over the area. The ring is
+// taken to be the side that's smaller than a hemisphere.
+func ringMoment(ring [][2]float64) [3]float64 {
+	ring = openRing(ring)
+	var m, mean [3]float64
+	for i := range ring {
+		a, b := toVector(ring[i]), toVector(ring[(i+1)%len(ring)])
+		// By Stokes' theorem the moment is half of the integral of r × dr
+		// around the ring. For a great circle arc this is the unit normal
+		// of the arc scaled by its angle.
+		n := cross(a, b)
+		l := norm(n)
+		if l == 0 {
+			continue
+		}
+		theta := math.Atan2(l, dot(a, b))
+		for j := 0; j < 3; j++ {
+			m[j] += n[j] / l * theta / 2
+			mean[j] += a[j]
+		}
+	}
+	if dot(m, mean) < 0 {
+		// the ring is clockwise
+		m = [3]float64{-m[0], -m[1], -m[2]}
+	}
+	return m
+}
+
+// PolygonCentroid returns the centroid of a polygon on a spherical Earth as a
+// lon,lat point in degrees. This is the center of mass of the surface of the
+// polygon, projected to the sphere. See PolygonArea for a description of the
+// rings. Returns false when the centroid is undefined, such as for an empty
+// polygon.
+func PolygonCentroid(exterior [][2]float64, holes ...[][2]float64) (
+	centroid [2]float64, ok bool,
+) {
+	m := ringMoment(exterior)
+	for _, hole := range holes {
+		h := ringMoment(hole)
+		for j := 0; j < 3; j++ {
+			m[j] -= h[j]
+		}
+	}
+	l := norm(m)
+	if l < 1e-15 {
+		return centroid, false
+	}
+	lat := math.Asin(math.Max(-1, math.Min(1, m[2]/l)))
+	lon := math.Atan2(m[1], m[0])
+	return [2]float64{degrees(lon), degrees(lat)}, true
+}
@@ -0,0 +1,75 @@
+package algo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonArea(t *testing.T) {
+	// an octant of the sphere is an eighth of its area
+	octant := [][2]float64{{0, 0}, {90, 0}, {0, 90}}
+	expect := 4 * math.Pi * EarthRadius * EarthRadius / 8
+	if area := PolygonArea(octant); math.Abs(area-expect) > 1 {
+		t.Fatalf("expected %v, got %v", expect, area)
+	}
+	// clockwise and closed
+	cw := [][2]float64{{0, 0}, {0, 90}, {90, 0}, {0, 0}}
+	if area := PolygonArea(cw); math.Abs(area-expect) > 1 {
+		t.Fatalf("expected %v, got %v", expect, area)
+	}
+	// a small square near the equator is nearly planar
+	sq := [][2]float64{{0, 0}, {0.01, 0}, {0.01, 0.01}, {0, 0.01}}
+	side := radians(0.01) * EarthRadius
+	if area := PolygonArea(sq); math.Abs(area-side*side)/area > 1e-4 {
+		t.Fatalf("expected %v, got %v", side*side, area)
+	}
+	// crossing the antimeridian
+	am := [][2]float64{{179.995, 0}, {-179.995, 0}, {-179.995, 0.01},
+		{179.995, 0.01}}
+	if area := PolygonArea(am); math.Abs(area-side*side)/area > 1e-4 {
+		t.Fatalf("expected %v, got %v", side*side, area)
+	}
+	// with a hole
+	hole := [][2]float64{{0.0025, 0.0025}, {0.0075, 0.0025},
+		{0.0075, 0.0075}, {0.0025, 0.0075}}
+	if area := PolygonArea(sq, hole); math.Abs(area-side*side*0.75)/area >
+		1e-4 {
+		t.Fatalf("expected %v, got %v", side*side*0.75, area)
+	}
+	if area := PolygonArea(sq[:2]); area != 0 {
+		t.Fatalf("expected %v, got %v", 0, area)
+	}
+}
+
+func TestPolygonCentroid(t *testing.T) {
+	near := func(a, b [2]float64) bool {
+		return math.Abs(a[0]-b[0]) < 1e-6 && math.Abs(a[1]-b[1]) < 1e-6
+	}
+	sq := [][2]float64{{10, 10}, {12, 10}, {12, 12}, {10, 12}}
+	c, ok := PolygonCentroid(sq)
+	if !ok || math.Abs(c[0]-11) > 1e-9 || c[1] < 10.9 || c[1] > 11.1 {
+		t.Fatalf("unexpected centroid %v", c)
+	}
+	// reversed winding gives the same centroid
+	rev := [][2]float64{{10, 12}, {12, 12}, {12, 10}, {10, 10}}
+	if c2, ok := PolygonCentroid(rev); !ok || !near(c, c2) {
+		t.Fatalf("expected %v, got %v", c, c2)
+	}
+	// symmetric about the antimeridian
+	am := [][2]float64{{179, -1}, {-179, -1}, {-179, 1}, {179, 1}}
+	c, ok = PolygonCentroid(am)
+	if !ok || math.Abs(math.Abs(c[0])-180) > 1e-9 || math.Abs(c[1]) > 1e-9 {
+		t.Fatalf("unexpected centroid %v", c)
+	}
+	// a hole on one side moves the centroid to the other side
+	hole := [][2]float64{{10.1, 10.9}, {10.9, 10.9}, {10.9, 11.9},
+		{10.1, 11.9}}
+	c0, _ := PolygonCentroid(sq)
+	c, ok = PolygonCentroid(sq, hole)
+	if !ok || c[0] <= c0[0] {
+		t.Fatalf("expected centroid east of %v, got %v", c0, c)
+	}
+	if _, ok := PolygonCentroid(nil); ok {
+		t.Fatal("expected false")
+	}
+}
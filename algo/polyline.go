@@ -0,0 +1,121 @@
+package algo
+
+import "math"
+
+// segmentBoxDist returns the squared distance from segment AB to a box, which
+// is zero when the segment intersects the box.
+func segmentBoxDist(a, b, min, max [2]float64) float64 {
+	// clip the segment to the box
+	t0, t1 := 0.0, 1.0
+	d := [2]float64{b[0] - a[0], b[1] - a[1]}
+	clips := true
+	for i := 0; i < 2 && clips; i++ {
+		if d[i] == 0 {
+			clips = a[i] >= min[i] && a[i] <= max[i]
+			continue
+		}
+		lo, hi := (min[i]-a[i])/d[i], (max[i]-a[i])/d[i]
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		t0, t1 = mmax(t0, lo), mmin(t1, hi)
+		clips = t0 <= t1
+	}
+	if clips {
+		return 0
+	}
+	dist := mmin(BoxDistCalc(a, a, min, max, false),
+		BoxDistCalc(b, b, min, max, false))
+	for _, p := range [4][2]float64{
+		min, max, {min[0], max[1]}, {max[0], min[1]},
+	} {
+		dist = mmin(dist, SegmentDistCalc(p, a, b))
+	}
+	return dist
+}
+
+// arcDist returns the great-circle distance in meters from point P to the
+// shortest great-circle arc between A and B.
+func arcDist(p, a, b [2]float64) float64 {
+	va, vb, vp := toVector(a), toVector(b), toVector(p)
+	n := cross(va, vb)
+	if l := norm(n); l > 0 {
+		n = [3]float64{n[0] / l, n[1] / l, n[2] / l}
+		// the projection of P onto the great circle of the arc
+		s := dot(vp, n)
+		c := [3]float64{vp[0] - s*n[0], vp[1] - s*n[1], vp[2] - s*n[2]}
+		if dot(cross(va, c), n) >= 0 && dot(cross(c, vb), n) >= 0 {
+			return math.Atan2(math.Abs(s), norm(c)) * EarthRadius
+		}
+	}
+	return math.Min(Haversine(p, a), Haversine(p, b))
+}
+
+// Polyline returns a kNN algo for the distance to a path of lon,lat points,
+// such as for finding the nearest items along a route.
+//
+// When spherical is false the distance is the planar squared distance in
+// degrees to the nearest segment of the path.
+//
+// When spherical is true the segments of the path are great-circle arcs and
+// the distance is in meters. The distance of a point is exact, and for other
+// boxes it's a lower bound that uses the distance from the box center,
+// because the nearest point of a box to an arc is not on a meridian or
+// parallel in general. A box that contains a point of the path has a
+// distance of zero.
+func Polyline(points [][2]float64, spherical bool) (
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) {
+	if !spherical {
+		return func(min, max [2]float64, data interface{}, item bool) float64 {
+			dist := math.Inf(1)
+			for i := range points {
+				j := i + 1
+				if j == len(points) {
+					if i > 0 {
+						break
+					}
+					j = i
+				}
+				dist = mmin(dist, segmentBoxDist(points[i], points[j], min, max))
+			}
+			return dist
+		}
+	}
+	pointDist := func(p [2]float64) float64 {
+		dist := math.Inf(1)
+		for i := range points {
+			j := i + 1
+			if j == len(points) {
+				if i > 0 {
+					break
+				}
+				j = i
+			}
+			dist = math.Min(dist, arcDist(p, points[i], points[j]))
+		}
+		return dist
+	}
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		if min == max {
+			return pointDist(min)
+		}
+		for _, p := range points {
+			if p[0] >= min[0] && p[0] <= max[0] &&
+				p[1] >= min[1] && p[1] <= max[1] {
+				return 0
+			}
+		}
+		// the radius of the box from its center, using the corners and the
+		// middles of the edges
+		c := [2]float64{(min[0] + max[0]) / 2, (min[1] + max[1]) / 2}
+		var r float64
+		for _, p := range [8][2]float64{
+			min, max, {min[0], max[1]}, {max[0], min[1]},
+			{c[0], min[1]}, {c[0], max[1]}, {min[0], c[1]}, {max[0], c[1]},
+		} {
+			r = math.Max(r, Haversine(c, p))
+		}
+		return math.Max(0, pointDist(c)-r)
+	}
+}
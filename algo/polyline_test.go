@@ -0,0 +1,70 @@
+package algo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPolyline(t *testing.T) {
+	path := [][2]float64{{0, 0}, {10, 0}, {10, 10}}
+	planar := Polyline(path, false)
+	for _, tc := range []struct {
+		min, max [2]float64
+		dist     float64
+	}{
+		{[2]float64{5, 1}, [2]float64{5, 1}, 1},
+		{[2]float64{5, -1}, [2]float64{6, 1}, 0},
+		{[2]float64{12, 5}, [2]float64{13, 6}, 4},
+		{[2]float64{-3, -4}, [2]float64{-3, -4}, 25},
+		{[2]float64{2, 2}, [2]float64{3, 3}, 4},
+	} {
+		if dist := planar(tc.min, tc.max, nil, true); math.Abs(
+			dist-tc.dist) > 1e-9 {
+			t.Fatalf("%v %v: expected %v, got %v", tc.min, tc.max, tc.dist, dist)
+		}
+	}
+	if dist := Polyline([][2]float64{{1, 1}}, false)(
+		[2]float64{4, 5}, [2]float64{4, 5}, nil, true); dist != 25 {
+		t.Fatalf("expected %v, got %v", 25, dist)
+	}
+	if dist := Polyline(nil, false)(
+		[2]float64{4, 5}, [2]float64{4, 5}, nil, true); !math.IsInf(dist, 1) {
+		t.Fatalf("expected %v, got %v", math.Inf(1), dist)
+	}
+
+	spherical := Polyline(path, true)
+	// a point north of the equator segment
+	p := [2]float64{5, 1}
+	if dist, expect := spherical(p, p, nil, true),
+		Haversine(p, [2]float64{5, 0}); math.Abs(dist-expect) > 1e-3 {
+		t.Fatalf("expected %v, got %v", expect, dist)
+	}
+	// past the end of the path
+	p = [2]float64{10, 20}
+	if dist, expect := spherical(p, p, nil, true),
+		Haversine(p, [2]float64{10, 10}); math.Abs(dist-expect) > 1e-3 {
+		t.Fatalf("expected %v, got %v", expect, dist)
+	}
+	// a box that contains a point of the path
+	if dist := spherical([2]float64{9, 9}, [2]float64{11, 11}, nil,
+		false); dist != 0 {
+		t.Fatalf("expected %v, got %v", 0, dist)
+	}
+	// boxes are a lower bound of their points
+	for i := 0; i < 1000; i++ {
+		min := [2]float64{rand.Float64()*60 - 30, rand.Float64()*60 - 30}
+		max := [2]float64{min[0] + rand.Float64()*10, min[1] + rand.Float64()*10}
+		bdist := spherical(min, max, nil, false)
+		for j := 0; j < 10; j++ {
+			q := [2]float64{
+				min[0] + (max[0]-min[0])*rand.Float64(),
+				min[1] + (max[1]-min[1])*rand.Float64(),
+			}
+			if pdist := spherical(q, q, nil, true); pdist < bdist-1e-6 {
+				t.Fatalf("%v %v: box distance %v is larger than %v at %v",
+					min, max, bdist, pdist, q)
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package algo
+
+// SegmentPoint returns the point on segment AB that is nearest to point P and
+// the position of that point along the segment as a fraction, where 0 is A
+// and 1 is B.
+func SegmentPoint(p, a, b [2]float64) (point [2]float64, t float64) {
+	dx := b[0] - a[0]
+	dy := b[1] - a[1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return a, 0
+	}
+	t = ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / l2
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	point[0] = a[0] + t*dx
+	point[1] = a[1] + t*dy
+	return point, t
+}
+
+// SegmentDistCalc returns the squared distance from point P to segment AB.
+func SegmentDistCalc(p, a, b [2]float64) float64 {
+	q, _ := SegmentPoint(p, a, b)
+	dx := p[0] - q[0]
+	dy := p[1] - q[1]
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,28 @@
+package algo
+
+import (
+	"testing"
+)
+
+func TestSegmentPoint(t *testing.T) {
+	a, b := [2]float64{0, 0}, [2]float64{10, 0}
+	p, f := SegmentPoint([2]float64{4, 3}, a, b)
+	if p != [2]float64{4, 0} || f != 0.4 {
+		t.Fatalf("unexpected results: %v %v", p, f)
+	}
+	p, f = SegmentPoint([2]float64{-5, 3}, a, b)
+	if p != a || f != 0 {
+		t.Fatalf("unexpected results: %v %v", p, f)
+	}
+	p, f = SegmentPoint([2]float64{15, -3}, a, b)
+	if p != b || f != 1 {
+		t.Fatalf("unexpected results: %v %v", p, f)
+	}
+	p, f = SegmentPoint([2]float64{15, -3}, a, a)
+	if p != a || f != 0 {
+		t.Fatalf("unexpected results: %v %v", p, f)
+	}
+	if SegmentDistCalc([2]float64{4, 3}, a, b) != 9 {
+		t.Fatalf("unexpected results")
+	}
+}
@@ -0,0 +1,27 @@
+package algo
+
+// Weighted scales the distances of a kNN algo by the weight of each item,
+// such that the distance of an item is its distance from the base algo
+// divided by its weight. Items with larger weights, such as higher priority
+// drivers for dispatching, are returned before items at a similar distance
+// with smaller weights.
+//
+// The weight function must return a value that's greater than zero and no
+// larger than maxWeight. The distance of a node is its distance from the base
+// algo divided by maxWeight, which is never larger than the distance of any
+// item in the node.
+func Weighted(
+	base func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	weight func(min, max [2]float64, data interface{}) float64,
+	maxWeight float64,
+) (
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) {
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		dist := base(min, max, data, item)
+		if item {
+			return dist / weight(min, max, data)
+		}
+		return dist / maxWeight
+	}
+}
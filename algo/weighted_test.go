@@ -0,0 +1,28 @@
+package algo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestWeighted(t *testing.T) {
+	target := [2]float64{0, 0}
+	algo := Weighted(Box(target, target, false, nil),
+		func(min, max [2]float64, data interface{}) float64 {
+			return data.(float64)
+		}, 4,
+	)
+	item := [2]float64{3, 4}
+	if dist := algo(item, item, 2.0, true); dist != 25.0/2 {
+		t.Fatalf("expected %v, got %v", 25.0/2, dist)
+	}
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{rand.Float64()*20 - 10, rand.Float64()*20 - 10}
+		w := 1 + rand.Float64()*3
+		min := [2]float64{p[0] - rand.Float64(), p[1] - rand.Float64()}
+		max := [2]float64{p[0] + rand.Float64(), p[1] + rand.Float64()}
+		if algo(min, max, nil, false) > algo(p, p, w, true) {
+			t.Fatal("node distance is larger than item distance")
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package geoindex
+
+import "fmt"
+
+// LevelQuality holds the quality metrics for the nodes of one level of the
+// tree, starting with the root nodes at level zero.
+type LevelQuality struct {
+	// Nodes is the number of nodes on the level.
+	Nodes int
+	// Area is the sum of the areas of the nodes.
+	Area float64
+	// Perimeter is the sum of the perimeters of the nodes.
+	Perimeter float64
+	// Coverage is the Area divided by the area of the tree's bounds. A
+	// value larger than one means that the nodes overlap.
+	Coverage float64
+	// Overlap is the sum of the areas where sibling nodes intersect each
+	// other, divided by the Area. Zero means that no siblings overlap.
+	Overlap float64
+	// Fill is the average number of children per node, divided by the
+	// largest number of children of any node in the tree.
+	Fill float64
+}
+
+// Advice is a recommendation from Analyze.
+type Advice int
+
+const (
+	// AdviceOK means that the tree is in good shape.
+	AdviceOK Advice = iota
+	// AdviceConsiderRebuild means that the tree has degraded and that
+	// searches may benefit from rebuilding it.
+	AdviceConsiderRebuild
+	// AdviceRebuild means that the tree has degraded badly and should be
+	// rebuilt.
+	AdviceRebuild
+)
+
+func (a Advice) String() string {
+	switch a {
+	case AdviceOK:
+		return "ok"
+	case AdviceConsiderRebuild:
+		return "consider rebuild"
+	case AdviceRebuild:
+		return "rebuild strongly advised"
+	}
+	return fmt.Sprintf("Advice(%d)", int(a))
+}
+
+// Thresholds are the limits that Analysis.Advise uses to make a
+// recommendation. The overlap thresholds are compared against the largest
+// Overlap of any level, and the fill thresholds against the smallest Fill of
+// any level that has more than one node.
+type Thresholds struct {
+	ConsiderOverlap float64
+	RebuildOverlap  float64
+	ConsiderFill    float64
+	RebuildFill     float64
+}
+
+// DefaultThresholds are the thresholds used by Analyze. The siblings of a
+// freshly built tree of random boxes overlap by up to about 0.3 of their
+// area, and its levels are at least about 0.6 full. A rebuild is considered
+// when the siblings of a level overlap by more than half of their area or
+// when a level is less than half full, and it's strongly advised when they
+// overlap by more than their area or when a level is less than a quarter
+// full.
+var DefaultThresholds = Thresholds{
+	ConsiderOverlap: 0.5,
+	RebuildOverlap:  1.0,
+	ConsiderFill:    0.5,
+	RebuildFill:     0.25,
+}
+
+// Analysis is the result of Analyze.
+type Analysis struct {
+	Levels []LevelQuality
+	// Advice is the recommendation using the DefaultThresholds.
+	Advice Advice
+}
+
+// Advise returns a recommendation for the analysis using the thresholds.
+func (a Analysis) Advise(th Thresholds) Advice {
+	advice := AdviceOK
+	for _, l := range a.Levels {
+		fill := l.Fill
+		if l.Nodes == 1 {
+			// a single node, such as the root, may have any number of
+			// children
+			fill = 1
+		}
+		if l.Overlap > th.RebuildOverlap || fill < th.RebuildFill {
+			return AdviceRebuild
+		}
+		if l.Overlap > th.ConsiderOverlap || fill < th.ConsiderFill {
+			advice = AdviceConsiderRebuild
+		}
+	}
+	return advice
+}
+
+func boxPerimeter(min, max [2]float64) float64 {
+	return 2 * ((max[0] - min[0]) + (max[1] - min[1]))
+}
+
+func overlapArea(aMin, aMax, bMin, bMax [2]float64) float64 {
+	area := 1.0
+	for i := 0; i < 2; i++ {
+		lo, hi := aMin[i], aMax[i]
+		if bMin[i] > lo {
+			lo = bMin[i]
+		}
+		if bMax[i] < hi {
+			hi = bMax[i]
+		}
+		if hi <= lo {
+			return 0
+		}
+		area *= hi - lo
+	}
+	return area
+}
+
+// Analyze computes the standard R-tree quality metrics for each level of the
+// tree, using the Children of the wrapped tree, and makes a recommendation
+// about rebuilding the tree using the DefaultThresholds.
+func (index *Index) Analyze() Analysis {
+	var a Analysis
+	var overlaps []float64
+	var maxChildren int
+	fill := index.Fill()
+	for _, l := range fill {
+		if l.Max > maxChildren {
+			maxChildren = l.Max
+		}
+	}
+	var visit func(parent interface{}, level int)
+	visit = func(parent interface{}, level int) {
+		children := index.tree.Children(parent, nil)
+		for i, c := range children {
+			if c.Item {
+				continue
+			}
+			for len(a.Levels) <= level {
+				a.Levels = append(a.Levels, LevelQuality{})
+				overlaps = append(overlaps, 0)
+			}
+			l := &a.Levels[level]
+			l.Nodes++
+			l.Area += boxArea(c)
+			l.Perimeter += boxPerimeter(c.Min, c.Max)
+			for _, s := range children[i+1:] {
+				if !s.Item {
+					overlaps[level] += overlapArea(c.Min, c.Max, s.Min, s.Max)
+				}
+			}
+			visit(c.Data, level+1)
+		}
+	}
+	visit(nil, 0)
+	bmin, bmax := index.tree.Bounds()
+	barea := (bmax[0] - bmin[0]) * (bmax[1] - bmin[1])
+	for i := range a.Levels {
+		l := &a.Levels[i]
+		if barea > 0 {
+			l.Coverage = l.Area / barea
+		}
+		if l.Area > 0 {
+			l.Overlap = overlaps[i] / l.Area
+		}
+		if maxChildren > 0 && i < len(fill) {
+			l.Fill = fill[i].Mean / float64(maxChildren)
+		}
+	}
+	a.Advice = a.Advise(DefaultThresholds)
+	return a
+}
@@ -0,0 +1,64 @@
+package geoindex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestAnalyze(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	if a := index.Analyze(); len(a.Levels) != 0 || a.Advice != AdviceOK {
+		t.Fatalf("unexpected analysis %+v", a)
+	}
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	a := index.Analyze()
+	fill := index.Fill()
+	if len(a.Levels) != len(fill) {
+		t.Fatalf("expected %d, got %d", len(fill), len(a.Levels))
+	}
+	for i, l := range a.Levels {
+		if l.Nodes != fill[i].Nodes || l.Area <= 0 || l.Perimeter <= 0 ||
+			l.Coverage <= 0 || l.Overlap < 0 || l.Fill <= 0 || l.Fill > 1 {
+			t.Fatalf("level %d: unexpected %+v", i, l)
+		}
+	}
+	if a.Levels[0].Overlap != 0 || a.Levels[0].Coverage != 1 {
+		t.Fatalf("unexpected root level %+v", a.Levels[0])
+	}
+	if a.Advice != AdviceOK {
+		t.Fatalf("expected %s, got %s: %+v", AdviceOK, a.Advice, a.Levels)
+	}
+	points := Wrap(&internal.RTree{})
+	for i, box := range randPoints(10000) {
+		points.Insert(box.min, box.max, i)
+	}
+	if a := points.Analyze(); a.Advice != AdviceOK {
+		t.Fatalf("expected %s, got %s: %+v", AdviceOK, a.Advice, a.Levels)
+	}
+	strict := Thresholds{ConsiderOverlap: -1, RebuildOverlap: 1e9,
+		ConsiderFill: 0, RebuildFill: 0}
+	if advice := a.Advise(strict); advice != AdviceConsiderRebuild {
+		t.Fatalf("expected %s, got %s", AdviceConsiderRebuild, advice)
+	}
+	strict.RebuildFill = 2
+	if advice := a.Advise(strict); advice != AdviceRebuild {
+		t.Fatalf("expected %s, got %s", AdviceRebuild, advice)
+	}
+
+	// huge overlapping items cause the nodes to overlap
+	index = Wrap(&internal.RTree{})
+	for i := 0; i < 10000; i++ {
+		min := [2]float64{rand.Float64() * 100, rand.Float64() * 100}
+		max := [2]float64{min[0] + 100, min[1] + 100}
+		index.Insert(min, max, i)
+	}
+	if a := index.Analyze(); a.Advice != AdviceRebuild {
+		t.Fatalf("expected %s, got %s: %+v", AdviceRebuild, a.Advice,
+			a.Levels)
+	}
+}
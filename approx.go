@@ -0,0 +1,81 @@
+package geoindex
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// NearbyApprox performs an approximate kNN-type operation on the index using
+// a beam search. No more than `beam` nodes are queued for each level of the
+// tree, and when a level is full the nodes furthest from the target are
+// dropped. Items are never dropped. This trades accuracy for speed on large
+// trees with heavily overlapping nodes, where an exact Nearby may visit many
+// nodes before returning its first items.
+//
+// The returned bound is the smallest distance of all nodes that were dropped,
+// or +Inf when nothing was dropped. All items with a dist smaller than bound
+// are the exact results, in order, and items at a larger dist may be missing.
+// See Nearby for a description of the `algo` and `iter` functions. A beam of
+// zero or less is not limited.
+func (index *Index) NearbyApprox(
+	beam int,
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) (bound float64) {
+	bound = math.Inf(+1)
+	var q queue
+	var queued []int // number of nodes queued for each level
+	var children []child.Child
+	var batch []qnode
+	push := func(depth int) {
+		batch = batch[:0]
+		for _, child := range children {
+			batch = append(batch, qnode{
+				dist:  algo(child.Min, child.Max, child.Data, child.Item),
+				aux:   depth,
+				child: child,
+			})
+		}
+		for len(queued) <= depth {
+			queued = append(queued, 0)
+		}
+		if beam > 0 {
+			sort.SliceStable(batch, func(i, j int) bool {
+				return batch[i].dist < batch[j].dist
+			})
+		}
+		for _, node := range batch {
+			if !node.child.Item && beam > 0 {
+				if queued[depth] >= beam {
+					if node.dist < bound {
+						bound = node.dist
+					}
+					continue
+				}
+				queued[depth]++
+			}
+			q.push(node)
+		}
+	}
+	children = index.tree.Children(nil, children[:0])
+	push(0)
+	for {
+		node, ok := q.pop()
+		if !ok {
+			// nothing left in queue
+			return bound
+		}
+		if node.child.Item {
+			if !iter(node.child.Min, node.child.Max, node.child.Data,
+				node.dist) {
+				return bound
+			}
+			continue
+		}
+		// gather more children
+		children = index.tree.Children(node.child.Data, children[:0])
+		push(node.aux.(int) + 1)
+	}
+}
@@ -0,0 +1,50 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbyApprox(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	target := [2]float64{10, 10}
+	var expect []float64
+	index.Nearby(algo.Box(target, target, false, nil),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			expect = append(expect, dist)
+			return true
+		},
+	)
+	for _, beam := range []int{0, 1, 2, 8, 1000} {
+		var got []float64
+		bound := index.NearbyApprox(beam, algo.Box(target, target, false, nil),
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				got = append(got, dist)
+				return true
+			},
+		)
+		if beam == 0 || beam == 1000 {
+			if !math.IsInf(bound, +1) || len(got) != len(expect) {
+				t.Fatalf("beam %d: expected exact results", beam)
+			}
+		} else if math.IsInf(bound, +1) || len(got) >= len(expect) {
+			t.Fatalf("beam %d: expected approximate results", beam)
+		}
+		for i := range got {
+			if got[i] >= bound {
+				break
+			}
+			if got[i] != expect[i] {
+				t.Fatalf("beam %d: result %d: expected %v, got %v",
+					beam, i, expect[i], got[i])
+			}
+		}
+	}
+}
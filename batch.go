@@ -0,0 +1,128 @@
+package geoindex
+
+import (
+	"sort"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/pqueue"
+)
+
+// batchSize is the number of targets that NearbyBatch searches for together
+const batchSize = 64
+
+// NearbyBatch returns the k nearest items for each of the targets, from the
+// smallest dist to the largest dist, where the dist is the same as Nearby
+// with algo.Box(target, target, false, nil). The results are in the same
+// order as the targets.
+// The targets are ordered along a Hilbert curve and then searched for in
+// batches of nearby targets, each with a single walk of the tree, so that a
+// node is visited once for all the targets of a batch, rather than once for
+// each target. This is much faster than a separate Nearby for each target,
+// such as for batch geocoding. Like NearbyAux, this function always uses the
+// Children of the wrapped tree.
+func (index *Index) NearbyBatch(targets [][2]float64, k int) [][]Result {
+	results := make([][]Result, len(targets))
+	if k < 1 || len(targets) == 0 {
+		return results
+	}
+	defer index.stats.end(opNearby, index.stats.begin())
+	order := make([]int, len(targets))
+	values := make([]uint64, len(targets))
+	for i, target := range targets {
+		order[i] = i
+		values[i] = hilbertBox(target, target)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+	b := &nearbyBatch{index: index, k: k}
+	for len(order) > 0 {
+		n := batchSize
+		if n > len(order) {
+			n = len(order)
+		}
+		b.targets = b.targets[:0]
+		b.best = b.best[:0]
+		active := make([]int, n)
+		for i, j := range order[:n] {
+			b.targets = append(b.targets, targets[j])
+			b.best = append(b.best, pqueue.Queue[Result]{})
+			active[i] = i
+		}
+		b.visit(index.tree.Children(nil, nil), active)
+		for i, j := range order[:n] {
+			results[j] = b.results(i)
+		}
+		order = order[n:]
+	}
+	return results
+}
+
+type nearbyBatch struct {
+	index   *Index
+	k       int
+	targets [][2]float64
+	// best holds the k nearest items of each target, where the priority is
+	// the negated distance, so the kth distance is at the top
+	best []pqueue.Queue[Result]
+}
+
+// accepts returns true when an item at the distance may be one of the k
+// nearest items of the target
+func (b *nearbyBatch) accepts(i int, dist float64) bool {
+	if b.best[i].Len() < b.k {
+		return true
+	}
+	_, kth, _ := b.best[i].Peek()
+	return dist < -kth
+}
+
+func (b *nearbyBatch) visit(children []child.Child, active []int) {
+	// Visit the nearest nodes first, by the distance to the center of the
+	// targets, which finds the nearest items sooner and prunes more nodes.
+	var center [2]float64
+	for _, i := range active {
+		center[0] += b.targets[i][0] / float64(len(active))
+		center[1] += b.targets[i][1] / float64(len(active))
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return algo.BoxDistCalc(center, center, children[i].Min,
+			children[i].Max, false) < algo.BoxDistCalc(center, center,
+			children[j].Min, children[j].Max, false)
+	})
+	var sub []int
+	for _, c := range children {
+		sub = sub[:0]
+		for _, i := range active {
+			t := b.targets[i]
+			dist := algo.BoxDistCalc(t, t, c.Min, c.Max, false)
+			if !b.accepts(i, dist) {
+				continue
+			}
+			if c.Item {
+				b.best[i].Push(Result{c.Min, c.Max, c.Data, dist}, -dist)
+				if b.best[i].Len() > b.k {
+					b.best[i].Pop()
+				}
+			} else {
+				sub = append(sub, i)
+			}
+		}
+		if len(sub) > 0 {
+			buf := getChildren()
+			*buf = b.index.tree.Children(c.Data, *buf)
+			b.visit(*buf, append([]int(nil), sub...))
+			putChildren(buf)
+		}
+	}
+}
+
+// results returns the nearest items of a target, in order
+func (b *nearbyBatch) results(i int) []Result {
+	results := make([]Result, b.best[i].Len())
+	for j := len(results) - 1; j >= 0; j-- {
+		results[j], _, _ = b.best[i].Pop()
+	}
+	return results
+}
@@ -0,0 +1,79 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbyBatch(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, item := range GenClustered(10000, 20, 5) {
+		index.Insert(item.Min, item.Max, i)
+	}
+	var targets [][2]float64
+	for _, item := range GenClustered(500, 20, 5) {
+		targets = append(targets, item.Min)
+	}
+	// a target that's far from the clusters
+	targets = append(targets, [2]float64{0, 89})
+	for _, k := range []int{1, 10, 100} {
+		results := index.NearbyBatch(targets, k)
+		if len(results) != len(targets) {
+			t.Fatalf("expected %d, got %d", len(targets), len(results))
+		}
+		for i, p := range targets {
+			var expect []Result
+			index.NearbyK(k, algo.Box(p, p, false, nil),
+				func(min, max [2]float64, data interface{}, dist float64) bool {
+					expect = append(expect, Result{min, max, data, dist})
+					return true
+				},
+			)
+			if len(results[i]) != len(expect) {
+				t.Fatalf("expected %d, got %d", len(expect), len(results[i]))
+			}
+			for j := range expect {
+				if results[i][j].Dist != expect[j].Dist {
+					t.Fatalf("%v: expected %v, got %v at %d", p,
+						expect[j].Dist, results[i][j].Dist, j)
+				}
+			}
+		}
+	}
+	if results := index.NearbyBatch(targets, 0); len(results) != len(targets) ||
+		results[0] != nil {
+		t.Fatal("expected empty results")
+	}
+}
+
+func BenchmarkNearbyBatch(b *testing.B) {
+	index := Wrap(&internal.RTree{})
+	for i, item := range GenCities(100000) {
+		index.Insert(item.Min, item.Max, i)
+	}
+	var targets [][2]float64
+	for _, item := range GenCities(10000) {
+		targets = append(targets, item.Min)
+	}
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			index.NearbyBatch(targets, 10)
+		}
+	})
+	b.Run("NearbyK", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, p := range targets {
+				var n int
+				index.NearbyK(10, algo.Box(p, p, false, nil),
+					func(min, max [2]float64, data interface{},
+						dist float64) bool {
+						n++
+						return true
+					},
+				)
+			}
+		}
+	})
+}
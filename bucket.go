@@ -0,0 +1,140 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// bucket holds all values that have the same box
+type bucket struct {
+	min, max [2]float64
+	values   []interface{}
+}
+
+// BucketTree is an Interface that coalesces items with exactly the same
+// box into a single item of the wrapped tree, which holds a bucket of values.
+// This prevents the tree from degenerating when large numbers of items share
+// a location, such as thousands of records at the same point.
+//
+// The Children of a BucketTree are the Children of the wrapped tree with the
+// buckets expanded into their values, so Nearby and the other Index
+// operations work as usual.
+type BucketTree struct {
+	tree    Interface
+	buckets map[[2][2]float64]*bucket
+	count   int
+}
+
+// NewBucketTree returns a BucketTree for an empty tree.
+func NewBucketTree(tree Interface) *BucketTree {
+	return &BucketTree{
+		tree:    tree,
+		buckets: make(map[[2][2]float64]*bucket),
+	}
+}
+
+// Insert an item into the tree
+func (tr *BucketTree) Insert(min, max [2]float64, data interface{}) {
+	key := [2][2]float64{min, max}
+	b := tr.buckets[key]
+	if b == nil {
+		b = &bucket{min: min, max: max}
+		tr.buckets[key] = b
+		tr.tree.Insert(min, max, b)
+	}
+	b.values = append(b.values, data)
+	tr.count++
+}
+
+// Delete an item from the tree
+func (tr *BucketTree) Delete(min, max [2]float64, data interface{}) {
+	key := [2][2]float64{min, max}
+	b := tr.buckets[key]
+	if b == nil {
+		return
+	}
+	for i, value := range b.values {
+		if value == data {
+			b.values[i] = b.values[len(b.values)-1]
+			b.values[len(b.values)-1] = nil
+			b.values = b.values[:len(b.values)-1]
+			tr.count--
+			if len(b.values) == 0 {
+				delete(tr.buckets, key)
+				tr.tree.Delete(min, max, b)
+			}
+			return
+		}
+	}
+}
+
+// Replace an item in the tree
+func (tr *BucketTree) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	tr.Delete(oldMin, oldMax, oldData)
+	tr.Insert(newMin, newMax, newData)
+}
+
+// Search the tree for items that intersects the rect param
+func (tr *BucketTree) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	tr.tree.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		for _, value := range data.(*bucket).values {
+			if !iter(min, max, value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Scan iterates through all data in tree in no specified order.
+func (tr *BucketTree) Scan(
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	for _, b := range tr.buckets {
+		for _, value := range b.values {
+			if !iter(b.min, b.max, value) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of items in tree
+func (tr *BucketTree) Len() int {
+	return tr.count
+}
+
+// Buckets returns the number of distinct boxes in the tree, which is the
+// number of items in the wrapped tree.
+func (tr *BucketTree) Buckets() int {
+	return len(tr.buckets)
+}
+
+// Bounds returns the minimum bounding box
+func (tr *BucketTree) Bounds() (min, max [2]float64) {
+	return tr.tree.Bounds()
+}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root nodes should be returned.
+func (tr *BucketTree) Children(parent interface{}, reuse []child.Child) (
+	children []child.Child,
+) {
+	children = tr.tree.Children(parent, reuse)
+	for i, n := 0, len(children); i < n; i++ {
+		if !children[i].Item {
+			continue
+		}
+		b := children[i].Data.(*bucket)
+		children[i].Data = b.values[0]
+		for _, value := range b.values[1:] {
+			children = append(children, child.Child{
+				Min: b.min, Max: b.max, Data: value, Item: true,
+			})
+		}
+	}
+	return children
+}
@@ -0,0 +1,73 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestBucketTree(t *testing.T) {
+	t.Run("RandomPoints", func(t *testing.T) {
+		Tests.TestRandomPoints(t, NewBucketTree(&internal.RTree{}), 10000)
+	})
+	t.Run("RandomRects", func(t *testing.T) {
+		Tests.TestRandomRects(t, NewBucketTree(&internal.RTree{}), 10000)
+	})
+	t.Run("ZeroPoints", func(t *testing.T) {
+		tr := NewBucketTree(&internal.RTree{})
+		Tests.TestZeroPoints(t, tr)
+		if tr.Len() != 10000 || tr.Buckets() != 1 || tr.tree.Len() != 1 {
+			t.Fatalf("expected one bucket, got %d", tr.Buckets())
+		}
+	})
+	t.Run("Duplicates", func(t *testing.T) {
+		tr := NewBucketTree(&internal.RTree{})
+		index := Wrap(tr)
+		var pts [][2]float64
+		for i := 0; i < 10; i++ {
+			pts = append(pts, [2]float64{float64(i), float64(i)})
+		}
+		for i := 0; i < 1000; i++ {
+			tr.Insert(pts[i%10], pts[i%10], i)
+		}
+		if tr.Len() != 1000 || tr.Buckets() != 10 {
+			t.Fatalf("expected %d/%d, got %d/%d",
+				1000, 10, tr.Len(), tr.Buckets())
+		}
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		var count int
+		index.Nearby(
+			func(min, max [2]float64, data interface{}, item bool) float64 {
+				return min[0]
+			},
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				if data.(int)%10 != count/100 {
+					t.Fatalf("unexpected item %v at %d", data, count)
+				}
+				count++
+				return true
+			},
+		)
+		if count != 1000 {
+			t.Fatalf("expected %d, got %d", 1000, count)
+		}
+		for i := 0; i < 1000; i++ {
+			if i%10 != 3 {
+				tr.Delete(pts[i%10], pts[i%10], i)
+			}
+		}
+		if tr.Len() != 100 || tr.Buckets() != 1 {
+			t.Fatalf("expected %d/%d, got %d/%d",
+				100, 1, tr.Len(), tr.Buckets())
+		}
+		tr.Delete(pts[3], pts[3], -1)
+		if tr.Len() != 100 {
+			t.Fatalf("expected %d, got %d", 100, tr.Len())
+		}
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
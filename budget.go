@@ -0,0 +1,90 @@
+package geoindex
+
+import (
+	"math"
+	"time"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// Budget is a limit on the work of a NearbyBudget operation. The zero value
+// of a field is no limit.
+type Budget struct {
+	// MaxNodes is the number of nodes whose children are visited.
+	MaxNodes int
+	// MaxItems is the number of items whose distance is calculated.
+	MaxItems int
+	// MaxDuration is the amount of time that the operation runs.
+	MaxDuration time.Duration
+}
+
+// NearbyBudget performs a kNN-type operation on the index, like Nearby, that
+// stops when the budget is exhausted, such as for a service that prefers a
+// partial answer over a slow answer. Returns true when the results are
+// complete, which is when all items have been returned or when iter returned
+// false, or false when the operation was stopped by the budget.
+// The items that are passed to iter are always the nearest items, in order.
+// Once the budget is exhausted, the items that are already known to be
+// nearer than any part of the tree that has not been visited are still
+// returned, and only the items that are further away are left out.
+// See Nearby for a description of the `algo` and `iter` functions. Like
+// NearbyAux, this function always uses the Children of the wrapped tree.
+func (index *Index) NearbyBudget(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+	budget Budget,
+) (complete bool) {
+	defer index.stats.end(opNearby, index.stats.begin())
+	var deadline time.Time
+	if budget.MaxDuration > 0 {
+		deadline = time.Now().Add(budget.MaxDuration)
+	}
+	buf := getQueue()
+	defer putQueue(buf)
+	q := &buf.q
+	var nodes, items int
+	exhausted := false
+	parent := math.Inf(-1) // the dist of the node of the children
+	children := index.tree.Children(nil, buf.children[:0])
+	defer func() { buf.children = children }()
+	for {
+		for _, c := range children {
+			if c.Item && budget.MaxItems > 0 && items == budget.MaxItems {
+				// The rest of the children are no nearer than their node,
+				// which is queued again in their place.
+				exhausted = true
+				q.push(qnode{dist: parent, child: child.Child{}})
+				break
+			}
+			if c.Item {
+				items++
+			}
+			dist := algo(c.Min, c.Max, c.Data, c.Item)
+			q.push(qnode{dist: dist, child: c})
+		}
+		children = children[:0]
+		for len(children) == 0 {
+			node, ok := q.pop()
+			if !ok {
+				// nothing left in queue
+				return !exhausted
+			}
+			if node.child.Item {
+				if !iter(node.child.Min, node.child.Max, node.child.Data,
+					node.dist) {
+					return true
+				}
+				continue
+			}
+			if exhausted || (budget.MaxNodes > 0 && nodes == budget.MaxNodes) ||
+				(!deadline.IsZero() && !time.Now().Before(deadline)) {
+				// the rest of the tree is no nearer than this node
+				return false
+			}
+			nodes++
+			parent = node.dist
+			// gather more children
+			children = index.tree.Children(node.child.Data, children)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package geoindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbyBudget(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(10000) {
+		index.Insert(box.min, box.max, i)
+	}
+	p := randPoints(1)[0].min
+	algo := algo.Box(p, p, false, nil)
+	expect := index.NearbyAll(algo, nil)
+	for _, budget := range []Budget{
+		{},
+		{MaxNodes: 1},
+		{MaxNodes: 10},
+		{MaxItems: 1},
+		{MaxItems: 100},
+		{MaxItems: 5000, MaxNodes: 100},
+		{MaxDuration: time.Nanosecond},
+		{MaxDuration: time.Hour},
+	} {
+		var results []Result
+		complete := index.NearbyBudget(algo,
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				results = append(results, Result{min, max, data, dist})
+				return true
+			}, budget,
+		)
+		if complete != (len(results) == len(expect)) {
+			t.Fatalf("%+v: expected complete %v, got %v", budget,
+				len(results) == len(expect), complete)
+		}
+		// the partial results are always the nearest items
+		for i := range results {
+			if results[i].Dist != expect[i].Dist {
+				t.Fatalf("%+v: expected %v, got %v at %d", budget,
+					expect[i].Dist, results[i].Dist, i)
+			}
+		}
+		if budget.MaxItems > 0 && len(results) > budget.MaxItems {
+			t.Fatalf("%+v: expected at most %d items, got %d", budget,
+				budget.MaxItems, len(results))
+		}
+	}
+	// stopping is complete
+	var n int
+	if !index.NearbyBudget(algo,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			n++
+			return n < 10
+		}, Budget{MaxItems: 10000},
+	) || n != 10 {
+		t.Fatalf("expected complete with %d items, got %d", 10, n)
+	}
+}
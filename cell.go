@@ -0,0 +1,53 @@
+package geoindex
+
+// Cell is a cell of a region covering, such as an S2 cell from the covering
+// of a region, which is used by SearchCellUnion. The s2index package has a
+// Cell for the s2.CellID type, so this package doesn't depend on S2.
+type Cell interface {
+	// Bounds returns the lon,lat bounding box of the cell. The box has a
+	// min longitude that's greater than its max longitude when the cell
+	// crosses the antimeridian, in the way of SearchWrapped.
+	Bounds() (min, max [2]float64)
+	// Intersects returns true when the cell contains any part of the lon,lat
+	// box, or contains the point when min and max are the same.
+	Intersects(min, max [2]float64) bool
+}
+
+// SearchCellUnion searches the index for items that intersect any of the
+// cells, such as the covering of a region. Each cell is searched by its
+// bounds, and the items that are found are checked with the Intersects of
+// the cell. The items that intersect many cells are only returned once.
+func (index *Index) SearchCellUnion(
+	cells []Cell,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	bounds := make([][2][2]float64, len(cells))
+	for i, cell := range cells {
+		bounds[i][0], bounds[i][1] = cell.Bounds()
+	}
+	done := false
+	for i, cell := range cells {
+		index.SearchWrapped(bounds[i][0], bounds[i][1],
+			func(min, max [2]float64, data interface{}) bool {
+				if !cell.Intersects(min, max) {
+					return true
+				}
+				for j := 0; j < i; j++ {
+					if wrappedIntersects(bounds[j][0], bounds[j][1],
+						min, max) && cells[j].Intersects(min, max) {
+						// already returned for an earlier cell
+						return true
+					}
+				}
+				if !iter(min, max, data) {
+					done = true
+					return false
+				}
+				return true
+			},
+		)
+		if done {
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// diskCell is a cell that only contains the points within a radius of its
+// center, which is smaller than its bounds.
+type diskCell struct {
+	center [2]float64
+	radius float64
+}
+
+func (c diskCell) Bounds() (min, max [2]float64) {
+	return [2]float64{c.center[0] - c.radius, c.center[1] - c.radius},
+		[2]float64{c.center[0] + c.radius, c.center[1] + c.radius}
+}
+
+func (c diskCell) Intersects(min, max [2]float64) bool {
+	dx := c.center[0] - (min[0]+max[0])/2
+	dy := c.center[1] - (min[1]+max[1])/2
+	return dx*dx+dy*dy <= c.radius*c.radius
+}
+
+// wrapCell is a box cell that may cross the antimeridian
+type wrapCell [2][2]float64
+
+func (c wrapCell) Bounds() (min, max [2]float64) { return c[0], c[1] }
+
+func (c wrapCell) Intersects(min, max [2]float64) bool {
+	return wrappedIntersects(c[0], c[1], min, max)
+}
+
+func TestSearchCellUnion(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randPoints(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	cells := []Cell{
+		diskCell{[2]float64{0, 0}, 20},
+		diskCell{[2]float64{10, 10}, 20}, // overlaps the first cell
+		diskCell{[2]float64{-100, 50}, 10},
+		wrapCell{{170, -10}, {-170, 10}},
+	}
+	expect := make(map[interface{}]bool)
+	for i, box := range boxes {
+		for _, cell := range cells {
+			if cell.Intersects(box.min, box.max) {
+				expect[i] = true
+			}
+		}
+	}
+	seen := make(map[interface{}]bool)
+	index.SearchCellUnion(cells,
+		func(min, max [2]float64, data interface{}) bool {
+			if seen[data] {
+				t.Fatalf("duplicate item %v", data)
+			}
+			if !expect[data] {
+				t.Fatalf("unexpected item %v %v", min, data)
+			}
+			seen[data] = true
+			return true
+		},
+	)
+	if len(seen) != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), len(seen))
+	}
+	var count int
+	index.SearchCellUnion(cells,
+		func(min, max [2]float64, data interface{}) bool {
+			count++
+			return count < 10
+		},
+	)
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+}
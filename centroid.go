@@ -0,0 +1,76 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// NodeAggregator is an optional interface for a tree that keeps aggregates
+// for its nodes. When a tree implements it, operations such as Centroid use
+// the aggregates of a node rather than visiting all of its items.
+type NodeAggregator interface {
+	// NodeAggregate returns the number of items under a node and the sum of
+	// the centers of their boxes. The node is the Data of a non-item child.
+	NodeAggregate(node interface{}) (count int, sum [2]float64)
+}
+
+// Centroid returns the mean position of all items in the index, where the
+// position of an item is the center of its box. The mean is calculated in
+// the coordinate space of the boxes. When weight is provided (not nil) each
+// item is weighted by its result, otherwise all items have a weight of one
+// and the aggregates of the nodes are used when the tree is a NodeAggregator.
+// Returns false when there are no items or the total weight is zero.
+func (index *Index) Centroid(
+	weight func(min, max [2]float64, data interface{}) float64,
+) (center [2]float64, ok bool) {
+	inf := math.Inf(1)
+	return index.CentroidRect(
+		[2]float64{-inf, -inf}, [2]float64{inf, inf}, weight)
+}
+
+// CentroidRect is like Centroid but only includes the items that intersect
+// the rect param.
+func (index *Index) CentroidRect(
+	min, max [2]float64,
+	weight func(min, max [2]float64, data interface{}) float64,
+) (center [2]float64, ok bool) {
+	agg, _ := index.tree.(NodeAggregator)
+	if weight != nil {
+		agg = nil
+	}
+	var sum [2]float64
+	var total float64
+	add := func(c child.Child) {
+		w := 1.0
+		if weight != nil {
+			w = weight(c.Min, c.Max, c.Data)
+		}
+		sum[0] += (c.Min[0] + c.Max[0]) / 2 * w
+		sum[1] += (c.Min[1] + c.Max[1]) / 2 * w
+		total += w
+	}
+	var walk func(parent interface{})
+	walk = func(parent interface{}) {
+		for _, c := range index.tree.Children(parent, nil) {
+			if !intersects(min, max, c.Min, c.Max) {
+				continue
+			}
+			if c.Item {
+				add(c)
+			} else if agg != nil && contains(min, max, c.Min, c.Max) {
+				count, nsum := agg.NodeAggregate(c.Data)
+				sum[0] += nsum[0]
+				sum[1] += nsum[1]
+				total += float64(count)
+			} else {
+				walk(c.Data)
+			}
+		}
+	}
+	walk(nil)
+	if total == 0 {
+		return center, false
+	}
+	return [2]float64{sum[0] / total, sum[1] / total}, true
+}
@@ -0,0 +1,97 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// aggTree is an RTree that calculates its node aggregates on demand
+type aggTree struct {
+	*internal.RTree
+	calls int
+}
+
+func (tr *aggTree) NodeAggregate(node interface{}) (count int,
+	sum [2]float64,
+) {
+	tr.calls++
+	var walk func(parent interface{})
+	walk = func(parent interface{}) {
+		for _, c := range tr.Children(parent, nil) {
+			if c.Item {
+				count++
+				sum[0] += (c.Min[0] + c.Max[0]) / 2
+				sum[1] += (c.Min[1] + c.Max[1]) / 2
+			} else {
+				walk(c.Data)
+			}
+		}
+	}
+	walk(node)
+	return count, sum
+}
+
+var _ NodeAggregator = &aggTree{}
+
+func TestCentroid(t *testing.T) {
+	tr := &aggTree{RTree: &internal.RTree{}}
+	index := Wrap(tr)
+	if _, ok := index.Centroid(nil); ok {
+		t.Fatal("expected false")
+	}
+	boxes := randBoxes(10000)
+	var sum, wsum [2]float64
+	var wtotal float64
+	min, max := [2]float64{-90, -45}, [2]float64{0, 45}
+	var rsum [2]float64
+	var rcount int
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+		c := [2]float64{(box.min[0] + box.max[0]) / 2,
+			(box.min[1] + box.max[1]) / 2}
+		sum[0] += c[0]
+		sum[1] += c[1]
+		w := float64(i % 10)
+		wsum[0] += c[0] * w
+		wsum[1] += c[1] * w
+		wtotal += w
+		if intersects(min, max, box.min, box.max) {
+			rsum[0] += c[0]
+			rsum[1] += c[1]
+			rcount++
+		}
+	}
+	near := func(a, b [2]float64) bool {
+		return math.Abs(a[0]-b[0]) < 1e-9 && math.Abs(a[1]-b[1]) < 1e-9
+	}
+	n := float64(len(boxes))
+	c, ok := index.Centroid(nil)
+	if !ok || !near(c, [2]float64{sum[0] / n, sum[1] / n}) {
+		t.Fatalf("unexpected centroid %v", c)
+	}
+	if tr.calls == 0 {
+		t.Fatal("expected the node aggregates to be used")
+	}
+	calls := tr.calls
+	c, ok = index.Centroid(
+		func(min, max [2]float64, data interface{}) float64 {
+			return float64(data.(int) % 10)
+		},
+	)
+	if !ok || !near(c, [2]float64{wsum[0] / wtotal, wsum[1] / wtotal}) {
+		t.Fatalf("unexpected weighted centroid %v", c)
+	}
+	if tr.calls != calls {
+		t.Fatal("expected the node aggregates to not be used")
+	}
+	c, ok = index.CentroidRect(min, max, nil)
+	if !ok || !near(c, [2]float64{rsum[0] / float64(rcount),
+		rsum[1] / float64(rcount)}) {
+		t.Fatalf("unexpected rect centroid %v", c)
+	}
+	if _, ok := Wrap(&internal.RTree{}).CentroidRect(min, max, nil); ok {
+		t.Fatal("expected false")
+	}
+}
@@ -9,3 +9,11 @@ type Child struct {
 	Data     interface{}
 	Item     bool
 }
+
+// Child3 represents a child of a 3d geospatial tree, such as one that has
+// altitude or time as the third dimension. The fields are the same as Child.
+type Child3 struct {
+	Min, Max [3]float64
+	Data     interface{}
+	Item     bool
+}
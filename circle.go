@@ -0,0 +1,42 @@
+package geoindex
+
+// CircleBounds returns the lon,lat bounding box of the great-circle cap with
+// a radius in meters around the lon,lat center. A box that crosses the
+// antimeridian has a min longitude that's greater than its max longitude, in
+// the way of SearchWrapped. A cap that covers a pole has the full range of
+// longitudes, up to the pole.
+func CircleBounds(center [2]float64, radiusMeters float64) (
+	min, max [2]float64,
+) {
+	rects := degreesCRS{}.Circle(center, radiusMeters)
+	if len(rects) == 1 {
+		return rects[0][0], rects[0][1]
+	}
+	// the circle is split at the antimeridian, so join the halves into a
+	// wrapped box
+	min, max = rects[0][0], rects[0][1]
+	for _, rect := range rects {
+		if rect[1][0] == 180 {
+			min[0] = rect[0][0]
+		} else {
+			max[0] = rect[1][0]
+		}
+	}
+	return min, max
+}
+
+// SearchCircle searches the index for items that are within a great-circle
+// distance in meters of the lon,lat center, including across the antimeridian
+// and around the poles. This is the same as Within without the distances, so
+// when the CRS of the index isn't Degrees, the distance is the Dist of the
+// CRS.
+func (index *Index) SearchCircle(
+	center [2]float64, radiusMeters float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	index.Within(center, radiusMeters,
+		func(min, max [2]float64, data interface{}, _ float64) bool {
+			return iter(min, max, data)
+		},
+	)
+}
@@ -0,0 +1,80 @@
+package geoindex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestCircleBounds(t *testing.T) {
+	// a degree of latitude is about 111 km
+	deg := algo.EarthRadius * math.Pi / 180
+	min, max := CircleBounds([2]float64{10, 0}, deg)
+	if math.Abs(min[1]+1) > 1e-9 || math.Abs(max[1]-1) > 1e-9 ||
+		math.Abs(min[0]-9) > 1e-9 || math.Abs(max[0]-11) > 1e-9 {
+		t.Fatalf("unexpected %v %v", min, max)
+	}
+	// longitudes are wider near the poles
+	min, max = CircleBounds([2]float64{0, 60}, deg)
+	if max[0]-min[0] < 3.9 {
+		t.Fatalf("unexpected %v %v", min, max)
+	}
+	min, max = CircleBounds([2]float64{0, 89.5}, deg)
+	if min != [2]float64{-180, 88.5} || max != [2]float64{180, 90} {
+		t.Fatalf("unexpected %v %v", min, max)
+	}
+	for _, lon := range []float64{179.5, -179.5} {
+		min, max = CircleBounds([2]float64{lon, 0}, deg)
+		if min[0] <= max[0] || math.Abs(min[0]-(lon-1)) > 1e-9 &&
+			math.Abs(min[0]-(lon+359)) > 1e-9 ||
+			math.Abs(max[0]-(lon+1)) > 1e-9 &&
+				math.Abs(max[0]-(lon-359)) > 1e-9 {
+			t.Fatalf("expected a wrapped box, got %v %v", min, max)
+		}
+	}
+	// a cap that's larger than a hemisphere covers a pole
+	min, max = CircleBounds([2]float64{0, 0}, deg*100)
+	if min != [2]float64{-180, -90} || max != [2]float64{180, 90} {
+		t.Fatalf("unexpected %v %v", min, max)
+	}
+}
+
+func TestSearchCircle(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := append(randPoints(5000), randBoxes(5000)...)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	for _, c := range []struct {
+		center [2]float64
+		radius float64
+	}{
+		{[2]float64{0, 0}, 1000000},
+		{[2]float64{179, 10}, 2000000},
+		{[2]float64{-30, 85}, 1500000},
+		{[2]float64{100, -88}, 500000},
+		{[2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}, 3000000},
+		{[2]float64{0, 0}, 30000000},
+	} {
+		seen := make(map[interface{}]bool)
+		index.SearchCircle(c.center, c.radius,
+			func(min, max [2]float64, data interface{}) bool {
+				if seen[data] {
+					t.Fatalf("duplicate item %v", data)
+				}
+				seen[data] = true
+				return true
+			},
+		)
+		for i, box := range boxes {
+			dist := algo.HaversineBox(c.center, box.min, box.max)
+			if (dist <= c.radius) != seen[i] {
+				t.Fatalf("%v %v: %v %v at %v: expected %v", c.center, c.radius,
+					box.min, box.max, dist, !seen[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/pqueue"
+)
+
+type closestPair struct {
+	a, b child.Child
+	self bool // a node with itself
+}
+
+// ClosestPair returns the two items in the index that are nearest to each
+// other, where the dist is the squared box distance, like algo.Box. Items
+// that overlap have a dist of zero. Returns a dist of +Inf when the index has
+// fewer than two items.
+//
+// This is a branch-and-bound traversal of the tree against itself, where the
+// pairs of nodes are visited from the smallest dist to the largest dist, and
+// stops at the first pair of items, so it's usually much faster than
+// comparing all pairs of items.
+func (index *Index) ClosestPair() (a, b Item, dist float64) {
+	defer index.stats.end(opNearby, index.stats.begin())
+	var q pqueue.Queue[closestPair]
+	push := func(pa, pb child.Child, self bool) {
+		q.Push(closestPair{pa, pb, self},
+			algo.BoxDistCalc(pa.Min, pa.Max, pb.Min, pb.Max, false))
+	}
+	// pushSelf pushes all pairs of the children, including a node with
+	// itself, but not an item with itself.
+	pushSelf := func(children []child.Child) {
+		for i := range children {
+			for j := i; j < len(children); j++ {
+				if i != j || !children[i].Item {
+					push(children[i], children[j], i == j)
+				}
+			}
+		}
+	}
+	var children []child.Child
+	pushSelf(index.tree.Children(nil, nil))
+	for {
+		pair, dist, ok := q.Pop()
+		if !ok {
+			return a, b, math.Inf(1)
+		}
+		pa, pb := pair.a, pair.b
+		switch {
+		case pa.Item && pb.Item:
+			return Item{pa.Min, pa.Max, pa.Data},
+				Item{pb.Min, pb.Max, pb.Data}, dist
+		case pair.self:
+			children = index.tree.Children(pa.Data, children[:0])
+			pushSelf(children)
+		case !pa.Item && (pb.Item || boxArea(pa) >= boxArea(pb)):
+			// expand the larger node
+			children = index.tree.Children(pa.Data, children[:0])
+			for _, ca := range children {
+				push(ca, pb, false)
+			}
+		default:
+			children = index.tree.Children(pb.Data, children[:0])
+			for _, cb := range children {
+				push(pa, cb, false)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestClosestPair(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	if _, _, dist := index.ClosestPair(); !math.IsInf(dist, 1) {
+		t.Fatalf("expected +Inf, got %v", dist)
+	}
+	index.Insert([2]float64{1, 1}, [2]float64{1, 1}, 0)
+	if _, _, dist := index.ClosestPair(); !math.IsInf(dist, 1) {
+		t.Fatalf("expected +Inf, got %v", dist)
+	}
+	boxes := randPoints(2000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i+1)
+	}
+	expect := math.Inf(1)
+	all := append([]tBox{{[2]float64{1, 1}, [2]float64{1, 1}}}, boxes...)
+	for i := range all {
+		for j := i + 1; j < len(all); j++ {
+			expect = math.Min(expect, algo.BoxDistCalc(all[i].min,
+				all[i].max, all[j].min, all[j].max, false))
+		}
+	}
+	a, b, dist := index.ClosestPair()
+	if dist != expect {
+		t.Fatalf("expected %v, got %v", expect, dist)
+	}
+	if a.Data == b.Data || dist != algo.BoxDistCalc(a.Min, a.Max, b.Min,
+		b.Max, false) {
+		t.Fatalf("unexpected pair %v %v", a, b)
+	}
+	// the same point twice
+	index.Insert(boxes[5].min, boxes[5].max, -1)
+	if a, b, dist := index.ClosestPair(); dist != 0 || a.Data == b.Data {
+		t.Fatalf("unexpected pair %v %v %v", a, b, dist)
+	}
+}
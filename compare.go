@@ -0,0 +1,178 @@
+package geoindex
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tidwall/geoindex/algo"
+)
+
+var registry struct {
+	mu        sync.Mutex
+	factories map[string]func() Interface
+}
+
+// RegisterImpl registers a named Interface implementation for use with
+// CompareImpls. The factory must return a new empty tree each time it's
+// called. Registering the same name twice will panic.
+func RegisterImpl(name string, factory func() Interface) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.factories == nil {
+		registry.factories = make(map[string]func() Interface)
+	}
+	if _, ok := registry.factories[name]; ok {
+		panic("geoindex: RegisterImpl called twice for " + name)
+	}
+	registry.factories[name] = factory
+}
+
+// Impls returns the names of all registered implementations, in sorted order.
+func Impls() []string {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	var names []string
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Comparison is the result of running one workload against one
+// implementation.
+type Comparison struct {
+	Impl     string
+	Workload string
+	Ops      int
+	Elapsed  time.Duration
+}
+
+// NsPerOp returns the average number of nanoseconds per operation.
+func (c Comparison) NsPerOp() int64 {
+	if c.Ops == 0 {
+		return 0
+	}
+	return int64(c.Elapsed) / int64(c.Ops)
+}
+
+type workload struct {
+	name string
+	run  func(tr Interface, rects []rect) (ops int)
+}
+
+var workloads = []workload{
+	{"insert", func(tr Interface, rects []rect) int {
+		for i, r := range rects {
+			tr.Insert(r.min, r.max, i)
+		}
+		return len(rects)
+	}},
+	{"search-item", func(tr Interface, rects []rect) int {
+		for _, r := range rects {
+			tr.Search(r.min, r.max,
+				func(min, max [2]float64, data interface{}) bool {
+					return true
+				},
+			)
+		}
+		return len(rects)
+	}},
+	{"search-1%", func(tr Interface, rects []rect) int {
+		const p = 0.01
+		for _, r := range rects[:len(rects)/10] {
+			max := [2]float64{r.min[0] + 360*p, r.min[1] + 180*p}
+			tr.Search(r.min, max,
+				func(min, max [2]float64, data interface{}) bool {
+					return true
+				},
+			)
+		}
+		return len(rects) / 10
+	}},
+	{"nearby-10", func(tr Interface, rects []rect) int {
+		index := Wrap(tr)
+		for _, r := range rects[:len(rects)/10] {
+			var n int
+			index.Nearby(algo.Box(r.min, r.min, false, nil),
+				func(min, max [2]float64, data interface{},
+					dist float64,
+				) bool {
+					n++
+					return n < 10
+				},
+			)
+		}
+		return len(rects) / 10
+	}},
+	{"scan", func(tr Interface, rects []rect) int {
+		tr.Scan(func(min, max [2]float64, data interface{}) bool {
+			return true
+		})
+		return 1
+	}},
+	{"delete", func(tr Interface, rects []rect) int {
+		for i, r := range rects {
+			tr.Delete(r.min, r.max, i)
+		}
+		return len(rects)
+	}},
+}
+
+// CompareImpls runs the same workloads with the same data against each of
+// the registered implementations, and writes a matrix of the ns/op for each
+// implementation and workload to w. The w param may be nil.
+// The workloads are: insert, search-item, search-1%, nearby-10, scan, and
+// delete, and they are run in that order using numItems random items.
+func CompareImpls(w io.Writer, numItems int) []Comparison {
+	rng := rand.New(rand.NewSource(int64(numItems)))
+	rects := make([]rect, numItems)
+	for i := range rects {
+		rects[i].min[0] = rng.Float64()*360 - 180
+		rects[i].min[1] = rng.Float64()*180 - 90
+		rects[i].max = rects[i].min
+		if i%2 == 0 {
+			rects[i].max[0] += rng.Float64() * 0.01
+			rects[i].max[1] += rng.Float64() * 0.01
+		}
+	}
+	var results []Comparison
+	names := Impls()
+	for _, name := range names {
+		registry.mu.Lock()
+		tr := registry.factories[name]()
+		registry.mu.Unlock()
+		for _, wl := range workloads {
+			start := time.Now()
+			ops := wl.run(tr, rects)
+			results = append(results, Comparison{
+				Impl:     name,
+				Workload: wl.name,
+				Ops:      ops,
+				Elapsed:  time.Since(start),
+			})
+		}
+	}
+	if w != nil {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintf(tw, "ns/op\t")
+		for _, wl := range workloads {
+			fmt.Fprintf(tw, "%s\t", wl.name)
+		}
+		fmt.Fprintf(tw, "\n")
+		for i, name := range names {
+			fmt.Fprintf(tw, "%s\t", name)
+			for j := range workloads {
+				fmt.Fprintf(tw, "%d\t", results[i*len(workloads)+j].NsPerOp())
+			}
+			fmt.Fprintf(tw, "\n")
+		}
+		tw.Flush()
+	}
+	return results
+}
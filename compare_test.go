@@ -0,0 +1,35 @@
+package geoindex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func init() {
+	RegisterImpl("internal", func() Interface { return &internal.RTree{} })
+	RegisterImpl("generic", func() Interface {
+		return FromG[int](&gtree[float64, int]{})
+	})
+}
+
+func TestCompareImpls(t *testing.T) {
+	var buf bytes.Buffer
+	results := CompareImpls(&buf, 1000)
+	if len(results) != len(workloads)*2 {
+		t.Fatalf("expected %d, got %d", len(workloads)*2, len(results))
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "generic") ||
+		!strings.Contains(lines[2], "internal") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	RegisterImpl("internal", func() Interface { return &internal.RTree{} })
+}
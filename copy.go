@@ -0,0 +1,36 @@
+package geoindex
+
+// Copier is an optional interface for a tree that can copy itself, such as
+// by using copy-on-write nodes, which is usually much faster than rebuilding
+// the tree.
+type Copier interface {
+	// Copy returns a copy of the tree. Modifying the copy must not change
+	// the original, and modifying the original must not change the copy.
+	Copy() Interface
+}
+
+// Copy returns a new Index with a copy of the wrapped tree. When the tree is
+// a Copier then its Copy is used, otherwise the items are loaded into an
+// empty tree from newTree using Load. The newTree function is only called
+// when the tree isn't a Copier. The new Index has the same CRS.
+func (index *Index) Copy(newTree func() Interface) *Index {
+	if tr, ok := index.tree.(Copier); ok {
+		copied := Wrap(tr.Copy())
+		copied.crs = index.crs
+		return copied
+	}
+	n := index.tree.Len()
+	mins := make([][2]float64, 0, n)
+	maxs := make([][2]float64, 0, n)
+	data := make([]interface{}, 0, n)
+	index.tree.Scan(func(min, max [2]float64, item interface{}) bool {
+		mins = append(mins, min)
+		maxs = append(maxs, max)
+		data = append(data, item)
+		return true
+	})
+	copied := Wrap(newTree())
+	copied.crs = index.crs
+	copied.Load(mins, maxs, data)
+	return copied
+}
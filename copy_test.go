@@ -0,0 +1,65 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// copierTree is an RTree that counts its copies
+type copierTree struct {
+	internal.RTree
+	copies *int
+}
+
+func (tr *copierTree) Copy() Interface {
+	*tr.copies++
+	copied := &copierTree{copies: tr.copies}
+	tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		copied.Insert(min, max, data)
+		return true
+	})
+	return copied
+}
+
+func TestCopy(t *testing.T) {
+	var copies int
+	boxes := randBoxes(1000)
+	for _, newTree := range []func() Interface{
+		func() Interface { return &internal.RTree{} },
+		func() Interface { return &copierTree{copies: &copies} },
+		func() Interface { return &loaderTree{} },
+		func() Interface { return NewBucketTree(&internal.RTree{}) },
+	} {
+		tr := newTree()
+		index := Wrap(tr)
+		index.SetCRS(Meters)
+		for i, box := range boxes {
+			index.Insert(box.min, box.max, i)
+		}
+		copied := index.Copy(newTree)
+		if copied.CRS() != Meters {
+			t.Fatalf("%T: expected the CRS to be copied", tr)
+		}
+		if copied.Len() != index.Len() {
+			t.Fatalf("%T: expected %d, got %d", tr, index.Len(), copied.Len())
+		}
+		if err := copied.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if tr, ok := copied.tree.(*loaderTree); ok && tr.loads != 1 {
+			t.Fatalf("expected %d, got %d", 1, tr.loads)
+		}
+		// the copy is independent of the original
+		for i, box := range boxes[:100] {
+			copied.Delete(box.min, box.max, i)
+		}
+		if index.Len() != len(boxes) || copied.Len() != len(boxes)-100 {
+			t.Fatalf("%T: expected %d/%d, got %d/%d", tr, len(boxes),
+				len(boxes)-100, index.Len(), copied.Len())
+		}
+	}
+	if copies != 1 {
+		t.Fatalf("expected %d, got %d", 1, copies)
+	}
+}
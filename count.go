@@ -0,0 +1,92 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// Counter is an optional interface for a tree that keeps the number of items
+// under each of its nodes. When a tree implements it, Count uses the number
+// of a node that's fully inside of the rect rather than visiting all of its
+// items.
+type Counter interface {
+	// NodeCount returns the number of items under a node. The node is the
+	// Data of a non-item child.
+	NodeCount(node interface{}) int
+}
+
+// CountedChildren is an optional interface for a tree that keeps the number
+// of items under each of its nodes, and can return the numbers together with
+// the children of a node. It's preferred over Counter because the numbers
+// come with the children in a single call. See the internal RTree for an
+// example.
+type CountedChildren interface {
+	// CountedChildren is like Children but also returns the number of items
+	// under each child, in the same order as the children, where an item
+	// child counts as one. The reuse and counts buffers are empty length
+	// slices that can optionally be used to avoid extra allocations.
+	CountedChildren(parent interface{}, reuse []child.Child, counts []int) (
+		children []child.Child, _ []int,
+	)
+}
+
+// Count returns the number of items that intersect the rect param. When the
+// wrapped tree is a CountedChildren or a Counter then the nodes that are fully
+// inside of the rect are counted without visiting their items.
+func (index *Index) Count(min, max [2]float64) int {
+	defer index.stats.end(opSearch, index.stats.begin())
+	counted, isCounted := index.tree.(CountedChildren)
+	counter, isCounter := index.tree.(Counter)
+	if !isCounted && !isCounter {
+		var count int
+		index.tree.Search(min, max,
+			func(min, max [2]float64, data interface{}) bool {
+				count++
+				return true
+			},
+		)
+		return count
+	}
+	var count int
+	var walk func(parent interface{})
+	walk = func(parent interface{}) {
+		buf := getChildren()
+		var counts []int
+		if isCounted {
+			*buf, counts = counted.CountedChildren(parent, *buf, nil)
+		} else {
+			*buf = index.tree.Children(parent, *buf)
+		}
+		for i, c := range *buf {
+			if !intersects(min, max, c.Min, c.Max) {
+				continue
+			}
+			switch {
+			case c.Item:
+				count++
+			case contains(min, max, c.Min, c.Max):
+				if isCounted {
+					count += counts[i]
+				} else {
+					count += counter.NodeCount(c.Data)
+				}
+			default:
+				walk(c.Data)
+			}
+		}
+		putChildren(buf)
+	}
+	walk(nil)
+	return count
+}
+
+// Intersects returns true when any item intersects the rect param. The
+// search stops at the first item that's found.
+func (index *Index) Intersects(min, max [2]float64) bool {
+	defer index.stats.end(opSearch, index.stats.begin())
+	found := false
+	index.tree.Search(min, max,
+		func(min, max [2]float64, data interface{}) bool {
+			found = true
+			return false
+		},
+	)
+	return found
+}
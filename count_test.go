@@ -0,0 +1,72 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// countTree is a tree that counts its node items on demand
+type countTree struct {
+	Interface
+	calls int
+}
+
+func (tr *countTree) NodeCount(node interface{}) int {
+	tr.calls++
+	var count int
+	var walk func(parent interface{})
+	walk = func(parent interface{}) {
+		for _, c := range tr.Children(parent, nil) {
+			if c.Item {
+				count++
+			} else {
+				walk(c.Data)
+			}
+		}
+	}
+	walk(node)
+	return count
+}
+
+var _ Counter = &countTree{}
+var _ CountedChildren = &internal.RTree{}
+
+func TestCount(t *testing.T) {
+	counter := &countTree{Interface: struct{ Interface }{&internal.RTree{}}}
+	for _, tr := range []Interface{
+		&internal.RTree{}, struct{ Interface }{&internal.RTree{}}, counter,
+	} {
+		index := Wrap(tr)
+		if index.Count([2]float64{-180, -90}, [2]float64{180, 90}) != 0 ||
+			index.Intersects([2]float64{-180, -90}, [2]float64{180, 90}) {
+			t.Fatal("expected no items")
+		}
+		for i, box := range randBoxes(10000) {
+			index.Insert(box.min, box.max, i)
+		}
+		for _, r := range [][2][2]float64{
+			{{-180, -90}, {180, 90}},
+			{{-50, -50}, {50, 50}},
+			{{10, 10}, {11, 11}},
+			{{200, 200}, {300, 300}},
+		} {
+			var expect int
+			index.Search(r[0], r[1],
+				func(min, max [2]float64, data interface{}) bool {
+					expect++
+					return true
+				},
+			)
+			if n := index.Count(r[0], r[1]); n != expect {
+				t.Fatalf("%v: expected %d, got %d", r, expect, n)
+			}
+			if index.Intersects(r[0], r[1]) != (expect > 0) {
+				t.Fatalf("%v: expected %v", r, expect > 0)
+			}
+		}
+	}
+	if counter.calls == 0 {
+		t.Fatal("expected the node counts to be used")
+	}
+}
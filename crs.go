@@ -0,0 +1,157 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/algo"
+)
+
+// CRS is the coordinate reference system of the boxes of an index. It's
+// consulted by the operations that depend on the meaning of the coordinates,
+// such as Within, SearchCircle, PointAlgo, and SVG. The default is Degrees.
+type CRS interface {
+	// Bounds returns the extent of the valid coordinates.
+	Bounds() (min, max [2]float64)
+	// Dist returns the distance from a point to the nearest point of a box,
+	// which is in meters for a CRS of the Earth, and in the units of the
+	// coordinates otherwise.
+	Dist(p, min, max [2]float64) float64
+	// Circle returns the boxes that cover all of the points that are within
+	// a Dist of the center. The boxes may overlap.
+	Circle(center [2]float64, dist float64) [][2][2]float64
+}
+
+// Degrees is the CRS of lon,lat coordinates in degrees, such as WGS84, where
+// the distances are great-circle distances in meters.
+var Degrees CRS = degreesCRS{}
+
+// Radians is the CRS of lon,lat coordinates in radians, where the distances
+// are great-circle distances in meters.
+var Radians CRS = radiansCRS{}
+
+// Meters is the CRS of projected coordinates in meters, such as Web Mercator
+// (EPSG:3857), where the distances are planar distances in meters. The
+// bounds are the extent of Web Mercator.
+var Meters CRS = planarCRS{
+	[2]float64{-webMercatorMax, -webMercatorMax},
+	[2]float64{webMercatorMax, webMercatorMax},
+}
+
+// Normalized is the CRS of coordinates in the unit square, from 0,0 to 1,1,
+// where the distances are planar distances in the units of the coordinates.
+var Normalized CRS = planarCRS{[2]float64{0, 0}, [2]float64{1, 1}}
+
+// webMercatorMax is the largest coordinate of Web Mercator, in meters
+const webMercatorMax = math.Pi * 6378137
+
+// SetCRS sets the coordinate reference system of the index. A nil CRS is
+// Degrees.
+func (index *Index) SetCRS(crs CRS) {
+	index.crs = crs
+}
+
+// CRS returns the coordinate reference system of the index.
+func (index *Index) CRS() CRS {
+	if index.crs == nil {
+		return Degrees
+	}
+	return index.crs
+}
+
+// PointAlgo returns a kNN algo for the Dist of the CRS from a target point,
+// so that Nearby results are ordered by the same distances as Within.
+func (index *Index) PointAlgo(target [2]float64) (
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) {
+	crs := index.CRS()
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		return crs.Dist(target, min, max)
+	}
+}
+
+type degreesCRS struct{}
+
+func (degreesCRS) Bounds() (min, max [2]float64) {
+	return [2]float64{-180, -90}, [2]float64{180, 90}
+}
+
+func (degreesCRS) Dist(p, min, max [2]float64) float64 {
+	return algo.HaversineBox(p, min, max)
+}
+
+// Circle returns the bounding box of the circle, which is split in two when
+// it crosses the antimeridian and which spans all longitudes when it's over
+// a pole.
+func (degreesCRS) Circle(center [2]float64, meters float64) [][2][2]float64 {
+	r := meters / algo.EarthRadius // radians
+	dlat := r * 180 / math.Pi
+	min := [2]float64{-180, math.Max(-90, center[1]-dlat)}
+	max := [2]float64{180, math.Min(90, center[1]+dlat)}
+	if center[1]+dlat >= 90 || center[1]-dlat <= -90 || r >= math.Pi/2 {
+		// the circle covers a pole
+		return [][2][2]float64{{min, max}}
+	}
+	coslat := math.Cos(center[1] * math.Pi / 180)
+	dlon := math.Asin(math.Min(1, math.Sin(r)/coslat)) * 180 / math.Pi
+	min[0], max[0] = center[0]-dlon, center[0]+dlon
+	switch {
+	case dlon >= 180:
+		min[0], max[0] = -180, 180
+		return [][2][2]float64{{min, max}}
+	case min[0] < -180:
+		return [][2][2]float64{
+			{{-180, min[1]}, max},
+			{{min[0] + 360, min[1]}, {180, max[1]}},
+		}
+	case max[0] > 180:
+		return [][2][2]float64{
+			{min, {180, max[1]}},
+			{{-180, min[1]}, {max[0] - 360, max[1]}},
+		}
+	}
+	return [][2][2]float64{{min, max}}
+}
+
+type radiansCRS struct{}
+
+func toDegrees(p [2]float64) [2]float64 {
+	return [2]float64{p[0] * 180 / math.Pi, p[1] * 180 / math.Pi}
+}
+
+func (radiansCRS) Bounds() (min, max [2]float64) {
+	return [2]float64{-math.Pi, -math.Pi / 2}, [2]float64{math.Pi, math.Pi / 2}
+}
+
+func (radiansCRS) Dist(p, min, max [2]float64) float64 {
+	return degreesCRS{}.Dist(toDegrees(p), toDegrees(min), toDegrees(max))
+}
+
+func (radiansCRS) Circle(center [2]float64, meters float64) [][2][2]float64 {
+	rects := degreesCRS{}.Circle(toDegrees(center), meters)
+	for i := range rects {
+		for j := range rects[i] {
+			rects[i][j][0] *= math.Pi / 180
+			rects[i][j][1] *= math.Pi / 180
+		}
+	}
+	return rects
+}
+
+type planarCRS struct {
+	min, max [2]float64
+}
+
+func (crs planarCRS) Bounds() (min, max [2]float64) {
+	return crs.min, crs.max
+}
+
+func (planarCRS) Dist(p, min, max [2]float64) float64 {
+	return math.Sqrt(algo.BoxDistCalc(p, p, min, max, false))
+}
+
+func (planarCRS) Circle(center [2]float64, dist float64) [][2][2]float64 {
+	return [][2][2]float64{{
+		{center[0] - dist, center[1] - dist},
+		{center[0] + dist, center[1] + dist},
+	}}
+}
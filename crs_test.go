@@ -0,0 +1,84 @@
+package geoindex
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestCRS(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	if index.CRS() != Degrees {
+		t.Fatal("expected Degrees")
+	}
+	// the same items in degrees and in radians
+	boxes := randBoxes(2000)
+	rindex := Wrap(&internal.RTree{})
+	rindex.SetCRS(Radians)
+	rad := func(p [2]float64) [2]float64 {
+		return [2]float64{p[0] * math.Pi / 180, p[1] * math.Pi / 180}
+	}
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+		rindex.Insert(rad(box.min), rad(box.max), i)
+	}
+	for _, center := range [][2]float64{{0, 0}, {179.9, 10}, {10, 89.5}} {
+		for _, meters := range []float64{1e5, 1e6, 5e6} {
+			expect := make(map[interface{}]float64)
+			index.Within(center, meters,
+				func(min, max [2]float64, data interface{}, dist float64) bool {
+					expect[data] = dist
+					return true
+				},
+			)
+			var count int
+			rindex.Within(rad(center), meters,
+				func(min, max [2]float64, data interface{}, dist float64) bool {
+					if d, ok := expect[data]; !ok || math.Abs(d-dist) > 1e-3 {
+						t.Fatalf("unexpected item %v", data)
+					}
+					count++
+					return true
+				},
+			)
+			if count != len(expect) {
+				t.Fatalf("expected %d, got %d", len(expect), count)
+			}
+		}
+	}
+	// planar meters
+	mindex := Wrap(&internal.RTree{})
+	mindex.SetCRS(Meters)
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i) * 1000, 0}
+		mindex.Insert(p, p, i)
+	}
+	var count int
+	mindex.SearchCircle([2]float64{0, 500}, 5000,
+		func(min, max [2]float64, data interface{}) bool {
+			count++
+			return true
+		},
+	)
+	if count != 5 {
+		t.Fatalf("expected %d, got %d", 5, count)
+	}
+	if d := mindex.PointAlgo([2]float64{3000, 4000})([2]float64{0, 0},
+		[2]float64{0, 0}, nil, true); d != 5000 {
+		t.Fatalf("expected %v, got %v", 5000, d)
+	}
+	// the viewport of the svg is the bounds of the crs
+	nindex := Wrap(&internal.RTree{})
+	nindex.SetCRS(Normalized)
+	nindex.Insert([2]float64{0.5, 0.5}, [2]float64{0.5, 0.5}, 1)
+	if svg := nindex.SVG(); !strings.HasPrefix(svg,
+		"<svg viewBox=\"0 -1000 1000 1000\"") {
+		t.Fatalf("unexpected %q", svg)
+	}
+	nindex.SetCRS(nil)
+	if nindex.CRS() != Degrees {
+		t.Fatal("expected Degrees")
+	}
+}
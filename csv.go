@@ -0,0 +1,136 @@
+package geoindex
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrBadCSV is returned by LoadCSV when a record doesn't have a valid
+// coordinate, or when the header doesn't have the lon,lat columns. The
+// errors for records are a *csv.ParseError, which has the line and column.
+var ErrBadCSV = errors.New("geoindex: bad csv")
+
+// CSVOptions are the options for LoadCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter, such as '\t' for tab separated files.
+	Comma rune
+	// Comment, when not zero, is the character that starts a comment line.
+	Comment rune
+	// LazyQuotes allows for quotes in unquoted fields, such as in the
+	// GeoNames dumps.
+	LazyQuotes bool
+	// Header means that the first record is the names of the columns.
+	Header bool
+	// LonColumn and LatColumn are the names of the lon,lat columns in the
+	// header. When empty, the columns are found by the common names, such
+	// as "lon", "lng", "longitude", and "x".
+	LonColumn, LatColumn string
+	// LonIndex and LatIndex are the positions of the lon,lat columns, which
+	// are used when there's no header.
+	LonIndex, LatIndex int
+	// Data, when not nil, returns the data of the item for a record. The
+	// record is only valid until Data returns. When nil, the data is the
+	// record.
+	Data func(record []string) interface{}
+}
+
+// DefaultCSVOptions are the default options for LoadCSV, which are comma
+// separated records with the lon,lat in the first two columns.
+var DefaultCSVOptions = CSVOptions{
+	Comma:    ',',
+	LonIndex: 0,
+	LatIndex: 1,
+}
+
+var (
+	csvLonNames = []string{"lon", "lng", "long", "longitude", "x"}
+	csvLatNames = []string{"lat", "latitude", "y"}
+)
+
+// LoadCSV reads records of lon,lat points from a CSV file and loads them
+// into the tree. Returns the number of items that were loaded. Nothing is
+// loaded when there's an error.
+//
+//	// the GeoNames dumps are tab separated, without a header
+//	opts := geoindex.DefaultCSVOptions
+//	opts.Comma, opts.LazyQuotes = '\t', true
+//	opts.LonIndex, opts.LatIndex = 5, 4
+//	opts.Data = func(record []string) interface{} { return record[1] }
+//	n, err := geoindex.LoadCSV(f, opts, tr)
+func LoadCSV(r io.Reader, opts CSVOptions, tr Interface) (int, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = opts.Comma
+	cr.Comment = opts.Comment
+	cr.LazyQuotes = opts.LazyQuotes
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = opts.Data != nil
+	lon, lat := opts.LonIndex, opts.LatIndex
+	if opts.Header {
+		header, err := cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return 0, nil
+			}
+			return 0, err
+		}
+		lon = csvColumn(header, opts.LonColumn, csvLonNames)
+		lat = csvColumn(header, opts.LatColumn, csvLatNames)
+		if lon == -1 || lat == -1 {
+			return 0, ErrBadCSV
+		}
+	}
+	var items []Item
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		var p [2]float64
+		for i, col := range [2]int{lon, lat} {
+			if col >= len(record) {
+				line, _ := cr.FieldPos(0)
+				return 0, &csv.ParseError{StartLine: line, Line: line,
+					Column: 1, Err: ErrBadCSV}
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(record[col]), 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				line, column := cr.FieldPos(col)
+				return 0, &csv.ParseError{StartLine: line, Line: line,
+					Column: column, Err: ErrBadCSV}
+			}
+			p[i] = f
+		}
+		var data interface{} = record
+		if opts.Data != nil {
+			data = opts.Data(record)
+		}
+		items = append(items, Item{p, p, data})
+	}
+	loadItems(tr, items)
+	return len(items), nil
+}
+
+// csvColumn returns the position of the named column of the header, or of
+// the first of the common names when the name is empty. Returns -1 when the
+// column isn't found.
+func csvColumn(header []string, name string, common []string) int {
+	names := common
+	if name != "" {
+		names = []string{name}
+	}
+	for _, name := range names {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
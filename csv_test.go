@@ -0,0 +1,105 @@
+package geoindex
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestLoadCSV(t *testing.T) {
+	tests := []struct {
+		input  string
+		opts   func(opts *CSVOptions)
+		expect map[interface{}][2]float64
+	}{
+		{"1,2,a\n3.5,-4,b\n", nil,
+			map[interface{}][2]float64{"a": {1, 2}, "b": {3.5, -4}}},
+		{"name,Latitude,Longitude\na,2,1\n# comment\nb, -4 ,3.5\n",
+			func(opts *CSVOptions) {
+				opts.Header = true
+				opts.Comment = '#'
+			},
+			map[interface{}][2]float64{"a": {1, 2}, "b": {3.5, -4}}},
+		{"name;y;x;px\na;2;1;9\n", func(opts *CSVOptions) {
+			opts.Comma = ';'
+			opts.Header = true
+			opts.LonColumn = "PX"
+		}, map[interface{}][2]float64{"a": {9, 2}}},
+		// the GeoNames layout
+		{"1\tThe \"Big\" City\t0\t0\t50.5\t-3.25\n", func(opts *CSVOptions) {
+			opts.Comma = '\t'
+			opts.LazyQuotes = true
+			opts.LonIndex, opts.LatIndex = 5, 4
+		}, map[interface{}][2]float64{"The \"Big\" City": {-3.25, 50.5}}},
+		{"lon,lat\n", func(opts *CSVOptions) { opts.Header = true }, nil},
+		{"", func(opts *CSVOptions) { opts.Header = true }, nil},
+	}
+	for i, tt := range tests {
+		opts := DefaultCSVOptions
+		if tt.opts != nil {
+			tt.opts(&opts)
+		}
+		// the name is the only non-numeric column
+		opts.Data = func(record []string) interface{} {
+			for _, field := range record {
+				if field != "" && strings.Trim(field, " -.0123456789") != "" {
+					return field
+				}
+			}
+			return nil
+		}
+		tr := &internal.RTree{}
+		n, err := LoadCSV(strings.NewReader(tt.input), opts, tr)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if n != len(tt.expect) || tr.Len() != len(tt.expect) {
+			t.Fatalf("%d: expected %d, got %d", i, len(tt.expect), n)
+		}
+		tr.Scan(func(min, max [2]float64, data interface{}) bool {
+			if p, ok := tt.expect[data]; !ok || min != p || max != p {
+				t.Fatalf("%d: unexpected %v %v %v", i, min, max, data)
+			}
+			return true
+		})
+	}
+	// the record is the data by default
+	tr := &internal.RTree{}
+	LoadCSV(strings.NewReader("1,2,a\n"), DefaultCSVOptions, tr)
+	tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		if r := data.([]string); len(r) != 3 || r[2] != "a" {
+			t.Fatalf("unexpected %v", data)
+		}
+		return true
+	})
+	for _, tt := range []struct {
+		input  string
+		header bool
+		line   int
+	}{
+		{"1,2\n3,x\n", false, 2},
+		{"1,2\n3\n", false, 2},
+		{"1,NaN\n", false, 1},
+		{"a,b\n1,2\n", true, 0},
+	} {
+		opts := DefaultCSVOptions
+		opts.Header = tt.header
+		tr := &internal.RTree{}
+		n, err := LoadCSV(strings.NewReader(tt.input), opts, tr)
+		if !errors.Is(err, ErrBadCSV) || n != 0 || tr.Len() != 0 {
+			t.Fatalf("%q: expected %v, got %v", tt.input, ErrBadCSV, err)
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) != (tt.line != 0) ||
+			(perr != nil && perr.Line != tt.line) {
+			t.Fatalf("%q: unexpected error %v", tt.input, err)
+		}
+	}
+	if _, err := LoadCSV(strings.NewReader("1,\"2\n"), DefaultCSVOptions,
+		&internal.RTree{}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
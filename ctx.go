@@ -0,0 +1,122 @@
+package geoindex
+
+import "context"
+
+func canceled(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// SearchCtx is like Search but stops when the context is canceled or its
+// deadline is exceeded, in which case the context error is returned. The
+// Children of the tree are walked, and the context is checked before each
+// node is visited and before each item is passed to iter, so that a search
+// that only matches a few items still stops soon after it's canceled.
+func (index *Index) SearchCtx(
+	ctx context.Context, min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) error {
+	defer index.stats.end(opSearch, index.stats.begin())
+	var err error
+	var walk func(parent interface{}) bool
+	walk = func(parent interface{}) bool {
+		if canceled(ctx) {
+			err = ctx.Err()
+			return false
+		}
+		buf := getChildren()
+		defer putChildren(buf)
+		*buf = index.tree.Children(parent, *buf)
+		for _, c := range *buf {
+			if !intersects(min, max, c.Min, c.Max) {
+				continue
+			}
+			if !c.Item {
+				if !walk(c.Data) {
+					return false
+				}
+				continue
+			}
+			if canceled(ctx) {
+				err = ctx.Err()
+				return false
+			}
+			if !iter(c.Min, c.Max, c.Data) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(nil)
+	return err
+}
+
+// ScanCtx is like Scan but stops when the context is canceled or its
+// deadline is exceeded, in which case the context error is returned.
+func (index *Index) ScanCtx(
+	ctx context.Context,
+	iter func(min, max [2]float64, data interface{}) bool,
+) error {
+	if canceled(ctx) {
+		return ctx.Err()
+	}
+	var err error
+	index.Scan(func(min, max [2]float64, data interface{}) bool {
+		if canceled(ctx) {
+			err = ctx.Err()
+			return false
+		}
+		return iter(min, max, data)
+	})
+	return err
+}
+
+// NearbyCtx is like Nearby but stops when the context is canceled or its
+// deadline is exceeded, in which case the context error is returned. The
+// context is checked for each node and item that's passed to algo, and
+// before each item is passed to iter. Like NearbyAux, this function always
+// uses the Children of the wrapped tree.
+func (index *Index) NearbyCtx(
+	ctx context.Context,
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) error {
+	defer index.stats.end(opNearby, index.stats.begin())
+	if canceled(ctx) {
+		return ctx.Err()
+	}
+	q := getQueue()
+	defer putQueue(q)
+	var err error
+	it := &NearbyIterator{index: index, buf: q}
+	it.algo = func(min, max [2]float64, data interface{}, item bool) (
+		dist float64, aux interface{},
+	) {
+		if err == nil && canceled(ctx) {
+			// stop the traversal
+			err = ctx.Err()
+			it.closed = true
+		}
+		if err != nil {
+			return 0, nil
+		}
+		return algo(min, max, data, item), nil
+	}
+	for {
+		node, ok := it.next()
+		if !ok {
+			return err
+		}
+		if canceled(ctx) {
+			return ctx.Err()
+		}
+		if !iter(node.child.Min, node.child.Max, node.child.Data,
+			node.dist) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package geoindex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/internal"
+)
+
+// cancelTree cancels a context after a number of calls to Children
+type cancelTree struct {
+	Interface
+	cancel func()
+	calls  int
+}
+
+func (tr *cancelTree) Children(parent interface{}, reuse []child.Child,
+) []child.Child {
+	if tr.calls++; tr.calls == 3 {
+		tr.cancel()
+	}
+	return tr.Interface.Children(parent, reuse)
+}
+
+func TestCtx(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(1000) {
+		index.Insert(box.min, box.max, i)
+	}
+	all := [2][2]float64{{-180, -90}, {180, 90}}
+	nearby := algo.Box([2]float64{}, [2]float64{}, false, nil)
+	ops := map[string]func(ctx context.Context, cancel func(), n *int) error{
+		"search": func(ctx context.Context, cancel func(), n *int) error {
+			return index.SearchCtx(ctx, all[0], all[1],
+				func(min, max [2]float64, data interface{}) bool {
+					if *n++; *n == 10 {
+						cancel()
+					}
+					return true
+				})
+		},
+		"scan": func(ctx context.Context, cancel func(), n *int) error {
+			return index.ScanCtx(ctx,
+				func(min, max [2]float64, data interface{}) bool {
+					if *n++; *n == 10 {
+						cancel()
+					}
+					return true
+				})
+		},
+		"nearby": func(ctx context.Context, cancel func(), n *int) error {
+			return index.NearbyCtx(ctx, nearby,
+				func(min, max [2]float64, data interface{},
+					dist float64) bool {
+					if *n++; *n == 10 {
+						cancel()
+					}
+					return true
+				})
+		},
+	}
+	for name, op := range ops {
+		var n int
+		if err := op(context.Background(), func() {}, &n); err != nil ||
+			n != 1000 {
+			t.Fatalf("%s: expected %d, got %d (%v)", name, 1000, n, err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		n = 0
+		if err := op(ctx, cancel, &n); err != context.Canceled || n != 10 {
+			t.Fatalf("%s: expected %d, got %d (%v)", name, 10, n, err)
+		}
+		n = 0
+		if err := op(ctx, cancel, &n); err != context.Canceled || n != 0 {
+			t.Fatalf("%s: expected %d, got %d (%v)", name, 0, n, err)
+		}
+	}
+}
+
+func TestCtxNodes(t *testing.T) {
+	// the context is checked while walking the nodes, even when no items
+	// are passed to iter
+	rt := &internal.RTree{}
+	for i, box := range randBoxes(1000) {
+		rt.Insert(box.min, box.max, i)
+	}
+	all := [2][2]float64{{-180, -90}, {180, 90}}
+	ctx, cancel := context.WithCancel(context.Background())
+	tr := &cancelTree{Interface: rt, cancel: cancel}
+	index := Wrap(tr)
+	var n int
+	err := index.SearchCtx(ctx, all[0], all[1],
+		func(min, max [2]float64, data interface{}) bool {
+			n++
+			return true
+		},
+	)
+	if err != context.Canceled || n == 1000 || tr.calls > 4 {
+		t.Fatalf("expected a canceled search, got %d items, %d calls (%v)",
+			n, tr.calls, err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	tr.cancel, tr.calls, n = cancel, 0, 0
+	err = index.NearbyCtx(ctx, algo.Box([2]float64{}, [2]float64{}, false,
+		nil), func(min, max [2]float64, data interface{}, dist float64) bool {
+		n++
+		return true
+	})
+	if err != context.Canceled || n == 1000 || tr.calls > 3 {
+		t.Fatalf("expected a canceled nearby, got %d items, %d calls (%v)",
+			n, tr.calls, err)
+	}
+}
@@ -0,0 +1,83 @@
+package geoindex
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// Density returns the number of items per cell of a grid of cols by rows
+// cells that covers the bounds param, in a single search. An item is in the
+// cell that has the center of its box, and items with a center outside of
+// the bounds aren't counted. The grid is indexed by row and then column,
+// where the first row is at the min y of the bounds. Returns nil when cols or
+// rows is less than one.
+func (index *Index) Density(bounds [2][2]float64, cols, rows int) [][]int {
+	if cols < 1 || rows < 1 {
+		return nil
+	}
+	grid := make([][]int, rows)
+	cells := make([]int, cols*rows)
+	for i := range grid {
+		grid[i] = cells[i*cols : (i+1)*cols]
+	}
+	min, max := bounds[0], bounds[1]
+	// cell returns the cell for a coordinate, where the max edge is in the
+	// last cell
+	cell := func(v, min, max float64, n int) int {
+		if max <= min {
+			return 0
+		}
+		i := int(math.Floor((v - min) / (max - min) * float64(n)))
+		if i >= n {
+			i = n - 1
+		}
+		return i
+	}
+	index.Search(min, max, func(imin, imax [2]float64, _ interface{}) bool {
+		c := [2]float64{(imin[0] + imax[0]) / 2, (imin[1] + imax[1]) / 2}
+		if c[0] < min[0] || c[0] > max[0] || c[1] < min[1] || c[1] > max[1] {
+			return true
+		}
+		grid[cell(c[1], min[1], max[1], rows)][cell(c[0], min[0], max[0],
+			cols)]++
+		return true
+	})
+	return grid
+}
+
+// DensityPNG writes the Density of the items as a PNG to w, with a pixel for
+// each cell and the first row at the bottom of the image. Cells with no items
+// are transparent, and the other cells are black with an opacity that grows
+// with the square root of the number of items, relative to the densest cell.
+func (index *Index) DensityPNG(w io.Writer, bounds [2][2]float64,
+	cols, rows int,
+) error {
+	if cols < 1 || rows < 1 {
+		return errors.New("geoindex: invalid image size")
+	}
+	grid := index.Density(bounds, cols, rows)
+	var most int
+	for _, row := range grid {
+		for _, n := range row {
+			if n > most {
+				most = n
+			}
+		}
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, cols, rows))
+	for i, row := range grid {
+		for x, n := range row {
+			if n == 0 {
+				continue
+			}
+			a := math.Sqrt(float64(n) / float64(most))
+			img.SetNRGBA(x, rows-1-i, color.NRGBA{A: uint8(math.Max(1,
+				math.Round(a*0xff)))})
+		}
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,72 @@
+package geoindex
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestDensity(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 10000; i++ {
+		p := [2]float64{float64(i%100) + 0.5, float64(i/100) + 0.5}
+		index.Insert(p, p, i)
+	}
+	// an extra item in the bottom left cell, and one outside of the bounds
+	index.Insert([2]float64{0, 0}, [2]float64{1, 1}, -1)
+	index.Insert([2]float64{-5, -5}, [2]float64{-4, 0.5}, -2)
+	bounds := [2][2]float64{{0, 0}, {100, 100}}
+	grid := index.Density(bounds, 10, 5)
+	if len(grid) != 5 || len(grid[0]) != 10 {
+		t.Fatalf("unexpected size %dx%d", len(grid[0]), len(grid))
+	}
+	var total int
+	for i, row := range grid {
+		for j, n := range row {
+			expect := 200
+			if i == 0 && j == 0 {
+				expect++
+			}
+			if n != expect {
+				t.Fatalf("%d,%d: expected %d, got %d", j, i, expect, n)
+			}
+			total += n
+		}
+	}
+	if total != 10001 {
+		t.Fatalf("expected %d, got %d", 10001, total)
+	}
+	// the max edge is in the last cell
+	index = Wrap(&internal.RTree{})
+	index.Insert([2]float64{100, 100}, [2]float64{100, 100}, 1)
+	if grid := index.Density(bounds, 2, 2); grid[1][1] != 1 {
+		t.Fatalf("unexpected grid %v", grid)
+	}
+	if index.Density(bounds, 0, 2) != nil {
+		t.Fatal("expected nil")
+	}
+
+	var buf bytes.Buffer
+	if err := index.DensityPNG(&buf, bounds, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 2 {
+		t.Fatalf("unexpected size %v", b)
+	}
+	// the densest cell is opaque and the first row is at the bottom
+	if _, _, _, a := img.At(1, 0).RGBA(); a != 0xffff {
+		t.Fatalf("expected opaque, got %v", a)
+	}
+	if _, _, _, a := img.At(1, 1).RGBA(); a != 0 {
+		t.Fatalf("expected transparent, got %v", a)
+	}
+	if err := index.DensityPNG(&buf, bounds, 0, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+}
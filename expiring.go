@@ -0,0 +1,157 @@
+package geoindex
+
+import (
+	"time"
+
+	"github.com/tidwall/geoindex/pqueue"
+)
+
+// ExpiringIndex is an Index of items that each have an expiration time, such
+// as the last known positions of a fleet of vehicles. The expired items are
+// skipped by Search, Scan, and Nearby, and are removed from the tree in bulk
+// by Sweep.
+type ExpiringIndex struct {
+	index   *Index
+	expires pqueue.Queue[*expiringItem] // by expiration time
+	now     func() time.Time
+}
+
+// expiringItem is the data of an item in the tree
+type expiringItem struct {
+	min, max [2]float64
+	data     interface{}
+	expires  time.Time
+	deleted  bool
+}
+
+// expired returns true when the item has expired at the time
+func (item *expiringItem) expired(now time.Time) bool {
+	return !item.expires.IsZero() && !item.expires.After(now)
+}
+
+// ExpiringWrap wraps a tree-like geospatial interface in an ExpiringIndex.
+// The tree should be empty, and it must only be modified by the
+// ExpiringIndex.
+func ExpiringWrap(tree Interface) *ExpiringIndex {
+	return &ExpiringIndex{index: Wrap(tree), now: time.Now}
+}
+
+// Len returns the number of items, including the expired items that haven't
+// been removed by Sweep.
+func (ei *ExpiringIndex) Len() int {
+	return ei.index.Len()
+}
+
+// Insert an item that expires at the expires time. A zero time never
+// expires.
+func (ei *ExpiringIndex) Insert(min, max [2]float64, data interface{},
+	expires time.Time,
+) {
+	item := &expiringItem{min: min, max: max, data: data, expires: expires}
+	ei.index.Insert(min, max, item)
+	if !expires.IsZero() {
+		ei.expires.Push(item, float64(expires.UnixNano()))
+	}
+}
+
+// Delete an item from the index, whether it has expired or not. Returns true
+// when an item was deleted.
+func (ei *ExpiringIndex) Delete(min, max [2]float64, data interface{}) bool {
+	var found *expiringItem
+	ei.index.Search(min, max, func(imin, imax [2]float64, idata interface{}) bool {
+		item := idata.(*expiringItem)
+		if imin == min && imax == max && item.data == data {
+			found = item
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		return false
+	}
+	found.deleted = true
+	return ei.index.Delete(found.min, found.max, found)
+}
+
+// Sweep removes all items that have expired at the now time, and returns the
+// number of removed items. Only the expired items are visited, from the
+// earliest expiration time.
+func (ei *ExpiringIndex) Sweep(now time.Time) int {
+	var n int
+	for {
+		item, prio, ok := ei.expires.Peek()
+		if !ok || prio > float64(now.UnixNano()) {
+			break
+		}
+		if !item.deleted && !item.expired(now) {
+			// the priority is rounded, so this is close to expiring
+			break
+		}
+		ei.expires.Pop()
+		if item.deleted {
+			continue
+		}
+		item.deleted = true
+		if ei.index.Delete(item.min, item.max, item) {
+			n++
+		}
+	}
+	return n
+}
+
+// Search the index for items that intersect the rect param and that haven't
+// expired.
+func (ei *ExpiringIndex) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	now := ei.now()
+	ei.index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		item := data.(*expiringItem)
+		if item.expired(now) {
+			return true
+		}
+		return iter(min, max, item.data)
+	})
+}
+
+// Scan iterates through all items that haven't expired, in no specified
+// order.
+func (ei *ExpiringIndex) Scan(
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	now := ei.now()
+	ei.index.Scan(func(min, max [2]float64, data interface{}) bool {
+		item := data.(*expiringItem)
+		if item.expired(now) {
+			return true
+		}
+		return iter(min, max, item.data)
+	})
+}
+
+// Nearby performs a kNN-type operation on the items that haven't expired.
+// The algo is called with the data of the items, in the same way as
+// Index.Nearby.
+// See Nearby for a description of the `algo` and `iter` functions.
+func (ei *ExpiringIndex) Nearby(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	now := ei.now()
+	ei.index.Nearby(
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			if item {
+				data = data.(*expiringItem).data
+			}
+			return algo(min, max, data, item)
+		},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			item := data.(*expiringItem)
+			if item.expired(now) {
+				return true
+			}
+			return iter(min, max, item.data, dist)
+		},
+	)
+}
@@ -0,0 +1,84 @@
+package geoindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestExpiringIndex(t *testing.T) {
+	start := time.Unix(1000, 0)
+	now := start
+	ei := ExpiringWrap(&internal.RTree{})
+	ei.now = func() time.Time { return now }
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		var expires time.Time
+		if i%10 != 0 {
+			expires = start.Add(time.Duration(i) * time.Second)
+		}
+		ei.Insert(p, p, i, expires)
+	}
+	count := func() (search, scan, nearby int) {
+		ei.Search([2]float64{-1, -1}, [2]float64{100, 100},
+			func(_, _ [2]float64, _ interface{}) bool {
+				search++
+				return true
+			},
+		)
+		ei.Scan(func(_, _ [2]float64, _ interface{}) bool {
+			scan++
+			return true
+		})
+		ei.Nearby(algo.Box([2]float64{0, 0}, [2]float64{0, 0}, false, nil),
+			func(_, _ [2]float64, data interface{}, _ float64) bool {
+				nearby++
+				return true
+			},
+		)
+		return search, scan, nearby
+	}
+	if a, b, c := count(); a != 100 || b != 100 || c != 100 {
+		t.Fatalf("expected 100, got %d %d %d", a, b, c)
+	}
+	// items 1-50 have expired, except for 10, 20, 30, 40, and 50
+	now = start.Add(50 * time.Second)
+	if a, b, c := count(); a != 55 || b != 55 || c != 55 {
+		t.Fatalf("expected 55, got %d %d %d", a, b, c)
+	}
+	if ei.Len() != 100 {
+		t.Fatalf("expected 100, got %d", ei.Len())
+	}
+	ei.Nearby(algo.Box([2]float64{0, 0}, [2]float64{0, 0}, false, nil),
+		func(_, _ [2]float64, data interface{}, _ float64) bool {
+			if data.(int) != 0 {
+				t.Fatalf("expected 0, got %v", data)
+			}
+			return false
+		},
+	)
+	// a deleted item is not swept
+	if !ei.Delete([2]float64{1, 1}, [2]float64{1, 1}, 1) {
+		t.Fatal("expected true")
+	}
+	if ei.Delete([2]float64{1, 1}, [2]float64{1, 1}, 1) {
+		t.Fatal("expected false")
+	}
+	if n := ei.Sweep(now); n != 44 {
+		t.Fatalf("expected 44, got %d", n)
+	}
+	if ei.Len() != 55 {
+		t.Fatalf("expected 55, got %d", ei.Len())
+	}
+	if n := ei.Sweep(now); n != 0 {
+		t.Fatalf("expected 0, got %d", n)
+	}
+	if n := ei.Sweep(start.Add(time.Hour)); n != 45 {
+		t.Fatalf("expected 45, got %d", n)
+	}
+	if ei.Len() != 10 {
+		t.Fatalf("expected 10, got %d", ei.Len())
+	}
+}
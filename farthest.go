@@ -0,0 +1,41 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// Farthest performs a kNN-type operation on the index in the reverse order,
+// from the largest dist to the smallest dist, such as for coverage analysis
+// or for finding outliers. Unlike Nearby, the dist of a node must never be
+// smaller than the dist of any item in the node, which is an upper bound
+// rather than a lower bound, such as the distance of algo.Farthest. The
+// items may be returned until the iter function returns false.
+func (index *Index) Farthest(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	defer index.stats.end(opNearby, index.stats.begin())
+	q := getQueue()
+	defer putQueue(q)
+	// the queue pops the smallest first, so the dists are negated
+	push := func(children []child.Child) {
+		for _, c := range children {
+			q.q.push(qnode{dist: -algo(c.Min, c.Max, c.Data, c.Item), child: c})
+		}
+	}
+	q.children = index.tree.Children(nil, q.children[:0])
+	push(q.children)
+	for {
+		node, ok := q.q.pop()
+		if !ok {
+			return
+		}
+		if node.child.Item {
+			if !iter(node.child.Min, node.child.Max, node.child.Data,
+				-node.dist) {
+				return
+			}
+			continue
+		}
+		q.children = index.tree.Children(node.child.Data, q.children[:0])
+		push(q.children)
+	}
+}
@@ -0,0 +1,48 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestFarthest(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(5000) {
+		index.Insert(box.min, box.max, i)
+	}
+	target := [2]float64{10, 20}
+	var expect []Result
+	index.Nearby(algo.Box(target, target, false, nil),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			expect = append(expect, Result{min, max, data, dist})
+			return true
+		},
+	)
+	var results []Result
+	index.Farthest(algo.Farthest(target, target),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			results = append(results, Result{min, max, data, dist})
+			return true
+		},
+	)
+	if len(results) != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), len(results))
+	}
+	for i := range results {
+		if e := expect[len(expect)-1-i]; results[i].Dist != e.Dist {
+			t.Fatalf("%d: expected %v, got %v", i, e.Dist, results[i].Dist)
+		}
+	}
+	var count int
+	index.Farthest(algo.Farthest(target, target),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			count++
+			return count < 10
+		},
+	)
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+}
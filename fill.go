@@ -0,0 +1,78 @@
+package geoindex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// FillLevel is the node fill of one level of the tree.
+type FillLevel struct {
+	// Nodes is the number of nodes on the level.
+	Nodes int
+	// Min and Max are the smallest and largest number of children of a
+	// node on the level.
+	Min, Max int
+	// Mean is the average number of children per node.
+	Mean float64
+	// Histogram counts the nodes by their number of children, where
+	// Histogram[n] is the number of nodes that have n children.
+	Histogram []int
+}
+
+// FillReport is the node fill of each level of a tree, starting with the
+// root nodes at level zero.
+type FillReport []FillLevel
+
+// Fill returns a histogram of the number of children per node for each level
+// of the tree, using the Children of the wrapped tree. A tree that has been
+// degraded by many deletes will have lots of nodes with few children.
+func (index *Index) Fill() FillReport {
+	var report FillReport
+	var walk func(c child.Child, level int)
+	walk = func(c child.Child, level int) {
+		children := index.tree.Children(c.Data, nil)
+		for len(report) <= level {
+			report = append(report, FillLevel{})
+		}
+		l := &report[level]
+		n := len(children)
+		if l.Nodes == 0 || n < l.Min {
+			l.Min = n
+		}
+		if n > l.Max {
+			l.Max = n
+		}
+		for len(l.Histogram) <= n {
+			l.Histogram = append(l.Histogram, 0)
+		}
+		l.Histogram[n]++
+		l.Nodes++
+		l.Mean += float64(n)
+		for _, c := range children {
+			if !c.Item {
+				walk(c, level+1)
+			}
+		}
+	}
+	for _, c := range index.tree.Children(nil, nil) {
+		if !c.Item {
+			walk(c, 0)
+		}
+	}
+	for i := range report {
+		report[i].Mean /= float64(report[i].Nodes)
+	}
+	return report
+}
+
+// String returns the report as text, with one line per level.
+func (report FillReport) String() string {
+	var sb strings.Builder
+	for i, l := range report {
+		fmt.Fprintf(&sb, "level %d: %d nodes, min %d, max %d, mean %.1f\n",
+			i, l.Nodes, l.Min, l.Max, l.Mean)
+	}
+	return sb.String()
+}
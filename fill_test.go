@@ -0,0 +1,48 @@
+package geoindex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestFill(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	if report := index.Fill(); len(report) != 0 {
+		t.Fatalf("expected %d, got %d", 0, len(report))
+	}
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	report := index.Fill()
+	if len(report) < 2 || report[0].Nodes != 1 {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+	for i, l := range report {
+		var nodes, children int
+		for n, count := range l.Histogram {
+			nodes += count
+			children += n * count
+		}
+		if nodes != l.Nodes || l.Min > l.Max ||
+			l.Mean != float64(children)/float64(nodes) {
+			t.Fatalf("level %d: unexpected %+v", i, l)
+		}
+		if i < len(report)-1 && children != report[i+1].Nodes {
+			t.Fatalf("level %d: expected %d, got %d",
+				i, report[i+1].Nodes, children)
+		}
+	}
+	var children int
+	for n, count := range report[len(report)-1].Histogram {
+		children += n * count
+	}
+	if children != len(boxes) {
+		t.Fatalf("expected %d, got %d", len(boxes), children)
+	}
+	if lines := strings.Count(report.String(), "\n"); lines != len(report) {
+		t.Fatalf("expected %d, got %d", len(report), lines)
+	}
+}
@@ -0,0 +1,116 @@
+// Package filter provides predicates for Index.SearchFiltered, which check
+// the items that are found by a bounding box search against an exact shape.
+package filter
+
+import "math"
+
+// Predicate returns true when an item matches, which can be used with
+// Index.SearchFiltered.
+type Predicate func(min, max [2]float64, data interface{}) bool
+
+// Polygon returns a Predicate for the items that intersect the polygon,
+// which is a point item inside of the polygon, or a box item that has any
+// part inside of the polygon. The ring is the lon,lat or x,y vertices of
+// the polygon, and may be either closed or open, such that the last vertex
+// is the same as the first. The points that are exactly on an edge may or
+// may not match. Search with the Bounds of the ring to find the items.
+//
+//	ring := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 0}}
+//	min, max := filter.Bounds(ring)
+//	index.SearchFiltered(min, max, filter.Polygon(ring), iter)
+func Polygon(ring [][2]float64) Predicate {
+	ring = append([][2]float64(nil), ring...)
+	bmin, bmax := Bounds(ring)
+	return func(min, max [2]float64, data interface{}) bool {
+		if len(ring) < 3 || !intersects(bmin, bmax, min, max) {
+			return false
+		}
+		if min == max {
+			return pointInRing(min, ring)
+		}
+		return boxIntersectsRing(min, max, ring)
+	}
+}
+
+// Bounds returns the bounding box of the ring
+func Bounds(ring [][2]float64) (min, max [2]float64) {
+	for i, p := range ring {
+		if i == 0 {
+			min, max = p, p
+			continue
+		}
+		min = [2]float64{math.Min(min[0], p[0]), math.Min(min[1], p[1])}
+		max = [2]float64{math.Max(max[0], p[0]), math.Max(max[1], p[1])}
+	}
+	return min, max
+}
+
+func intersects(aMin, aMax, bMin, bMax [2]float64) bool {
+	return !(bMin[0] > aMax[0] || bMax[0] < aMin[0] ||
+		bMin[1] > aMax[1] || bMax[1] < aMin[1])
+}
+
+// pointInRing uses the even-odd rule
+func pointInRing(p [2]float64, ring [][2]float64) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[1] > p[1]) != (b[1] > p[1]) &&
+			p[0] < (b[0]-a[0])*(p[1]-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
+
+func boxIntersectsRing(min, max [2]float64, ring [][2]float64) bool {
+	// a vertex of the ring is inside of the box
+	for _, p := range ring {
+		if intersects(min, max, p, p) {
+			return true
+		}
+	}
+	// the box is inside of the ring
+	if pointInRing(min, ring) {
+		return true
+	}
+	// an edge of the ring crosses an edge of the box
+	corners := [4][2]float64{min, {max[0], min[1]}, max, {min[0], max[1]}}
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		for k := 0; k < 4; k++ {
+			if segmentsIntersect(ring[j], ring[i], corners[k],
+				corners[(k+1)%4]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// orient returns the sign of the cross product of ab and ac
+func orient(a, b, c [2]float64) int {
+	v := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+func segmentsIntersect(a, b, c, d [2]float64) bool {
+	o1, o2 := orient(a, b, c), orient(a, b, d)
+	o3, o4 := orient(c, d, a), orient(c, d, b)
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	// collinear segments that overlap
+	onSegment := func(a, b, p [2]float64) bool {
+		return intersects(
+			[2]float64{math.Min(a[0], b[0]), math.Min(a[1], b[1])},
+			[2]float64{math.Max(a[0], b[0]), math.Max(a[1], b[1])}, p, p)
+	}
+	return (o1 == 0 && onSegment(a, b, c)) || (o2 == 0 && onSegment(a, b, d)) ||
+		(o3 == 0 && onSegment(c, d, a)) || (o4 == 0 && onSegment(c, d, b))
+}
@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestPolygon(t *testing.T) {
+	// a concave "C" shape
+	ring := [][2]float64{
+		{0, 0}, {10, 0}, {10, 2}, {2, 2}, {2, 8}, {10, 8}, {10, 10}, {0, 10},
+		{0, 0},
+	}
+	pred := Polygon(ring)
+	for _, tt := range []struct {
+		min, max [2]float64
+		expect   bool
+	}{
+		{[2]float64{1, 1}, [2]float64{1, 1}, true},
+		{[2]float64{5, 5}, [2]float64{5, 5}, false}, // inside the notch
+		{[2]float64{5, 9}, [2]float64{5, 9}, true},
+		{[2]float64{11, 5}, [2]float64{11, 5}, false},
+		{[2]float64{4, 4}, [2]float64{6, 6}, false}, // box in the notch
+		{[2]float64{1, 4}, [2]float64{6, 6}, true},  // edge crossing
+		{[2]float64{-5, -5}, [2]float64{15, 15}, true},
+		{[2]float64{0.5, 0.5}, [2]float64{1, 1}, true}, // inside
+		{[2]float64{3, -1}, [2]float64{4, 11}, true},   // crosses the edges
+		{[2]float64{11, 0}, [2]float64{12, 10}, false},
+	} {
+		if pred(tt.min, tt.max, nil) != tt.expect {
+			t.Fatalf("%v %v: expected %v", tt.min, tt.max, tt.expect)
+		}
+	}
+	// an open ring is the same as a closed ring
+	open := Polygon(ring[:len(ring)-1])
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{rand.Float64()*12 - 1, rand.Float64()*12 - 1}
+		if pred(p, p, nil) != open(p, p, nil) {
+			t.Fatalf("%v: expected %v", p, pred(p, p, nil))
+		}
+	}
+	if Polygon(ring[:2])([2]float64{0, 0}, [2]float64{10, 10}, nil) {
+		t.Fatal("expected no match for a degenerate ring")
+	}
+	if min, max := Bounds(ring); min != [2]float64{0, 0} ||
+		max != [2]float64{10, 10} {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+}
+
+func TestSearchFiltered(t *testing.T) {
+	index := geoindex.Wrap(&internal.RTree{})
+	var pts [][2]float64
+	for i := 0; i < 10000; i++ {
+		p := [2]float64{rand.Float64() * 20, rand.Float64() * 20}
+		pts = append(pts, p)
+		index.Insert(p, p, i)
+	}
+	ring := [][2]float64{{5, 5}, {15, 5}, {10, 15}, {5, 5}}
+	pred := Polygon(ring)
+	min, max := Bounds(ring)
+	seen := make(map[interface{}]bool)
+	index.SearchFiltered(min, max, pred,
+		func(min, max [2]float64, data interface{}) bool {
+			seen[data] = true
+			return true
+		},
+	)
+	for i, p := range pts {
+		if seen[i] != pointInRing(p, ring) {
+			t.Fatalf("%v: expected %v", p, !seen[i])
+		}
+	}
+	if len(seen) == 0 {
+		t.Fatal("expected items")
+	}
+}
@@ -0,0 +1,98 @@
+package geoindex
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/tidwall/cities"
+)
+
+// The Gen functions generate items with realistic spatial distributions for
+// tests and benchmarks. The coordinates are lon,lat degrees, and the Data of
+// each item is its position in the returned slice. They use the math/rand
+// default source, so the output can be repeated by seeding it.
+
+// GenUniform returns n items that are uniformly distributed around the world.
+// The width and height of each item are random values from zero to size, so
+// a size of zero generates points.
+func GenUniform(n int, size float64) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		min := [2]float64{
+			rand.Float64()*(360-size) - 180,
+			rand.Float64()*(180-size) - 90,
+		}
+		max := [2]float64{
+			min[0] + rand.Float64()*size,
+			min[1] + rand.Float64()*size,
+		}
+		items[i] = Item{min, max, i}
+	}
+	return items
+}
+
+// GenClustered returns n points that are normally distributed around a number
+// of uniformly distributed cluster centers, where sigma is the standard
+// deviation in degrees.
+func GenClustered(n, clusters int, sigma float64) []Item {
+	centers := GenUniform(clusters, 0)
+	items := make([]Item, n)
+	for i := range items {
+		c := centers[rand.Intn(len(centers))].Min
+		p := genClamp([2]float64{
+			c[0] + rand.NormFloat64()*sigma,
+			c[1] + rand.NormFloat64()*sigma,
+		})
+		items[i] = Item{p, p, i}
+	}
+	return items
+}
+
+// GenRoadsLike returns n items that are the bounding boxes of the segments of
+// winding roads. Each road is a random walk of up to 200 segments that are
+// about a kilometer long, which generates long, thin, and overlapping
+// clusters of small boxes.
+func GenRoadsLike(n int) []Item {
+	items := make([]Item, 0, n)
+	for len(items) < n {
+		p := GenUniform(1, 0)[0].Min
+		heading := rand.Float64() * 2 * math.Pi
+		segs := 1 + rand.Intn(200)
+		for j := 0; j < segs && len(items) < n; j++ {
+			heading += rand.NormFloat64() * 0.3
+			step := 0.005 + rand.Float64()*0.01
+			q := genClamp([2]float64{
+				p[0] + math.Cos(heading)*step,
+				p[1] + math.Sin(heading)*step,
+			})
+			items = append(items, Item{
+				[2]float64{math.Min(p[0], q[0]), math.Min(p[1], q[1])},
+				[2]float64{math.Max(p[0], q[0]), math.Max(p[1], q[1])},
+				len(items),
+			})
+			p = q
+		}
+	}
+	return items
+}
+
+// GenCities returns n points that are the locations of real world cities.
+// The cities are repeated when n is larger than the number of cities.
+func GenCities(n int) []Item {
+	items := make([]Item, n)
+	perm := rand.Perm(len(cities.Cities))
+	for i := range items {
+		city := cities.Cities[perm[i%len(perm)]]
+		p := [2]float64{city.Longitude, city.Latitude}
+		items[i] = Item{p, p, i}
+	}
+	return items
+}
+
+// genClamp returns the point clamped to the bounds of the world
+func genClamp(p [2]float64) [2]float64 {
+	return [2]float64{
+		math.Max(-180, math.Min(180, p[0])),
+		math.Max(-90, math.Min(90, p[1])),
+	}
+}
@@ -0,0 +1,57 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGen(t *testing.T) {
+	world := [2][2]float64{{-180, -90}, {180, 90}}
+	for _, gen := range []struct {
+		name  string
+		items []Item
+		size  float64 // the largest expected width and height
+	}{
+		{"uniform", GenUniform(1000, 0), 0},
+		{"uniform-boxes", GenUniform(1000, 2), 2},
+		{"clustered", GenClustered(1000, 10, 1), 0},
+		{"roads", GenRoadsLike(1000), 0.015},
+		{"cities", GenCities(20000), 0},
+	} {
+		if len(gen.items) != 1000 && gen.name != "cities" {
+			t.Fatalf("%s: expected %d, got %d", gen.name, 1000, len(gen.items))
+		}
+		for i, item := range gen.items {
+			if item.Data != i {
+				t.Fatalf("%s: expected %d, got %v", gen.name, i, item.Data)
+			}
+			if !contains(world[0], world[1], item.Min, item.Max) {
+				t.Fatalf("%s: %v %v is outside of the world",
+					gen.name, item.Min, item.Max)
+			}
+			if item.Min[0] > item.Max[0] || item.Min[1] > item.Max[1] ||
+				item.Max[0]-item.Min[0] > gen.size ||
+				item.Max[1]-item.Min[1] > gen.size {
+				t.Fatalf("%s: unexpected box %v %v",
+					gen.name, item.Min, item.Max)
+			}
+		}
+	}
+	// the points of a cluster are close to each other
+	items := GenClustered(1000, 1, 1)
+	var sum [2]float64
+	for _, item := range items {
+		sum[0] += item.Min[0]
+		sum[1] += item.Min[1]
+	}
+	mean := [2]float64{sum[0] / 1000, sum[1] / 1000}
+	var far int
+	for _, item := range items {
+		if math.Hypot(item.Min[0]-mean[0], item.Min[1]-mean[1]) > 5 {
+			far++
+		}
+	}
+	if far > 10 {
+		t.Fatalf("expected at most %d far points, got %d", 10, far)
+	}
+}
@@ -0,0 +1,76 @@
+package geoindex
+
+import "errors"
+
+// ErrBadGeohash is returned when a geohash is empty or has a character that's
+// not in the geohash alphabet.
+var ErrBadGeohash = errors.New("geoindex: bad geohash")
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+var geohashValues = func() (values [256]int8) {
+	for i := range values {
+		values[i] = -1
+	}
+	for i := 0; i < len(geohashAlphabet); i++ {
+		values[geohashAlphabet[i]] = int8(i)
+		if c := geohashAlphabet[i]; c >= 'a' && c <= 'z' {
+			values[c-'a'+'A'] = int8(i)
+		}
+	}
+	return values
+}()
+
+// GeohashBox returns the lon,lat bounding box of a geohash cell.
+func GeohashBox(hash string) (min, max [2]float64, err error) {
+	if len(hash) == 0 {
+		return min, max, ErrBadGeohash
+	}
+	min, max = [2]float64{-180, -90}, [2]float64{180, 90}
+	axis := 0 // the bits alternate between longitude and latitude
+	for i := 0; i < len(hash); i++ {
+		v := geohashValues[hash[i]]
+		if v < 0 {
+			return [2]float64{}, [2]float64{}, ErrBadGeohash
+		}
+		for bit := 4; bit >= 0; bit-- {
+			mid := (min[axis] + max[axis]) / 2
+			if v&(1<<bit) != 0 {
+				min[axis] = mid
+			} else {
+				max[axis] = mid
+			}
+			axis = 1 - axis
+		}
+	}
+	return min, max, nil
+}
+
+// GeohashBoxes returns the lon,lat bounding boxes of a list of geohash cells,
+// in the same order as the cells.
+func GeohashBoxes(hashes []string) ([][2][2]float64, error) {
+	boxes := make([][2][2]float64, len(hashes))
+	for i, hash := range hashes {
+		min, max, err := GeohashBox(hash)
+		if err != nil {
+			return nil, err
+		}
+		boxes[i] = [2][2]float64{min, max}
+	}
+	return boxes, nil
+}
+
+// SearchGeohash searches the index for items that intersects a geohash cell,
+// like Search with the bounding box of the cell. Returns ErrBadGeohash when
+// the geohash is not valid.
+func (index *Index) SearchGeohash(
+	hash string,
+	iter func(min, max [2]float64, data interface{}) bool,
+) error {
+	min, max, err := GeohashBox(hash)
+	if err != nil {
+		return err
+	}
+	index.Search(min, max, iter)
+	return nil
+}
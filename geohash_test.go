@@ -0,0 +1,69 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestGeohashBox(t *testing.T) {
+	// the example from the geohash wikipedia page
+	min, max, err := GeohashBox("ezs42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectMin := [2]float64{-5.625, 42.5830078125}
+	expectMax := [2]float64{-5.5810546875, 42.626953125}
+	for i := 0; i < 2; i++ {
+		if math.Abs(min[i]-expectMin[i]) > 1e-9 ||
+			math.Abs(max[i]-expectMax[i]) > 1e-9 {
+			t.Fatalf("expected %v %v, got %v %v",
+				expectMin, expectMax, min, max)
+		}
+	}
+	if min2, max2, err := GeohashBox("EZS42"); err != nil || min2 != min ||
+		max2 != max {
+		t.Fatalf("expected %v %v, got %v %v (%v)", min, max, min2, max2, err)
+	}
+	for _, hash := range []string{"", "ezs4a", "ezs4i", "ezs4l", "ezs4o"} {
+		if _, _, err := GeohashBox(hash); err != ErrBadGeohash {
+			t.Fatalf("%q: expected %v, got %v", hash, ErrBadGeohash, err)
+		}
+	}
+	boxes, err := GeohashBoxes([]string{"0", "z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if boxes[0] != [2][2]float64{{-180, -90}, {-135, -45}} ||
+		boxes[1] != [2][2]float64{{135, 45}, {180, 90}} {
+		t.Fatalf("unexpected %v", boxes)
+	}
+	if _, err := GeohashBoxes([]string{"0", "a"}); err != ErrBadGeohash {
+		t.Fatalf("expected %v, got %v", ErrBadGeohash, err)
+	}
+}
+
+func TestSearchGeohash(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	min, max, _ := GeohashBox("u")
+	var expect, got int
+	index.Search(min, max, func(_, _ [2]float64, _ interface{}) bool {
+		expect++
+		return true
+	})
+	err := index.SearchGeohash("u", func(_, _ [2]float64, _ interface{}) bool {
+		got++
+		return true
+	})
+	if err != nil || got != expect || got == 0 {
+		t.Fatalf("expected %d, got %d (%v)", expect, got, err)
+	}
+	if err := index.SearchGeohash("!", nil); err != ErrBadGeohash {
+		t.Fatalf("expected %v, got %v", ErrBadGeohash, err)
+	}
+}
@@ -1,8 +1,6 @@
 package geoindex
 
 import (
-	"fmt"
-
 	"github.com/tidwall/geoindex/child"
 )
 
@@ -42,20 +40,23 @@ type Interface interface {
 // This can be created like such:
 //
 //	var tree = &rtree.RTree{}
-//	var index = index.Index{tree}
+//	var index = geoindex.Wrap(tree)
 //
 // Now you can use `index` just like tree but with the extra features.
 type Index struct {
-	tree Interface
+	stats opStats
+	tree  Interface
+	crs   CRS
 }
 
 // Wrap a tree-like geospatial interface.
 func Wrap(tree Interface) *Index {
-	return &Index{tree}
+	return &Index{tree: tree}
 }
 
 // Insert an item into the index
 func (index *Index) Insert(min, max [2]float64, data interface{}) {
+	defer index.stats.end(opInsert, index.stats.begin())
 	index.tree.Insert(min, max, data)
 }
 
@@ -64,12 +65,103 @@ func (index *Index) Search(
 	min, max [2]float64,
 	iter func(min, max [2]float64, data interface{}) bool,
 ) {
+	defer index.stats.end(opSearch, index.stats.begin())
 	index.tree.Search(min, max, iter)
 }
 
-// Delete an item from the index
-func (index *Index) Delete(min, max [2]float64, data interface{}) {
+// OneDeleter is an optional interface for a tree that can report whether an
+// item was deleted.
+type OneDeleter interface {
+	// DeleteOne deletes one item that matches the box and data, and returns
+	// true when an item was deleted. Only one item is deleted when the tree
+	// has many items with the same box and data.
+	DeleteOne(min, max [2]float64, data interface{}) bool
+}
+
+// ReportingEditor is an optional interface for a tree that reports whether
+// the old item existed for both Delete and Replace. The delete is the
+// DeleteOne of OneDeleter rather than a separate DeleteR method, so that a
+// tree has a single delete that reports its result, and Index.Delete only
+// needs to check for OneDeleter.
+type ReportingEditor interface {
+	OneDeleter
+	// ReplaceR is like Replace, and returns true when the old item was
+	// deleted. The new item is inserted either way.
+	ReplaceR(
+		oldMin, oldMax [2]float64, oldData interface{},
+		newMin, newMax [2]float64, newData interface{},
+	) (deleted bool)
+}
+
+// Delete an item from the index. Returns true when an item was deleted. When
+// the tree has many items with the same box and data, only one of them is
+// deleted. For a tree that's not a OneDeleter, the result comes from
+// comparing the Len before and after the delete.
+func (index *Index) Delete(min, max [2]float64, data interface{}) bool {
+	defer index.stats.end(opDelete, index.stats.begin())
+	if tr, ok := index.tree.(OneDeleter); ok {
+		return tr.DeleteOne(min, max, data)
+	}
+	n := index.tree.Len()
 	index.tree.Delete(min, max, data)
+	return index.tree.Len() < n
+}
+
+// FastReplacer is an optional interface for a tree that can replace an item
+// without a delete and insert, such as when the new box stays within the
+// same leaf.
+type FastReplacer interface {
+	// ReplaceInPlace replaces the item and returns true, or returns false
+	// without changing the tree when the item cannot be replaced in place.
+	ReplaceInPlace(
+		oldMin, oldMax [2]float64, oldData interface{},
+		newMin, newMax [2]float64, newData interface{},
+	) bool
+}
+
+// Replace an item in the index, and returns true when the old item was
+// deleted. The new item is inserted either way, so a false result means that
+// the index has grown by one item. When the wrapped tree is a FastReplacer
+// then its ReplaceInPlace is tried first, otherwise the ReplaceR of a
+// ReportingEditor or the Replace of the tree is used. For a tree that's not
+// a ReportingEditor, the result comes from comparing the Len before and
+// after the replace.
+func (index *Index) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) (deleted bool) {
+	defer index.stats.end(opReplace, index.stats.begin())
+	if tr, ok := index.tree.(FastReplacer); ok &&
+		tr.ReplaceInPlace(oldMin, oldMax, oldData, newMin, newMax, newData) {
+		return true
+	}
+	if tr, ok := index.tree.(ReportingEditor); ok {
+		return tr.ReplaceR(oldMin, oldMax, oldData, newMin, newMax, newData)
+	}
+	n := index.tree.Len()
+	index.tree.Replace(oldMin, oldMax, oldData, newMin, newMax, newData)
+	return index.tree.Len() == n
+}
+
+// DeleteWhere deletes the items that intersect the rect param and for which
+// the pred function returns true, and returns the number of deleted items.
+// The matching items are collected first and deleted afterwards, so the tree
+// is not modified while it's being searched.
+func (index *Index) DeleteWhere(
+	min, max [2]float64,
+	pred func(min, max [2]float64, data interface{}) bool,
+) int {
+	var items []Item
+	index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		if pred(min, max, data) {
+			items = append(items, Item{min, max, data})
+		}
+		return true
+	})
+	for _, item := range items {
+		index.Delete(item.Min, item.Max, item.Data)
+	}
+	return len(items)
 }
 
 // Children returns all children for parent node. If parent node is nil
@@ -101,39 +193,295 @@ func (index *Index) Nearby(
 	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
 	iter func(min, max [2]float64, data interface{}, dist float64) bool,
 ) {
+	defer index.stats.end(opNearby, index.stats.begin())
 	if tr, ok := index.tree.(treeNearby); ok {
 		tr.Nearby(algo, iter)
 		return
 	}
-	var q queue
-	var parent interface{}
-	var children []child.Child
+	index.nearbyAux(
+		func(min, max [2]float64, data interface{}, item bool) (
+			dist float64, aux interface{},
+		) {
+			return algo(min, max, data, item), nil
+		},
+		func(min, max [2]float64, data interface{}, dist float64,
+			aux interface{},
+		) bool {
+			return iter(min, max, data, dist)
+		},
+	)
+}
+
+// NearbyExclude is like Nearby but skips the items for which the exclude
+// function returns true. The skipped items are never passed to iter, so they
+// don't count towards the number of items that the caller is collecting, such
+// as when finding the nearest drivers that have not already been assigned.
+//
+//	assigned := map[interface{}]bool{driver1: true, driver2: true}
+//	index.NearbyExclude(
+//		func(data interface{}) bool { return assigned[data] },
+//		algo, iter,
+//	)
+func (index *Index) NearbyExclude(
+	exclude func(data interface{}) bool,
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	index.Nearby(algo,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			if exclude(data) {
+				return true
+			}
+			return iter(min, max, data, dist)
+		},
+	)
+}
+
+// Item is an item for an Interface.
+type Item struct {
+	Min, Max [2]float64
+	Data     interface{}
+}
+
+// Result is an item that was returned by a kNN-type operation, along with its
+// distance from the target.
+type Result struct {
+	Min, Max [2]float64
+	Data     interface{}
+	Dist     float64
+}
+
+// NearbyAll performs a kNN-type operation on the index and appends every item
+// to buf, from the smallest dist to the largest dist. The buf may be a
+// previous result that's been truncated to zero, to avoid extra allocations.
+// See Nearby for a description of the `algo` function.
+func (index *Index) NearbyAll(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	buf []Result,
+) []Result {
+	index.Nearby(algo,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			buf = append(buf, Result{min, max, data, dist})
+			return true
+		},
+	)
+	return buf
+}
+
+// NearbyAux is like Nearby but the `algo` function may also return auxiliary
+// data, such as the closest point on a segment item, which is calculated
+// while determining the distance. This aux value is passed to the `iter`
+// function along with its item, saving the caller from having to calculate it
+// a second time.
+// Unlike Nearby, this function always uses the Children of the wrapped tree,
+// even when the tree provides its own Nearby.
+func (index *Index) NearbyAux(
+	algo func(min, max [2]float64, data interface{}, item bool) (
+		dist float64, aux interface{},
+	),
+	iter func(min, max [2]float64, data interface{}, dist float64,
+		aux interface{}) bool,
+) {
+	defer index.stats.end(opNearby, index.stats.begin())
+	index.nearbyAux(algo, iter)
+}
+
+func (index *Index) nearbyAux(
+	algo func(min, max [2]float64, data interface{}, item bool) (
+		dist float64, aux interface{},
+	),
+	iter func(min, max [2]float64, data interface{}, dist float64,
+		aux interface{}) bool,
+) {
+	q := getQueue()
+	defer putQueue(q)
+	index.nearbyQueue(q, algo, iter)
+}
+
+func (index *Index) nearbyQueue(q *Queue,
+	algo func(min, max [2]float64, data interface{}, item bool) (
+		dist float64, aux interface{},
+	),
+	iter func(min, max [2]float64, data interface{}, dist float64,
+		aux interface{}) bool,
+) {
+	it := &NearbyIterator{index: index, algo: algo, buf: q}
 	for {
-		// gather all children for parent
-		children = index.tree.Children(parent, children[:0])
-		for _, child := range children {
-			q.push(qnode{
-				dist:  algo(child.Min, child.Max, child.Data, child.Item),
+		node, ok := it.next()
+		if !ok || !iter(node.child.Min, node.child.Max, node.child.Data,
+			node.dist, node.aux) {
+			return
+		}
+	}
+}
+
+// NearbyReuse performs a kNN-type operation on the index, like Nearby, using
+// the provided Queue rather than allocating a new one. The queue is Reset
+// when the operation is done, so that it doesn't hold on to any items, and
+// it's ready for the next operation. A Queue must not be used by two
+// operations at the same time.
+// See Nearby for a description of the `algo` and `iter` functions. Like
+// NearbyAux, this function always uses the Children of the wrapped tree.
+func (index *Index) NearbyReuse(q *Queue,
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	defer index.stats.end(opNearby, index.stats.begin())
+	q.Reset()
+	defer q.Reset()
+	index.nearbyQueue(q,
+		func(min, max [2]float64, data interface{}, item bool) (
+			dist float64, aux interface{},
+		) {
+			return algo(min, max, data, item), nil
+		},
+		func(min, max [2]float64, data interface{}, dist float64,
+			aux interface{},
+		) bool {
+			return iter(min, max, data, dist)
+		},
+	)
+}
+
+// NearbyIterator returns the items of a kNN-type operation one at a time,
+// from the smallest dist to the largest dist. The queue of the operation is
+// kept between calls to Next, so the caller may stop at any point and
+// continue later without recalculating the items that were already returned.
+// The iterator should not be used after the index has been modified.
+// Call Close when done with an iterator that has not returned all items.
+type NearbyIterator struct {
+	index *Index
+	algo  func(min, max [2]float64, data interface{}, item bool) (
+		dist float64, aux interface{},
+	)
+	buf     *Queue
+	started bool
+	closed  bool
+	// results and done are used when the items come from the Nearby of the
+	// wrapped tree, which runs in its own goroutine.
+	results chan Result
+	done    chan struct{}
+}
+
+// NearbyIterator returns an iterator for a kNN-type operation on the index.
+// See Nearby for a description of the `algo` function. Like NearbyAux, the
+// iterator always uses the Children of the wrapped tree.
+func (index *Index) NearbyIterator(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) *NearbyIterator {
+	return &NearbyIterator{index: index, buf: &Queue{},
+		algo: func(min, max [2]float64, data interface{}, item bool) (
+			dist float64, aux interface{},
+		) {
+			return algo(min, max, data, item), nil
+		},
+	}
+}
+
+// NearbyIter returns an iterator for a kNN-type operation on the index,
+// allowing for the caller to pull the items one at a time, such as for
+// merging the results from two indexes. See Nearby for a description of the
+// `algo` function.
+// Unlike NearbyIterator, this uses the Nearby of the wrapped tree when it
+// provides one, which runs in a separate goroutine until the iterator has
+// returned all items or until Close is called.
+func (index *Index) NearbyIter(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) *NearbyIterator {
+	tr, ok := index.tree.(treeNearby)
+	if !ok {
+		return index.NearbyIterator(algo)
+	}
+	it := &NearbyIterator{index: index,
+		results: make(chan Result),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(it.results)
+		tr.Nearby(algo,
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				select {
+				case it.results <- Result{min, max, data, dist}:
+					return true
+				case <-it.done:
+					return false
+				}
+			},
+		)
+	}()
+	return it
+}
+
+// Next returns the next nearest item. Returns false when there are no more
+// items or when the iterator has been closed.
+func (it *NearbyIterator) Next() (
+	min, max [2]float64, data interface{}, dist float64, ok bool,
+) {
+	if it.results != nil {
+		if it.closed {
+			return min, max, nil, 0, false
+		}
+		r, ok := <-it.results
+		return r.Min, r.Max, r.Data, r.Dist, ok
+	}
+	node, ok := it.next()
+	if !ok {
+		return min, max, nil, 0, false
+	}
+	return node.child.Min, node.child.Max, node.child.Data, node.dist, true
+}
+
+// Close releases the resources of the iterator. Following calls to Next will
+// return false.
+func (it *NearbyIterator) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	if it.results != nil {
+		close(it.done)
+		// wait for the Nearby goroutine to finish
+		for range it.results {
+		}
+		return
+	}
+	it.buf = nil
+}
+
+func (it *NearbyIterator) next() (qnode, bool) {
+	if it.closed {
+		return qnode{}, false
+	}
+	if !it.started {
+		it.buf.children = it.index.tree.Children(nil, it.buf.children[:0])
+		it.started = true
+	}
+	for {
+		for _, child := range it.buf.children {
+			dist, aux := it.algo(child.Min, child.Max, child.Data,
+				child.Item)
+			it.buf.q.push(qnode{
+				dist:  dist,
+				aux:   aux,
 				child: child,
 			})
 		}
-		for {
-			node, ok := q.pop()
-			if !ok {
-				// nothing left in queue
-				return
-			}
-			if node.child.Item {
-				if !iter(node.child.Min, node.child.Max,
-					node.child.Data, node.dist) {
-					return
-				}
-			} else {
-				// gather more children
-				parent = node.child.Data
-				break
-			}
+		it.buf.children = it.buf.children[:0]
+		if it.closed {
+			// closed by the algo, such as by NearbyCtx
+			return qnode{}, false
+		}
+		node, ok := it.buf.q.pop()
+		if !ok {
+			// nothing left in queue
+			return qnode{}, false
+		}
+		if node.child.Item {
+			return node, true
 		}
+		// gather more children
+		it.buf.children = it.index.tree.Children(node.child.Data,
+			it.buf.children)
 	}
 }
 
@@ -151,59 +499,23 @@ func (index *Index) Bounds() (min, max [2]float64) {
 func (index *Index) Scan(
 	iter func(min, max [2]float64, data interface{}) bool,
 ) {
+	defer index.stats.end(opScan, index.stats.begin())
 	index.tree.Scan(iter)
 }
 
-func (index *Index) svg(child child.Child, height int) []byte {
-	var out []byte
-
-	if !child.Item {
-		out = append(out, fmt.Sprintf(
-			"<rect x=\"%.0f\" y=\"%.0f\" width=\"%.0f\" height=\"%.0f\" "+
-				"stroke=\"%s\" fill-opacity=\"0\" stroke-opacity=\"1\"/>\n",
-			(child.Min[0])*svgScale,
-			(child.Min[1])*svgScale,
-			(child.Max[0]-child.Min[0]+1/svgScale)*svgScale,
-			(child.Max[1]-child.Min[1]+1/svgScale)*svgScale,
-			strokes[height%len(strokes)])...)
-		children := index.tree.Children(child.Data, nil)
-		for _, child := range children {
-			out = append(out, index.svg(child, height+1)...)
+// SearchFiltered searches the index for items that intersect the rect param
+// and for which the pred function returns true, such as an exact check of
+// the items against a polygon with the filter package. The items that don't
+// match are not passed to iter.
+func (index *Index) SearchFiltered(
+	min, max [2]float64,
+	pred func(min, max [2]float64, data interface{}) bool,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		if !pred(min, max, data) {
+			return true
 		}
-	} else {
-		out = append(out, fmt.Sprintf(
-			"<rect x=\"%.0f\" y=\"%.0f\" width=\"%.0f\" height=\"%.0f\" "+
-				"stroke=\"%s\" fill-opacity=\"0\" stroke-opacity=\"1\"/>\n",
-			(child.Min[0])*svgScale,
-			(child.Min[1])*svgScale,
-			(child.Max[0]-child.Min[0]+1/svgScale)*svgScale,
-			(child.Max[1]-child.Min[1]+1/svgScale)*svgScale,
-			strokes[len(strokes)-1])...)
-	}
-	return out
-}
-
-const svgScale = 5.0
-
-var strokes = [...]string{"purple", "red", "#009900", "#cccc00", "black"}
-
-// SVG prints 2D rtree in wgs84 coordinate space
-func (index *Index) SVG() string {
-	var out string
-	out += fmt.Sprintf("<svg viewBox=\"%.0f %.0f %.0f %.0f\" "+
-		"xmlns =\"http://www.w3.org/2000/svg\">\n",
-		-190.0*svgScale, -100.0*svgScale,
-		380.0*svgScale, 190.0*svgScale)
-
-	out += fmt.Sprintf("<g transform=\"scale(1,-1)\">\n")
-
-	var outb []byte
-	for _, child := range index.Children(nil, nil) {
-		outb = append(outb, index.svg(child, 1)...)
-	}
-
-	out += string(outb)
-	out += fmt.Sprintf("</g>\n")
-	out += fmt.Sprintf("</svg>\n")
-	return out
+		return iter(min, max, data)
+	})
 }
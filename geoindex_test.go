@@ -2,9 +2,12 @@ package geoindex
 
 import (
 	"math/rand"
+	"sort"
 	"testing"
 	"time"
 
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/child"
 	"github.com/tidwall/geoindex/internal"
 )
 
@@ -33,8 +36,466 @@ func TestGeoIndex(t *testing.T) {
 	t.Run("RandomSVG", func(t *testing.T) {
 		Tests.TestRandomSVG(t, &internal.RTree{})
 	})
+	t.Run("CitiesKNN", func(t *testing.T) {
+		Tests.TestCitiesKNN(t, &internal.RTree{})
+	})
+	t.Run("DatelinePoles", func(t *testing.T) {
+		Tests.TestDatelinePoles(t, &internal.RTree{})
+	})
+	t.Run("ConcurrentReadWrite", func(t *testing.T) {
+		Tests.TestConcurrentReadWrite(t, &internal.RTree{}, 10000)
+	})
+	t.Run("NearbyCorrectness", func(t *testing.T) {
+		Tests.TestNearbyCorrectness(t, &internal.RTree{}, 10000)
+	})
+	t.Run("EdgeOfWorld", func(t *testing.T) {
+		Tests.TestEdgeOfWorld(t, &internal.RTree{})
+	})
+	t.Run("Operations", func(t *testing.T) {
+		Tests.TestOperations(t, func() Interface { return &internal.RTree{} })
+	})
+	t.Run("ChildrenInvariants", func(t *testing.T) {
+		Tests.TestChildrenInvariants(t, &internal.RTree{}, 10000)
+	})
+	t.Run("Duplicates", func(t *testing.T) {
+		Tests.TestDuplicates(t, &internal.RTree{})
+		Tests.TestDuplicates(t, struct{ Interface }{&internal.RTree{}})
+	})
+}
+
+func FuzzOperations(f *testing.F) {
+	Tests.FuzzOperations(f, func() Interface { return &internal.RTree{} })
 }
 
 func BenchmarkRandomInsert(b *testing.B) {
 	Tests.BenchmarkRandomInsert(b, &internal.RTree{})
 }
+
+func BenchmarkWorkloads(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		fn   func(b *testing.B, tr Interface, dataset string)
+	}{
+		{"Search", Tests.BenchmarkSearch},
+		{"Nearby", Tests.BenchmarkNearby},
+		{"Delete", Tests.BenchmarkDelete},
+		{"MixedWorkload", Tests.BenchmarkMixedWorkload},
+	} {
+		for _, dataset := range BenchmarkDatasets {
+			b.Run(bench.name+"/"+dataset, func(b *testing.B) {
+				bench.fn(b, &internal.RTree{}, dataset)
+			})
+		}
+	}
+}
+
+func TestNearbyAux(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		index.Insert(p, p, i)
+	}
+	var count int
+	index.NearbyAux(
+		func(min, max [2]float64, data interface{}, item bool) (
+			float64, interface{},
+		) {
+			if item {
+				return min[0], data.(int) * 2
+			}
+			return min[0], nil
+		},
+		func(min, max [2]float64, data interface{}, dist float64,
+			aux interface{},
+		) bool {
+			if aux.(int) != data.(int)*2 || dist != float64(count) {
+				t.Fatalf("unexpected aux %v for %v", aux, data)
+			}
+			count++
+			return true
+		},
+	)
+	if count != 100 {
+		t.Fatalf("expected %d, got %d", 100, count)
+	}
+}
+
+func TestNearbyIterator(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{rand.Float64() * 100, rand.Float64() * 100}
+		index.Insert(p, p, i)
+	}
+	algo := func(min, max [2]float64, data interface{}, item bool) float64 {
+		return min[0]*min[0] + min[1]*min[1]
+	}
+	var expect []interface{}
+	index.Nearby(algo,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			expect = append(expect, data)
+			return true
+		},
+	)
+	it := index.NearbyIterator(algo)
+	var got []interface{}
+	for len(got) < len(expect) {
+		// take twenty at a time
+		for i := 0; i < 20; i++ {
+			_, _, data, _, ok := it.Next()
+			if !ok {
+				break
+			}
+			got = append(got, data)
+		}
+	}
+	if _, _, _, _, ok := it.Next(); ok {
+		t.Fatal("expected no more items")
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Fatalf("result %d: expected %v, got %v", i, expect[i], got[i])
+		}
+	}
+	if _, _, _, _, ok := Wrap(&internal.RTree{}).NearbyIterator(algo).
+		Next(); ok {
+		t.Fatal("expected no items")
+	}
+}
+
+// nativeTree is an RTree that provides its own Nearby
+type nativeTree struct {
+	internal.RTree
+}
+
+func (tr *nativeTree) Nearby(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	Wrap(&tr.RTree).Nearby(algo, iter)
+}
+
+func TestNearbyIter(t *testing.T) {
+	a, b := Wrap(&internal.RTree{}), Wrap(&nativeTree{})
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{rand.Float64() * 100, rand.Float64() * 100}
+		if i%2 == 0 {
+			a.Insert(p, p, i)
+		} else {
+			b.Insert(p, p, i)
+		}
+	}
+	target := [2]float64{50, 50}
+	calc := algo.Box(target, target, false, nil)
+	// merge the two streams
+	ita, itb := a.NearbyIter(calc), b.NearbyIter(calc)
+	_, _, da, distA, okA := ita.Next()
+	_, _, db, distB, okB := itb.Next()
+	var got []interface{}
+	last := -1.0
+	for okA || okB {
+		if okA && (!okB || distA <= distB) {
+			if distA < last {
+				t.Fatal("out of order")
+			}
+			got, last = append(got, da), distA
+			_, _, da, distA, okA = ita.Next()
+		} else {
+			if distB < last {
+				t.Fatal("out of order")
+			}
+			got, last = append(got, db), distB
+			_, _, db, distB, okB = itb.Next()
+		}
+	}
+	if len(got) != 1000 {
+		t.Fatalf("expected %d, got %d", 1000, len(got))
+	}
+	ita.Close()
+	itb.Close()
+
+	// close early
+	for _, index := range []*Index{a, b} {
+		it := index.NearbyIter(calc)
+		if _, _, _, _, ok := it.Next(); !ok {
+			t.Fatal("expected an item")
+		}
+		it.Close()
+		it.Close()
+		if _, _, _, _, ok := it.Next(); ok {
+			t.Fatal("expected no items")
+		}
+	}
+}
+
+func TestNearbyAll(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		index.Insert(p, p, i)
+	}
+	algo := func(min, max [2]float64, data interface{}, item bool) float64 {
+		return min[0]
+	}
+	buf := index.NearbyAll(algo, nil)
+	if len(buf) != 100 {
+		t.Fatalf("expected %d, got %d", 100, len(buf))
+	}
+	for i, r := range buf {
+		if r.Data != i || r.Dist != float64(i) || r.Min != r.Max ||
+			r.Min[0] != float64(i) {
+			t.Fatalf("unexpected result %d: %v", i, r)
+		}
+	}
+	buf2 := index.NearbyAll(algo, buf[:0])
+	if len(buf2) != 100 || &buf2[0] != &buf[0] {
+		t.Fatal("expected the buffer to be reused")
+	}
+}
+
+func TestNearbyWeighted(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randPoints(10000)
+	weights := make([]float64, len(boxes))
+	for i, box := range boxes {
+		weights[i] = 1 + float64(i%5)
+		index.Insert(box.min, box.max, i)
+	}
+	target := [2]float64{20, 20}
+	expect := make([]float64, len(boxes))
+	for i, box := range boxes {
+		expect[i] = algo.BoxDistCalc(target, target, box.min, box.max,
+			false) / weights[i]
+	}
+	sort.Float64s(expect)
+	var got []float64
+	index.Nearby(
+		algo.Weighted(algo.Box(target, target, false, nil),
+			func(min, max [2]float64, data interface{}) float64 {
+				return weights[data.(int)]
+			}, 5,
+		),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			got = append(got, dist)
+			return len(got) < 100
+		},
+	)
+	for i := range got {
+		if got[i] != expect[i] {
+			t.Fatalf("result %d: expected %v, got %v", i, expect[i], got[i])
+		}
+	}
+}
+
+func TestNearbyExclude(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		index.Insert(p, p, i)
+	}
+	assigned := map[interface{}]bool{0: true, 2: true, 3: true}
+	var got []int
+	index.NearbyExclude(
+		func(data interface{}) bool { return assigned[data] },
+		algo.Box([2]float64{0, 0}, [2]float64{0, 0}, false, nil),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			got = append(got, data.(int))
+			return len(got) < 3
+		},
+	)
+	if len(got) != 3 || got[0] != 1 || got[1] != 4 || got[2] != 5 {
+		t.Fatalf("unexpected results %v", got)
+	}
+}
+
+func TestDeleteWhere(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	min, max := [2]float64{-90, -45}, [2]float64{90, 45}
+	even := func(min, max [2]float64, data interface{}) bool {
+		return data.(int)%2 == 0
+	}
+	var expect int
+	for i, box := range boxes {
+		if i%2 == 0 && intersects(min, max, box.min, box.max) {
+			expect++
+		}
+	}
+	if n := index.DeleteWhere(min, max, even); n != expect {
+		t.Fatalf("expected %d, got %d", expect, n)
+	}
+	if index.Len() != len(boxes)-expect {
+		t.Fatalf("expected %d, got %d", len(boxes)-expect, index.Len())
+	}
+	index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		if data.(int)%2 == 0 {
+			t.Fatalf("unexpected %v", data)
+		}
+		return true
+	})
+	if n := index.DeleteWhere(min, max, even); n != 0 {
+		t.Fatalf("expected %d, got %d", 0, n)
+	}
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var _ ReportingEditor = &internal.RTree{}
+
+func TestReplace(t *testing.T) {
+	// the tree3 adapter is not a FastReplacer or a ReportingEditor
+	for _, tr := range []Interface{&internal.RTree{}, From3(&tree3{})} {
+		index := Wrap(tr)
+		boxes := randPoints(1000)
+		for i, box := range boxes {
+			index.Insert(box.min, box.max, i)
+		}
+		for i, box := range boxes {
+			// move some items a little and some a lot
+			d := 0.001
+			if i%2 == 0 {
+				d = 100
+			}
+			p := [2]float64{box.min[0] + d, box.min[1]}
+			if !index.Replace(box.min, box.max, i, p, p, i) {
+				t.Fatalf("item %d: expected the old item to be deleted", i)
+			}
+			boxes[i] = tBox{p, p}
+		}
+		if index.Len() != len(boxes) {
+			t.Fatalf("expected %d, got %d", len(boxes), index.Len())
+		}
+		// a missing old item grows the tree
+		p := [2]float64{1, 2}
+		if index.Replace(p, p, -1, p, p, -1) {
+			t.Fatal("expected the old item to be missing")
+		}
+		if index.Len() != len(boxes)+1 || !index.Delete(p, p, -1) {
+			t.Fatalf("expected %d, got %d", len(boxes)+1, index.Len())
+		}
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		for i, box := range boxes {
+			var found bool
+			index.Search(box.min, box.max,
+				func(min, max [2]float64, data interface{}) bool {
+					found = data == i
+					return !found
+				},
+			)
+			if !found {
+				t.Fatalf("item %d not found", i)
+			}
+		}
+	}
+}
+
+func TestNearbyReuse(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(10000) {
+		index.Insert(box.min, box.max, i)
+	}
+	var q Queue
+	for i := 0; i < 10; i++ {
+		p := randPoints(1)[0].min
+		algo := algo.Box(p, p, false, nil)
+		expect := index.NearbyAll(algo, nil)
+		var results []Result
+		index.NearbyReuse(&q, algo,
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				results = append(results, Result{min, max, data, dist})
+				// stop early every other time
+				return i%2 == 0 || len(results) < 100
+			},
+		)
+		if i%2 == 1 {
+			expect = expect[:100]
+		}
+		if len(results) != len(expect) {
+			t.Fatalf("expected %d, got %d", len(expect), len(results))
+		}
+		for j := range results {
+			if results[j].Dist != expect[j].Dist {
+				t.Fatalf("expected %v, got %v", expect[j].Dist, results[j].Dist)
+			}
+		}
+		if q.q.Len() != 0 || len(q.children) != 0 {
+			t.Fatal("expected an empty queue")
+		}
+	}
+	p := [2]float64{10, 10}
+	algo := algo.Box(p, p, false, nil)
+	iter := func(min, max [2]float64, data interface{}, dist float64) bool {
+		return true
+	}
+	reused := testing.AllocsPerRun(10, func() {
+		index.NearbyReuse(&q, algo, iter)
+	})
+	allocs := testing.AllocsPerRun(10, func() {
+		it := index.NearbyIterator(algo)
+		for {
+			if _, _, _, _, ok := it.Next(); !ok {
+				break
+			}
+		}
+	})
+	if reused >= allocs {
+		t.Fatalf("expected fewer than %v allocations, got %v", allocs, reused)
+	}
+}
+
+func TestNearbyPool(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(10000) {
+		index.Insert(box.min, box.max, i)
+	}
+	p := [2]float64{10, 10}
+	algo := algo.Box(p, p, false, nil)
+	iter := func(min, max [2]float64, data interface{}, dist float64) bool {
+		return true
+	}
+	index.Nearby(algo, iter)
+	// the queue and children buffers are reused from the pool
+	if allocs := testing.AllocsPerRun(10, func() {
+		index.Nearby(algo, iter)
+	}); allocs > 5 {
+		t.Fatalf("expected at most %d allocations, got %v", 5, allocs)
+	}
+	q := getQueue()
+	for i := 0; i < maxPooled+1; i++ {
+		q.q.push(qnode{dist: float64(i)})
+	}
+	putQueue(q)
+	buf := getChildren()
+	*buf = append(*buf, child.Child{Data: 1})
+	putChildren(buf)
+	if len(*buf) != 0 {
+		t.Fatal("expected an empty buffer")
+	}
+}
+
+func TestSearchFiltered(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		index.Insert(p, p, i)
+	}
+	even := func(min, max [2]float64, data interface{}) bool {
+		return data.(int)%2 == 0
+	}
+	var found []int
+	index.SearchFiltered([2]float64{10, 10}, [2]float64{19, 19}, even,
+		func(min, max [2]float64, data interface{}) bool {
+			found = append(found, data.(int))
+			return len(found) < 4
+		},
+	)
+	sort.Ints(found)
+	if len(found) != 4 || found[0]%2 != 0 || found[0] < 10 {
+		t.Fatalf("unexpected %v", found)
+	}
+}
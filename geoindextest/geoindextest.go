@@ -0,0 +1,269 @@
+// Package geoindextest provides a conformance test suite for implementations
+// of geoindex.Interface.
+package geoindextest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/tidwall/geoindex"
+	"github.com/tidwall/geoindex/algo"
+)
+
+// Conformance runs the full battery of tests against an implementation of
+// geoindex.Interface, each category as a subtest. The newTree function must
+// return a new empty tree each time it's called.
+//
+//	func TestConformance(t *testing.T) {
+//		geoindextest.Conformance(t, func() geoindex.Interface {
+//			return &RTree{}
+//		})
+//	}
+func Conformance(t *testing.T, newTree func() geoindex.Interface) {
+	t.Run("RandomPoints", func(t *testing.T) {
+		geoindex.Tests.TestRandomPoints(t, newTree(), 10000)
+	})
+	t.Run("RandomRects", func(t *testing.T) {
+		geoindex.Tests.TestRandomRects(t, newTree(), 10000)
+	})
+	t.Run("ZeroPoints", func(t *testing.T) {
+		geoindex.Tests.TestZeroPoints(t, newTree())
+	})
+	t.Run("CitiesKNN", func(t *testing.T) {
+		geoindex.Tests.TestCitiesKNN(t, newTree())
+	})
+	t.Run("DatelinePoles", func(t *testing.T) {
+		geoindex.Tests.TestDatelinePoles(t, newTree())
+	})
+	t.Run("ConcurrentReadWrite", func(t *testing.T) {
+		geoindex.Tests.TestConcurrentReadWrite(t, newTree(), 10000)
+	})
+	t.Run("NearbyCorrectness", func(t *testing.T) {
+		geoindex.Tests.TestNearbyCorrectness(t, newTree(), 10000)
+	})
+	t.Run("EdgeOfWorld", func(t *testing.T) {
+		geoindex.Tests.TestEdgeOfWorld(t, newTree())
+	})
+	t.Run("Operations", func(t *testing.T) {
+		geoindex.Tests.TestOperations(t, newTree)
+	})
+	t.Run("ChildrenInvariants", func(t *testing.T) {
+		geoindex.Tests.TestChildrenInvariants(t, newTree(), 10000)
+	})
+	t.Run("Duplicates", func(t *testing.T) {
+		geoindex.Tests.TestDuplicates(t, newTree())
+	})
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			c.fn(t, newTree())
+		})
+	}
+}
+
+// checks are the tests that only need a testing.TB, which allows for them to
+// be run against a Mock that is expected to fail.
+var checks = []struct {
+	name string
+	fn   func(tb testing.TB, tr geoindex.Interface)
+}{
+	{"Empty", testEmpty},
+	{"Children", func(tb testing.TB, tr geoindex.Interface) {
+		testChildren(tb, tr, 10000)
+	}},
+	{"Bounds", func(tb testing.TB, tr geoindex.Interface) {
+		testBounds(tb, tr, 1000)
+	}},
+	{"Nearby", func(tb testing.TB, tr geoindex.Interface) {
+		testNearby(tb, tr, 1000)
+	}},
+	{"DeleteStress", func(tb testing.TB, tr geoindex.Interface) {
+		testDeleteStress(tb, tr, 5000)
+	}},
+}
+
+type item struct {
+	min, max [2]float64
+	id       int
+}
+
+func randItems(n int) []item {
+	items := make([]item, n)
+	for i := range items {
+		items[i].id = i
+		items[i].min[0] = rand.Float64()*350 - 175
+		items[i].min[1] = rand.Float64()*170 - 85
+		items[i].max = items[i].min
+		if i%2 == 0 {
+			items[i].max[0] += rand.Float64() * 5
+			items[i].max[1] += rand.Float64() * 5
+		}
+	}
+	return items
+}
+
+func insertAll(tr geoindex.Interface, items []item) {
+	for _, item := range items {
+		tr.Insert(item.min, item.max, item)
+	}
+}
+
+func testEmpty(t testing.TB, tr geoindex.Interface) {
+	if tr.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, tr.Len())
+	}
+	if children := tr.Children(nil, nil); len(children) != 0 {
+		t.Fatalf("expected %d, got %d", 0, len(children))
+	}
+	index := geoindex.Wrap(tr)
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	tr.Search([2]float64{-180, -90}, [2]float64{180, 90},
+		func(min, max [2]float64, data interface{}) bool {
+			count++
+			return true
+		},
+	)
+	index.Nearby(algo.Box([2]float64{}, [2]float64{}, false, nil),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			count++
+			return true
+		},
+	)
+	if count != 0 {
+		t.Fatalf("expected %d, got %d", 0, count)
+	}
+}
+
+func testChildren(t testing.TB, tr geoindex.Interface, n int) {
+	items := randItems(n)
+	insertAll(tr, items)
+	index := geoindex.Wrap(tr)
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range items[:n/2] {
+		tr.Delete(item.min, item.max, item)
+	}
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testBounds(t testing.TB, tr geoindex.Interface, n int) {
+	items := randItems(n)
+	for i, item := range items {
+		tr.Insert(item.min, item.max, item)
+		if i%100 != 0 {
+			continue
+		}
+		min, max := items[0].min, items[0].max
+		for _, item := range items[1 : i+1] {
+			for j := 0; j < 2; j++ {
+				if item.min[j] < min[j] {
+					min[j] = item.min[j]
+				}
+				if item.max[j] > max[j] {
+					max[j] = item.max[j]
+				}
+			}
+		}
+		bmin, bmax := tr.Bounds()
+		if bmin != min || bmax != max {
+			t.Fatalf("expected %v %v, got %v %v", min, max, bmin, bmax)
+		}
+	}
+}
+
+func testNearby(t testing.TB, tr geoindex.Interface, n int) {
+	items := randItems(n)
+	insertAll(tr, items)
+	index := geoindex.Wrap(tr)
+	for i := 0; i < 20; i++ {
+		target := [2]float64{rand.Float64()*360 - 180,
+			rand.Float64()*180 - 90}
+		expect := make([]float64, len(items))
+		for i, item := range items {
+			expect[i] = algo.BoxDistCalc(target, target,
+				item.min, item.max, false)
+		}
+		sort.Float64s(expect)
+		var got []float64
+		index.Nearby(algo.Box(target, target, false, nil),
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				item := data.(item)
+				if algo.BoxDistCalc(target, target, item.min, item.max,
+					false) != dist {
+					t.Fatalf("wrong distance for item %d", item.id)
+				}
+				got = append(got, dist)
+				return true
+			},
+		)
+		if len(got) != len(expect) {
+			t.Fatalf("expected %d, got %d", len(expect), len(got))
+		}
+		for i := range got {
+			if got[i] != expect[i] {
+				t.Fatalf("result %d: expected %v, got %v",
+					i, expect[i], got[i])
+			}
+		}
+	}
+}
+
+func testDeleteStress(t testing.TB, tr geoindex.Interface, n int) {
+	items := randItems(n)
+	insertAll(tr, items)
+	index := geoindex.Wrap(tr)
+	live := make([]bool, n)
+	for i := range live {
+		live[i] = true
+	}
+	for round := 0; round < 5; round++ {
+		// delete most items in random order, which causes many underflows
+		for _, i := range rand.Perm(n)[:n*9/10] {
+			if live[i] {
+				tr.Delete(items[i].min, items[i].max, items[i])
+				live[i] = false
+			}
+		}
+		var count int
+		for i := range items {
+			if live[i] {
+				count++
+			}
+		}
+		if tr.Len() != count {
+			t.Fatalf("expected %d, got %d", count, tr.Len())
+		}
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		for i, item := range items {
+			var found bool
+			tr.Search(item.min, item.max,
+				func(min, max [2]float64, data interface{}) bool {
+					if data == item {
+						found = true
+						return false
+					}
+					return true
+				},
+			)
+			if found != live[i] {
+				t.Fatalf("item %d: expected found=%v", i, live[i])
+			}
+		}
+		// reinsert some of them
+		for _, i := range rand.Perm(n)[:n/2] {
+			if !live[i] {
+				tr.Insert(items[i].min, items[i].max, items[i])
+				live[i] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package geoindextest
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestConformance(t *testing.T) {
+	Conformance(t, func() geoindex.Interface {
+		return &internal.RTree{}
+	})
+	// many of the items are in the side list
+	t.Run("OversizeTree", func(t *testing.T) {
+		Conformance(t, func() geoindex.Interface {
+			return geoindex.NewOversizeTree(&internal.RTree{}, 0.5)
+		})
+	})
+}
@@ -0,0 +1,131 @@
+package geoindextest
+
+import (
+	"strconv"
+
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/internal"
+)
+
+// Fault is a class of misbehavior for a Mock.
+type Fault int
+
+const (
+	// NoFault makes the Mock a correct implementation.
+	NoFault Fault = iota
+	// WrongBoxes shrinks the boxes of the nodes returned by Children, so they
+	// no longer contain their children.
+	WrongBoxes
+	// MissingItems hides every tenth inserted item from Search and Children,
+	// while still counting it in Len and Scan.
+	MissingItems
+	// WrongItemFlags reports the items returned by Children as nodes.
+	WrongItemFlags
+)
+
+func (f Fault) String() string {
+	switch f {
+	case NoFault:
+		return "NoFault"
+	case WrongBoxes:
+		return "WrongBoxes"
+	case MissingItems:
+		return "MissingItems"
+	case WrongItemFlags:
+		return "WrongItemFlags"
+	}
+	return "Fault(" + strconv.Itoa(int(f)) + ")"
+}
+
+// Mock is a geoindex.Interface with configurable misbehavior. It's intended
+// for verifying that the Validate method and the Conformance suite detect
+// each class of bug.
+type Mock struct {
+	Fault  Fault
+	tr     internal.RTree
+	hidden map[interface{}]bool
+	count  int
+}
+
+// Insert an item into the tree
+func (m *Mock) Insert(min, max [2]float64, data interface{}) {
+	if m.Fault == MissingItems && m.count%10 == 0 {
+		if m.hidden == nil {
+			m.hidden = make(map[interface{}]bool)
+		}
+		m.hidden[data] = true
+	}
+	m.count++
+	m.tr.Insert(min, max, data)
+}
+
+// Delete an item from the tree
+func (m *Mock) Delete(min, max [2]float64, data interface{}) {
+	m.tr.Delete(min, max, data)
+}
+
+// Replace an item in the tree
+func (m *Mock) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	m.Delete(oldMin, oldMax, oldData)
+	m.Insert(newMin, newMax, newData)
+}
+
+// Search the tree for items that intersects the rect param
+func (m *Mock) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	m.tr.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		if m.hidden[data] {
+			return true
+		}
+		return iter(min, max, data)
+	})
+}
+
+// Scan iterates through all data in tree in no specified order.
+func (m *Mock) Scan(iter func(min, max [2]float64, data interface{}) bool) {
+	m.tr.Scan(iter)
+}
+
+// Len returns the number of items in tree
+func (m *Mock) Len() int {
+	return m.tr.Len()
+}
+
+// Bounds returns the minimum bounding box
+func (m *Mock) Bounds() (min, max [2]float64) {
+	return m.tr.Bounds()
+}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root node is returned.
+func (m *Mock) Children(parent interface{}, reuse []child.Child,
+) []child.Child {
+	children := m.tr.Children(parent, reuse)
+	n := 0
+	for _, c := range children {
+		switch m.Fault {
+		case WrongBoxes:
+			if !c.Item && parent != nil {
+				for i := 0; i < 2; i++ {
+					half := (c.Max[i] - c.Min[i]) / 4
+					c.Min[i] += half
+					c.Max[i] -= half
+				}
+			}
+		case MissingItems:
+			if c.Item && m.hidden[c.Data] {
+				continue
+			}
+		case WrongItemFlags:
+			c.Item = false
+		}
+		children[n] = c
+		n++
+	}
+	return children[:n]
+}
@@ -0,0 +1,78 @@
+package geoindextest
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/tidwall/geoindex"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper()                              {}
+func (r *recorder) Error(args ...interface{})            { r.failed = true }
+func (r *recorder) Errorf(f string, args ...interface{}) { r.failed = true }
+func (r *recorder) Fatal(args ...interface{})            { r.FailNow() }
+func (r *recorder) Fatalf(f string, args ...interface{}) { r.FailNow() }
+func (r *recorder) FailNow() {
+	r.failed = true
+	runtime.Goexit()
+}
+
+// fails runs the check and returns true if it failed or panicked
+func fails(t *testing.T, fn func(tb testing.TB, tr geoindex.Interface),
+	tr geoindex.Interface,
+) bool {
+	r := &recorder{TB: t}
+	done := make(chan bool)
+	go func() {
+		defer func() {
+			if recover() != nil {
+				r.failed = true
+			}
+			close(done)
+		}()
+		fn(r, tr)
+	}()
+	<-done
+	return r.failed
+}
+
+func TestMockNoFault(t *testing.T) {
+	Conformance(t, func() geoindex.Interface { return &Mock{} })
+}
+
+func TestMockFaults(t *testing.T) {
+	for _, fault := range []Fault{WrongBoxes, MissingItems, WrongItemFlags} {
+		t.Run(fmt.Sprint(fault), func(t *testing.T) {
+			m := &Mock{Fault: fault}
+			for _, item := range randItems(1000) {
+				m.Insert(item.min, item.max, item)
+			}
+			if err := geoindex.Wrap(m).Validate(); err == nil {
+				t.Fatal("expected Validate error")
+			}
+			var detected []string
+			for _, c := range checks {
+				if fails(t, c.fn, &Mock{Fault: fault}) {
+					detected = append(detected, c.name)
+				}
+			}
+			if len(detected) == 0 {
+				t.Fatal("expected conformance failure")
+			}
+			var nearby bool
+			for _, name := range detected {
+				nearby = nearby || name == "Nearby"
+			}
+			if !nearby {
+				t.Fatalf("expected the kNN oracle to fail, got %v", detected)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package geoindex
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrBadGeoJSON is returned by LoadGeoJSON when the data is not a valid
+// GeoJSON Feature or FeatureCollection.
+var ErrBadGeoJSON = errors.New("geoindex: bad geojson")
+
+// Feature is a GeoJSON feature that was read by LoadGeoJSON.
+type Feature struct {
+	// ID is the "id" member of the feature, which is nil when the feature
+	// doesn't have an id.
+	ID interface{}
+	// Geometry is the undecoded "geometry" member of the feature.
+	Geometry json.RawMessage
+	// Properties are the decoded "properties" member of the feature.
+	Properties map[string]interface{}
+}
+
+type geojsonObject struct {
+	Type        string                 `json:"type"`
+	ID          interface{}            `json:"id"`
+	BBox        []float64              `json:"bbox"`
+	Geometry    json.RawMessage        `json:"geometry"`
+	Properties  map[string]interface{} `json:"properties"`
+	Features    []json.RawMessage      `json:"features"`
+	Coordinates json.RawMessage        `json:"coordinates"`
+	Geometries  []json.RawMessage      `json:"geometries"`
+}
+
+// LoadGeoJSON loads the features of a GeoJSON FeatureCollection, or of a
+// single Feature, into the tree, where the data of each item is a *Feature.
+// The box of a feature is its "bbox" member, when it has one, otherwise the
+// box is calculated from all the coordinates of its geometry, including
+// every part of a multi geometry or a GeometryCollection. A feature that
+// crosses the antimeridian, which is a bbox with a west that is larger than
+// its east, or an edge of a geometry that spans more than 180 degrees of
+// longitude, is loaded as two items, one on each side of the antimeridian,
+// with the same data.
+// The keep function is called for each feature, and only the features for
+// which it returns true are loaded. When keep is nil then all the features
+// are loaded. Features without a geometry are skipped.
+// Returns the number of features that were loaded. Nothing is loaded when
+// there's an error.
+func LoadGeoJSON(data []byte, tr Interface, keep func(f Feature) bool) (
+	int, error,
+) {
+	var obj geojsonObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return 0, ErrBadGeoJSON
+	}
+	var features []geojsonObject
+	switch obj.Type {
+	case "Feature":
+		features = append(features, obj)
+	case "FeatureCollection":
+		features = make([]geojsonObject, len(obj.Features))
+		for i, data := range obj.Features {
+			if json.Unmarshal(data, &features[i]) != nil ||
+				features[i].Type != "Feature" {
+				return 0, ErrBadGeoJSON
+			}
+		}
+	default:
+		return 0, ErrBadGeoJSON
+	}
+	var items []Item
+	var n int
+	for _, obj := range features {
+		if len(obj.Geometry) == 0 || string(obj.Geometry) == "null" {
+			continue
+		}
+		f := &Feature{obj.ID, obj.Geometry, obj.Properties}
+		if keep != nil && !keep(*f) {
+			continue
+		}
+		var boxes [][2][2]float64
+		if len(obj.BBox) == 4 || len(obj.BBox) == 6 {
+			boxes = geojsonBBox(obj.BBox)
+		} else {
+			var w geojsonWrap
+			if !w.geometry(obj.Geometry) {
+				return 0, ErrBadGeoJSON
+			}
+			boxes = w.boxes()
+		}
+		for _, box := range boxes {
+			items = append(items, Item{box[0], box[1], f})
+		}
+		if len(boxes) > 0 {
+			n++
+		}
+	}
+	loadItems(tr, items)
+	return n, nil
+}
+
+// geojsonBBox returns the boxes of a bbox member
+func geojsonBBox(bbox []float64) [][2][2]float64 {
+	dims := len(bbox) / 2
+	min := [2]float64{bbox[0], bbox[1]}
+	max := [2]float64{bbox[dims], bbox[dims+1]}
+	if min[0] <= max[0] {
+		return [][2][2]float64{{min, max}}
+	}
+	return [][2][2]float64{
+		{min, {180, max[1]}},
+		{{-180, min[1]}, max},
+	}
+}
+
+// geojsonWrap gathers the extent of the parts of a geometry, where east and
+// west are the parts on either side of the antimeridian.
+type geojsonWrap struct {
+	east, west extent
+	crosses    bool
+}
+
+func (w *geojsonWrap) boxes() [][2][2]float64 {
+	var boxes [][2][2]float64
+	if !w.crosses {
+		// the geometry doesn't cross, so the sides are both the same box
+		var e extent
+		for _, side := range []extent{w.east, w.west} {
+			if side.ok {
+				e.add(side.min)
+				e.add(side.max)
+			}
+		}
+		if e.ok {
+			boxes = append(boxes, [2][2]float64{e.min, e.max})
+		}
+		return boxes
+	}
+	for _, side := range []extent{w.east, w.west} {
+		if side.ok {
+			boxes = append(boxes, [2][2]float64{side.min, side.max})
+		}
+	}
+	return boxes
+}
+
+// part adds a point or a path to the extents. The longitudes are unwrapped
+// along the path, by adding or subtracting 360 at each jump of more than 180
+// degrees, and the extent of the continuous path is split at the
+// antimeridian.
+func (w *geojsonWrap) part(coords [][]float64) bool {
+	var e extent
+	var last float64
+	for i, c := range coords {
+		if len(c) < 2 {
+			return false
+		}
+		p := [2]float64{c[0], c[1]}
+		if i > 0 {
+			for p[0]-last > 180 {
+				p[0] -= 360
+			}
+			for p[0]-last < -180 {
+				p[0] += 360
+			}
+		}
+		last = p[0]
+		e.add(p)
+	}
+	if !e.ok {
+		return true
+	}
+	switch {
+	case e.min[0] >= -180 && e.max[0] <= 180:
+		side := &w.east
+		if e.min[0]+e.max[0] < 0 {
+			side = &w.west
+		}
+		side.add(e.min)
+		side.add(e.max)
+		return true
+	case e.max[0]-e.min[0] >= 360:
+		// the path goes all the way around
+		w.crosses = true
+		w.east.add([2]float64{-180, e.min[1]})
+		w.east.add([2]float64{180, e.max[1]})
+		return true
+	case e.min[0] < -180:
+		e.min[0] += 360
+		e.max[0] += 360
+	}
+	w.crosses = true
+	w.east.add(e.min)
+	w.east.add([2]float64{180, e.max[1]})
+	w.west.add([2]float64{-180, e.min[1]})
+	w.west.add([2]float64{e.max[0] - 360, e.max[1]})
+	return true
+}
+
+func (w *geojsonWrap) geometry(data json.RawMessage) bool {
+	var obj geojsonObject
+	if json.Unmarshal(data, &obj) != nil {
+		return false
+	}
+	switch obj.Type {
+	case "GeometryCollection":
+		for _, g := range obj.Geometries {
+			if !w.geometry(g) {
+				return false
+			}
+		}
+		return true
+	case "Point":
+		var c []float64
+		return json.Unmarshal(obj.Coordinates, &c) == nil &&
+			w.part([][]float64{c})
+	case "MultiPoint":
+		var cs [][]float64
+		if json.Unmarshal(obj.Coordinates, &cs) != nil {
+			return false
+		}
+		for _, c := range cs {
+			if !w.part([][]float64{c}) {
+				return false
+			}
+		}
+		return true
+	case "LineString":
+		var cs [][]float64
+		return json.Unmarshal(obj.Coordinates, &cs) == nil && w.part(cs)
+	case "MultiLineString", "Polygon":
+		var css [][][]float64
+		if json.Unmarshal(obj.Coordinates, &css) != nil {
+			return false
+		}
+		for _, cs := range css {
+			if !w.part(cs) {
+				return false
+			}
+		}
+		return true
+	case "MultiPolygon":
+		var csss [][][][]float64
+		if json.Unmarshal(obj.Coordinates, &csss) != nil {
+			return false
+		}
+		for _, css := range csss {
+			for _, cs := range css {
+				if !w.part(cs) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
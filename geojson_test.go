@@ -0,0 +1,115 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestLoadGeoJSON(t *testing.T) {
+	data := []byte(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":1,"properties":{"name":"point"},
+			"geometry":{"type":"Point","coordinates":[1,2]}},
+		{"type":"Feature","id":2,"properties":{"name":"multipolygon"},
+			"geometry":{"type":"MultiPolygon","coordinates":[
+				[[[0,0],[1,0],[1,1],[0,0]]],
+				[[[10,10],[11,10],[11,11],[10,10]]]
+			]}},
+		{"type":"Feature","id":3,"properties":{"name":"collection"},
+			"geometry":{"type":"GeometryCollection","geometries":[
+				{"type":"LineString","coordinates":[[-5,-5,100],[-4,-3,100]]},
+				{"type":"MultiPoint","coordinates":[[3,4]]}
+			]}},
+		{"type":"Feature","id":4,"properties":{"name":"crossing"},
+			"geometry":{"type":"LineString","coordinates":[
+				[170,10],[-170,20]
+			]}},
+		{"type":"Feature","id":8,"properties":{"name":"crossing at 0"},
+			"geometry":{"type":"LineString","coordinates":[
+				[-5,0],[170,0],[-170,0]
+			]}},
+		{"type":"Feature","id":9,"properties":{"name":"crossing west"},
+			"geometry":{"type":"LineString","coordinates":[
+				[-170,0],[170,5],[175,10]
+			]}},
+		{"type":"Feature","id":5,"properties":{"name":"bbox"},
+			"bbox":[175,-10,-175,10],
+			"geometry":{"type":"Point","coordinates":[180,0]}},
+		{"type":"Feature","id":6,"properties":{"name":"skipped"},
+			"geometry":{"type":"Point","coordinates":[0,0]}},
+		{"type":"Feature","id":7,"properties":{"name":"null"},
+			"geometry":null}
+	]}`)
+	expect := map[interface{}][][2][2]float64{
+		1.0: {{{1, 2}, {1, 2}}},
+		2.0: {{{0, 0}, {11, 11}}},
+		3.0: {{{-5, -5}, {3, 4}}},
+		4.0: {{{170, 10}, {180, 20}}, {{-180, 10}, {-170, 20}}},
+		5.0: {{{175, -10}, {180, 10}}, {{-180, -10}, {-175, 10}}},
+		8.0: {{{-5, 0}, {180, 0}}, {{-180, 0}, {-170, 0}}},
+		9.0: {{{170, 0}, {180, 10}}, {{-180, 0}, {-170, 10}}},
+	}
+	tr := &internal.RTree{}
+	n, err := LoadGeoJSON(data, tr, func(f Feature) bool {
+		return f.Properties["name"] != "skipped"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), n)
+	}
+	got := make(map[interface{}][][2][2]float64)
+	tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		f := data.(*Feature)
+		got[f.ID] = append(got[f.ID], [2][2]float64{min, max})
+		return true
+	})
+	for id, boxes := range expect {
+		if len(got[id]) != len(boxes) {
+			t.Fatalf("%v: expected %v, got %v", id, boxes, got[id])
+		}
+		for _, box := range boxes {
+			found := false
+			for _, g := range got[id] {
+				found = found || g == box
+			}
+			if !found {
+				t.Fatalf("%v: expected %v, got %v", id, boxes, got[id])
+			}
+		}
+	}
+	// the line that crosses at 0 passes through 100,0
+	var found bool
+	tr.Search([2]float64{100, 0}, [2]float64{100, 0},
+		func(min, max [2]float64, data interface{}) bool {
+			found = found || data.(*Feature).ID == 8.0
+			return true
+		},
+	)
+	if !found {
+		t.Fatal("expected the line at 100,0")
+	}
+	// a single feature
+	tr = &internal.RTree{}
+	n, err = LoadGeoJSON([]byte(`{"type":"Feature","id":"a",
+		"geometry":{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,3],[0,0]]]}
+	}`), tr, nil)
+	if err != nil || n != 1 || tr.Len() != 1 {
+		t.Fatalf("expected %d, got %d, %v", 1, n, err)
+	}
+	for _, bad := range []string{
+		`{"type":"Point","coordinates":[1,2]}`,
+		`{"type":"FeatureCollection","features":[{"type":"Point"}]}`,
+		`{"type":"Feature","geometry":{"type":"Circle","coordinates":[1,2]}}`,
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[1]}}`,
+		`{"type":"Feature","geometry":{"type":"LineString","coordinates":[1,2]}}`,
+		`{"type":"Feature"`,
+	} {
+		tr := &internal.RTree{}
+		if n, err := LoadGeoJSON([]byte(bad), tr, nil); err != ErrBadGeoJSON ||
+			n != 0 || tr.Len() != 0 {
+			t.Fatalf("%s: expected %v, got %v", bad, ErrBadGeoJSON, err)
+		}
+	}
+}
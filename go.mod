@@ -1,6 +1,6 @@
 module github.com/tidwall/geoindex
 
-go 1.15
+go 1.18
 
 require (
 	github.com/tidwall/cities v0.1.0
@@ -0,0 +1,47 @@
+package geoindex
+
+import "math"
+
+// hilbertXY returns the distance along a Hilbert curve that fills a grid of
+// 2^32 by 2^32 cells, for the cell at x,y.
+func hilbertXY(x, y uint32) uint64 {
+	var d uint64
+	for s := uint32(1 << 31); s > 0; s >>= 1 {
+		var rx, ry uint32
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		// rotate the quadrant
+		if ry == 0 {
+			if rx == 1 {
+				x, y = ^x, ^y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+// sortableBits returns the high bits of a float64 as a uint32 that has the
+// same order as the float64.
+func sortableBits(f float64) uint32 {
+	b := math.Float64bits(f)
+	if b>>63 == 1 {
+		b = ^b
+	} else {
+		b |= 1 << 63
+	}
+	return uint32(b >> 32)
+}
+
+// hilbertBox returns the Hilbert value of the center of a box. The whole
+// range of float64 coordinates is used, so the value doesn't depend on the
+// bounds of a tree.
+func hilbertBox(min, max [2]float64) uint64 {
+	return hilbertXY(sortableBits((min[0]+max[0])/2),
+		sortableBits((min[1]+max[1])/2))
+}
@@ -0,0 +1,41 @@
+package geoindex
+
+import "testing"
+
+func TestHilbert(t *testing.T) {
+	// the first order curve visits the quadrants in a U shape
+	for i, p := range [][2]uint32{
+		{0, 0}, {0, 1 << 31}, {1 << 31, 1 << 31}, {1 << 31, 0},
+	} {
+		if d := hilbertXY(p[0], p[1]) >> 62; d != uint64(i) {
+			t.Fatalf("%v: expected %d, got %d", p, i, d)
+		}
+	}
+	// neighboring cells are next to each other on the curve
+	const n = 64
+	seen := make(map[uint64]bool)
+	cells := make(map[uint64][2]uint32)
+	for x := uint32(0); x < n; x++ {
+		for y := uint32(0); y < n; y++ {
+			d := hilbertXY(x<<26, y<<26) >> 52
+			if seen[d] {
+				t.Fatalf("duplicate %d", d)
+			}
+			seen[d] = true
+			cells[d] = [2]uint32{x, y}
+		}
+	}
+	for d := uint64(1); d < n*n; d++ {
+		a, b := cells[d-1], cells[d]
+		dx, dy := int(a[0])-int(b[0]), int(a[1])-int(b[1])
+		if dx*dx+dy*dy != 1 {
+			t.Fatalf("%d: %v and %v are not neighbors", d, a, b)
+		}
+	}
+	for _, v := range [][2]float64{{-1e300, -1}, {-1, -0.5}, {-0.5, 0},
+		{0, 1}, {1, 1e300}} {
+		if sortableBits(v[0]) >= sortableBits(v[1]) {
+			t.Fatalf("%v is out of order", v)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// SearchHints searches the index for items that intersects the rect param,
+// like Search, but the `iter` function is also called for each node that
+// intersects the rect, before its children, allowing for the caller to see
+// how the query traverses the tree. The depth is zero for the root nodes and
+// their children are one level deeper. The leaf param is true for items.
+// Returning false for a node skips the children of that node, such as for
+// custom pruning or for stopping at a level of detail when rendering.
+// Returning false for an item stops the search.
+// This function uses the Children of the wrapped tree.
+func (index *Index) SearchHints(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}, depth int,
+		leaf bool) bool,
+) {
+	defer index.stats.end(opSearch, index.stats.begin())
+	var search func(children []child.Child, depth int) bool
+	search = func(children []child.Child, depth int) bool {
+		for _, c := range children {
+			if !intersects(min, max, c.Min, c.Max) {
+				continue
+			}
+			if c.Item {
+				if !iter(c.Min, c.Max, c.Data, depth, true) {
+					return false
+				}
+			} else if iter(c.Min, c.Max, c.Data, depth, false) {
+				if !search(index.tree.Children(c.Data, nil), depth+1) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	search(index.tree.Children(nil, nil), 0)
+}
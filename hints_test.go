@@ -0,0 +1,83 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSearchHints(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(10000) {
+		index.Insert(box.min, box.max, i)
+	}
+	min, max := [2]float64{-50, -30}, [2]float64{50, 30}
+	expect := make(map[interface{}]bool)
+	index.Search(min, max, func(_, _ [2]float64, data interface{}) bool {
+		expect[data] = true
+		return true
+	})
+	got := make(map[interface{}]bool)
+	var nodes int
+	itemDepth := -1
+	index.SearchHints(min, max,
+		func(imin, imax [2]float64, data interface{}, depth int,
+			leaf bool) bool {
+			if !intersects(min, max, imin, imax) {
+				t.Fatalf("%v %v does not intersect", imin, imax)
+			}
+			if !leaf {
+				nodes++
+				return true
+			}
+			if itemDepth == -1 {
+				itemDepth = depth
+			} else if depth != itemDepth {
+				t.Fatalf("expected depth %d, got %d", itemDepth, depth)
+			}
+			got[data] = true
+			return true
+		},
+	)
+	if len(got) != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), len(got))
+	}
+	for data := range expect {
+		if !got[data] {
+			t.Fatalf("missing %v", data)
+		}
+	}
+	if nodes == 0 || itemDepth < 1 {
+		t.Fatalf("expected nodes, got %d at depth %d", nodes, itemDepth)
+	}
+
+	// prune everything below the root nodes
+	var count int
+	index.SearchHints(min, max,
+		func(_, _ [2]float64, _ interface{}, depth int, leaf bool) bool {
+			if depth != 0 || leaf {
+				t.Fatalf("expected a root node, got depth %d", depth)
+			}
+			count++
+			return false
+		},
+	)
+	if count == 0 {
+		t.Fatal("expected root nodes")
+	}
+
+	// stop at the first item
+	count = 0
+	index.SearchHints(min, max,
+		func(_, _ [2]float64, _ interface{}, depth int, leaf bool) bool {
+			if leaf {
+				count++
+				return false
+			}
+			return true
+		},
+	)
+	if count != 1 {
+		t.Fatalf("expected %d, got %d", 1, count)
+	}
+}
@@ -0,0 +1,230 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/pqueue"
+)
+
+// Interface3 is a tree-like structure that contains 3d geospatial data, such
+// as data that has altitude or time as the third dimension. It's the same as
+// Interface but with [3]float64 bounds.
+type Interface3 interface {
+	// Insert an item into the structure
+	Insert(min, max [3]float64, data interface{})
+	// Delete an item from the structure
+	Delete(min, max [3]float64, data interface{})
+	// Replace an item in the structure.
+	Replace(
+		oldMin, oldMax [3]float64, oldData interface{},
+		newMin, newMax [3]float64, newData interface{},
+	)
+	// Search the structure for items that intersects the rect param
+	Search(
+		min, max [3]float64,
+		iter func(min, max [3]float64, data interface{}) bool,
+	)
+	// Scan iterates through all data in tree in no specified order.
+	Scan(iter func(min, max [3]float64, data interface{}) bool)
+	// Len returns the number of items in tree
+	Len() int
+	// Bounds returns the minimum bounding box
+	Bounds() (min, max [3]float64)
+	// Children returns all children for parent node. If parent node is nil
+	// then the root nodes should be returned.
+	// The reuse buffer is an empty length slice that can optionally be used
+	// to avoid extra allocations.
+	Children(parent interface{}, reuse []child.Child3) (
+		children []child.Child3)
+}
+
+// Index3 is a wrapper around Interface3 that provides extra features like a
+// Nearby (kNN) function, in the same way as Index.
+type Index3 struct {
+	tree Interface3
+}
+
+// Wrap3 wraps a 3d tree-like geospatial interface.
+func Wrap3(tree Interface3) *Index3 {
+	return &Index3{tree: tree}
+}
+
+// Insert an item into the index
+func (index *Index3) Insert(min, max [3]float64, data interface{}) {
+	index.tree.Insert(min, max, data)
+}
+
+// Delete an item from the index
+func (index *Index3) Delete(min, max [3]float64, data interface{}) {
+	index.tree.Delete(min, max, data)
+}
+
+// Replace an item in the index
+func (index *Index3) Replace(
+	oldMin, oldMax [3]float64, oldData interface{},
+	newMin, newMax [3]float64, newData interface{},
+) {
+	index.tree.Replace(oldMin, oldMax, oldData, newMin, newMax, newData)
+}
+
+// Search the index for items that intersects the rect param
+func (index *Index3) Search(
+	min, max [3]float64,
+	iter func(min, max [3]float64, data interface{}) bool,
+) {
+	index.tree.Search(min, max, iter)
+}
+
+// Scan iterates through all data in tree in no specified order.
+func (index *Index3) Scan(
+	iter func(min, max [3]float64, data interface{}) bool,
+) {
+	index.tree.Scan(iter)
+}
+
+// Len returns the number of items in tree
+func (index *Index3) Len() int {
+	return index.tree.Len()
+}
+
+// Bounds returns the minimum bounding box
+func (index *Index3) Bounds() (min, max [3]float64) {
+	return index.tree.Bounds()
+}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root nodes should be returned.
+func (index *Index3) Children(parent interface{}, reuse []child.Child3) (
+	children []child.Child3,
+) {
+	return index.tree.Children(parent, reuse)
+}
+
+type treeNearby3 interface {
+	Nearby(
+		algo func(min, max [3]float64, data interface{}, item bool,
+		) (dist float64),
+		iter func(min, max [3]float64, data interface{}, dist float64) bool,
+	)
+}
+
+// Nearby performs a kNN-type operation on the index, in the same way as
+// Index.Nearby, but with [3]float64 bounds.
+func (index *Index3) Nearby(
+	algo func(min, max [3]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [3]float64, data interface{}, dist float64) bool,
+) {
+	if tr, ok := index.tree.(treeNearby3); ok {
+		tr.Nearby(algo, iter)
+		return
+	}
+	var q pqueue.Queue[child.Child3]
+	var children []child.Child3
+	children = index.tree.Children(nil, children[:0])
+	for {
+		for _, child := range children {
+			q.Push(child, algo(child.Min, child.Max, child.Data, child.Item))
+		}
+		children = children[:0]
+		for len(children) == 0 {
+			child, dist, ok := q.Pop()
+			if !ok {
+				// nothing left in queue
+				return
+			}
+			if child.Item {
+				if !iter(child.Min, child.Max, child.Data, dist) {
+					return
+				}
+			} else {
+				// gather more children
+				children = index.tree.Children(child.Data, children)
+			}
+		}
+	}
+}
+
+// SVG prints the tree in the same way as Index.SVG, using a projection of
+// the tree onto the first two dimensions.
+func (index *Index3) SVG() string {
+	return Wrap(From3(index.tree)).SVG()
+}
+
+// From3 returns an Interface for a 3d tree, where the bounds of the items and
+// nodes are projected onto the first two dimensions, and items are inserted
+// with a third dimension of zero. This allows for a 3d tree to be used with
+// the features of Index, and to be checked with the Tests.
+func From3(tree Interface3) Interface {
+	return &adapter3{tree: tree}
+}
+
+type adapter3 struct {
+	tree Interface3
+}
+
+func to3(p [2]float64) [3]float64 {
+	return [3]float64{p[0], p[1], 0}
+}
+
+func from3(p [3]float64) [2]float64 {
+	return [2]float64{p[0], p[1]}
+}
+
+func (a *adapter3) Insert(min, max [2]float64, data interface{}) {
+	a.tree.Insert(to3(min), to3(max), data)
+}
+
+func (a *adapter3) Delete(min, max [2]float64, data interface{}) {
+	a.tree.Delete(to3(min), to3(max), data)
+}
+
+func (a *adapter3) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	a.tree.Replace(to3(oldMin), to3(oldMax), oldData,
+		to3(newMin), to3(newMax), newData)
+}
+
+func (a *adapter3) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	// search all of the third dimension
+	a.tree.Search(
+		[3]float64{min[0], min[1], math.Inf(-1)},
+		[3]float64{max[0], max[1], math.Inf(+1)},
+		func(min, max [3]float64, data interface{}) bool {
+			return iter(from3(min), from3(max), data)
+		},
+	)
+}
+
+func (a *adapter3) Scan(
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	a.tree.Scan(func(min, max [3]float64, data interface{}) bool {
+		return iter(from3(min), from3(max), data)
+	})
+}
+
+func (a *adapter3) Len() int {
+	return a.tree.Len()
+}
+
+func (a *adapter3) Bounds() (min, max [2]float64) {
+	min3, max3 := a.tree.Bounds()
+	return from3(min3), from3(max3)
+}
+
+func (a *adapter3) Children(parent interface{}, reuse []child.Child,
+) []child.Child {
+	children := reuse
+	for _, c := range a.tree.Children(parent, nil) {
+		children = append(children, child.Child{
+			Min: from3(c.Min), Max: from3(c.Max), Data: c.Data, Item: c.Item,
+		})
+	}
+	return children
+}
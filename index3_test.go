@@ -0,0 +1,178 @@
+package geoindex
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// tree3 is a simple 3d tree that stores its items in a slice, with a node for
+// every sixteen items
+type tree3 struct {
+	items []child.Child3
+}
+
+func intersects3(aMin, aMax, bMin, bMax [3]float64) bool {
+	for i := 0; i < 3; i++ {
+		if bMin[i] > aMax[i] || bMax[i] < aMin[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (tr *tree3) Insert(min, max [3]float64, data interface{}) {
+	tr.items = append(tr.items,
+		child.Child3{Min: min, Max: max, Data: data, Item: true})
+}
+
+func (tr *tree3) Delete(min, max [3]float64, data interface{}) {
+	for i, item := range tr.items {
+		if item.Min == min && item.Max == max && item.Data == data {
+			copy(tr.items[i:], tr.items[i+1:])
+			tr.items = tr.items[:len(tr.items)-1]
+			return
+		}
+	}
+}
+
+func (tr *tree3) Replace(
+	oldMin, oldMax [3]float64, oldData interface{},
+	newMin, newMax [3]float64, newData interface{},
+) {
+	tr.Delete(oldMin, oldMax, oldData)
+	tr.Insert(newMin, newMax, newData)
+}
+
+func (tr *tree3) Search(min, max [3]float64,
+	iter func(min, max [3]float64, data interface{}) bool,
+) {
+	for _, item := range tr.items {
+		if intersects3(min, max, item.Min, item.Max) {
+			if !iter(item.Min, item.Max, item.Data) {
+				return
+			}
+		}
+	}
+}
+
+func (tr *tree3) Scan(iter func(min, max [3]float64, data interface{}) bool) {
+	for _, item := range tr.items {
+		if !iter(item.Min, item.Max, item.Data) {
+			return
+		}
+	}
+}
+
+func (tr *tree3) Len() int {
+	return len(tr.items)
+}
+
+func bounds3(items []child.Child3) (min, max [3]float64) {
+	for i, item := range items {
+		if i == 0 {
+			min, max = item.Min, item.Max
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			min[j] = math.Min(min[j], item.Min[j])
+			max[j] = math.Max(max[j], item.Max[j])
+		}
+	}
+	return min, max
+}
+
+func (tr *tree3) Bounds() (min, max [3]float64) {
+	return bounds3(tr.items)
+}
+
+func (tr *tree3) Children(parent interface{}, reuse []child.Child3,
+) []child.Child3 {
+	children := reuse
+	if parent == nil {
+		for i := 0; i < len(tr.items); i += 16 {
+			j := i + 16
+			if j > len(tr.items) {
+				j = len(tr.items)
+			}
+			min, max := bounds3(tr.items[i:j])
+			children = append(children,
+				child.Child3{Min: min, Max: max, Data: [2]int{i, j}})
+		}
+		return children
+	}
+	r := parent.([2]int)
+	return append(children, tr.items[r[0]:r[1]]...)
+}
+
+func TestIndex3(t *testing.T) {
+	t.Run("RandomRects", func(t *testing.T) {
+		Tests.TestRandomRects(t, From3(&tree3{}), 1000)
+	})
+	t.Run("RandomPoints", func(t *testing.T) {
+		Tests.TestRandomPoints(t, From3(&tree3{}), 1000)
+	})
+	index := Wrap3(&tree3{})
+	for i := 0; i < 1000; i++ {
+		p := [3]float64{rand.Float64() * 100, rand.Float64() * 100,
+			rand.Float64() * 100}
+		index.Insert(p, p, i)
+	}
+	if index.Len() != 1000 {
+		t.Fatalf("expected %d, got %d", 1000, index.Len())
+	}
+	min, max := [3]float64{25, 25, 25}, [3]float64{75, 75, 75}
+	var expect, got int
+	index.Scan(func(imin, imax [3]float64, data interface{}) bool {
+		if intersects3(min, max, imin, imax) {
+			expect++
+		}
+		return true
+	})
+	index.Search(min, max, func(_, _ [3]float64, _ interface{}) bool {
+		got++
+		return true
+	})
+	if got != expect {
+		t.Fatalf("expected %d, got %d", expect, got)
+	}
+	target := [3]float64{50, 50, 50}
+	dist3 := func(min, max [3]float64) float64 {
+		var dist float64
+		for i := 0; i < 3; i++ {
+			d := math.Max(math.Max(min[i]-target[i], target[i]-max[i]), 0)
+			dist += d * d
+		}
+		return dist
+	}
+	var dists []float64
+	index.Scan(func(min, max [3]float64, data interface{}) bool {
+		dists = append(dists, dist3(min, max))
+		return true
+	})
+	sort.Float64s(dists)
+	var n int
+	index.Nearby(
+		func(min, max [3]float64, data interface{}, item bool) float64 {
+			return dist3(min, max)
+		},
+		func(min, max [3]float64, data interface{}, dist float64) bool {
+			if dist != dists[n] {
+				t.Fatalf("result %d: expected %v, got %v", n, dists[n], dist)
+			}
+			n++
+			return true
+		},
+	)
+	if n != len(dists) {
+		t.Fatalf("expected %d, got %d", len(dists), n)
+	}
+	if svg := index.SVG(); strings.Count(svg, "<rect") != 1000+63 {
+		t.Fatalf("expected %d rects, got %d", 1000+63,
+			strings.Count(svg, "<rect"))
+	}
+}
@@ -0,0 +1,102 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// InterfaceG is a generic version of Interface with typed data. The Children
+// are the same as for Interface, where the Data of an item child holds a T.
+type InterfaceG[T any] interface {
+	TreeG[T]
+	Children(parent interface{}, reuse []child.Child) []child.Child
+}
+
+// IndexG is a generic version of Index that provides typed data to the
+// caller, avoiding the type assertions of interface{} data. It's a wrapper
+// around an InterfaceG, or any TreeG, in which case the Children are
+// synthesized as described in FromGN.
+//
+// Features that are not provided by IndexG are available on the Index
+// returned by the Index method, which shares the same tree.
+type IndexG[T any] struct {
+	tree  TreeG[T]
+	index *Index
+}
+
+// WrapIndexG returns an IndexG for a generic tree.
+func WrapIndexG[T any](tree TreeG[T]) *IndexG[T] {
+	return &IndexG[T]{tree: tree, index: WrapG(tree)}
+}
+
+// Index returns an Index for the same tree, which uses interface{} data.
+func (index *IndexG[T]) Index() *Index {
+	return index.index
+}
+
+// Insert an item into the index
+func (index *IndexG[T]) Insert(min, max [2]float64, data T) {
+	index.tree.Insert(min, max, data)
+}
+
+// Delete an item from the index
+func (index *IndexG[T]) Delete(min, max [2]float64, data T) {
+	index.tree.Delete(min, max, data)
+}
+
+// Replace an item in the index
+func (index *IndexG[T]) Replace(
+	oldMin, oldMax [2]float64, oldData T,
+	newMin, newMax [2]float64, newData T,
+) {
+	index.tree.Replace(oldMin, oldMax, oldData, newMin, newMax, newData)
+}
+
+// Search the index for items that intersects the rect param
+func (index *IndexG[T]) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	index.tree.Search(min, max, iter)
+}
+
+// Scan iterates through all data in tree in no specified order.
+func (index *IndexG[T]) Scan(iter func(min, max [2]float64, data T) bool) {
+	index.tree.Scan(iter)
+}
+
+// Len returns the number of items in tree
+func (index *IndexG[T]) Len() int {
+	return index.tree.Len()
+}
+
+// Bounds returns the minimum bounding box
+func (index *IndexG[T]) Bounds() (min, max [2]float64) {
+	return index.tree.Bounds()
+}
+
+// Nearby performs a kNN-type operation on the index. See Index.Nearby.
+// The data is the zero value of T for nodes. When the tree provides its own
+// typed Nearby then it's used as is.
+func (index *IndexG[T]) Nearby(
+	algo func(min, max [2]float64, data T, item bool) (dist float64),
+	iter func(min, max [2]float64, data T, dist float64) bool,
+) {
+	if tr, ok := index.tree.(treeNearbyGN[float64, T]); ok {
+		tr.Nearby(algo, iter)
+		return
+	}
+	index.index.nearbyAux(
+		func(min, max [2]float64, data interface{}, item bool) (
+			dist float64, aux interface{},
+		) {
+			var v T
+			if item {
+				v = toT[T](data)
+			}
+			return algo(min, max, v, item), nil
+		},
+		func(min, max [2]float64, data interface{}, dist float64,
+			aux interface{},
+		) bool {
+			return iter(min, max, toT[T](data), dist)
+		},
+	)
+}
@@ -0,0 +1,159 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// gtree is a generic tree that uses the internal rtree
+type gtree[N Numeric, T any] struct {
+	tr internal.RTree
+}
+
+func (t *gtree[N, T]) Insert(min, max [2]N, data T) {
+	t.tr.Insert(fromN(min), fromN(max), data)
+}
+
+func (t *gtree[N, T]) Delete(min, max [2]N, data T) {
+	t.tr.Delete(fromN(min), fromN(max), data)
+}
+
+func (t *gtree[N, T]) Replace(
+	oldMin, oldMax [2]N, oldData T,
+	newMin, newMax [2]N, newData T,
+) {
+	t.tr.Replace(fromN(oldMin), fromN(oldMax), oldData,
+		fromN(newMin), fromN(newMax), newData)
+}
+
+func (t *gtree[N, T]) Search(
+	min, max [2]N, iter func(min, max [2]N, data T) bool,
+) {
+	t.tr.Search(fromN(min), fromN(max),
+		func(min, max [2]float64, data interface{}) bool {
+			return iter(toN[N](min), toN[N](max), toT[T](data))
+		})
+}
+
+func (t *gtree[N, T]) Scan(iter func(min, max [2]N, data T) bool) {
+	t.tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		return iter(toN[N](min), toN[N](max), toT[T](data))
+	})
+}
+
+func (t *gtree[N, T]) Len() int {
+	return t.tr.Len()
+}
+
+func (t *gtree[N, T]) Bounds() (min, max [2]N) {
+	fmin, fmax := t.tr.Bounds()
+	return toN[N](fmin), toN[N](fmax)
+}
+
+// gnearbytree is a gtree with a typed Nearby
+type gnearbytree struct {
+	gtree[float64, int]
+	calls int
+}
+
+func (t *gnearbytree) Nearby(
+	algo func(min, max [2]float64, data int, item bool) (dist float64),
+	iter func(min, max [2]float64, data int, dist float64) bool,
+) {
+	t.calls++
+	WrapG[int](&t.gtree).Nearby(
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			var v int
+			if item {
+				v = data.(int)
+			}
+			return algo(min, max, v, item)
+		},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			return iter(min, max, data.(int), dist)
+		},
+	)
+}
+
+func TestIndexG(t *testing.T) {
+	for _, native := range []bool{false, true} {
+		var tree TreeG[int]
+		nt := &gnearbytree{}
+		if native {
+			tree = nt
+		} else {
+			tree = &gtree[float64, int]{}
+		}
+		index := WrapIndexG(tree)
+		for i := 0; i < 100; i++ {
+			p := [2]float64{float64(i), float64(i)}
+			index.Insert(p, p, i)
+		}
+		p := [2]float64{0, 0}
+		index.Replace(p, p, 0, [2]float64{100, 100}, [2]float64{100, 100},
+			100)
+		index.Delete([2]float64{1, 1}, [2]float64{1, 1}, 1)
+		if index.Len() != 99 || index.Index().Len() != 99 {
+			t.Fatalf("expected %d, got %d", 99, index.Len())
+		}
+		if min, max := index.Bounds(); min != [2]float64{2, 2} ||
+			max != [2]float64{100, 100} {
+			t.Fatalf("unexpected bounds %v %v", min, max)
+		}
+		var sum int
+		index.Search([2]float64{2, 2}, [2]float64{4, 4},
+			func(min, max [2]float64, data int) bool {
+				sum += data
+				return true
+			},
+		)
+		if sum != 9 {
+			t.Fatalf("expected %d, got %d", 9, sum)
+		}
+		sum = 0
+		index.Scan(func(min, max [2]float64, data int) bool {
+			sum += data
+			return true
+		})
+		if sum != 4950-1+100 {
+			t.Fatalf("expected %d, got %d", 4950-1+100, sum)
+		}
+		var got []int
+		index.Nearby(
+			func(min, max [2]float64, data int, item bool) float64 {
+				return testBoxDist(min, max, [2]float64{50, 50},
+					[2]float64{50, 50})
+			},
+			func(min, max [2]float64, data int, dist float64) bool {
+				got = append(got, data)
+				return len(got) < 3
+			},
+		)
+		if len(got) != 3 || got[0] != 50 {
+			t.Fatalf("unexpected results %v", got)
+		}
+		if native && nt.calls != 1 {
+			t.Fatalf("expected %d, got %d", 1, nt.calls)
+		}
+	}
+}
+
+func TestIndexGNilData(t *testing.T) {
+	index := WrapIndexG[interface{}](&gtree[float64, interface{}]{})
+	index.Insert([2]float64{1, 1}, [2]float64{1, 1}, nil)
+	index.Insert([2]float64{2, 2}, [2]float64{2, 2}, 2)
+	var got []interface{}
+	index.Nearby(
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			return testBoxDist(min, max, [2]float64{0, 0}, [2]float64{0, 0})
+		},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			got = append(got, data)
+			return true
+		},
+	)
+	if len(got) != 2 || got[0] != nil || got[1] != 2 {
+		t.Fatalf("unexpected results %v", got)
+	}
+}
@@ -24,15 +24,33 @@ type rect struct {
 
 type node struct {
 	count int
+	items int // the number of items under the node
 	rects [maxEntries + 1]rect
 }
 
+// recount sets the number of items under the node from its children
+func (n *node) recount() {
+	if n.count == 0 {
+		n.items = 0
+		return
+	}
+	if _, ok := n.rects[0].data.(*node); !ok {
+		n.items = n.count
+		return
+	}
+	n.items = 0
+	for i := 0; i < n.count; i++ {
+		n.items += n.rects[i].data.(*node).items
+	}
+}
+
 // RTree ...
 type RTree struct {
 	height   int
 	root     rect
 	count    int
 	reinsert []rect
+	version  uint64
 }
 
 func (r *rect) expand(b *rect) {
@@ -129,6 +147,7 @@ func (tr *RTree) Insert(min, max [2]float64, value interface{}) {
 }
 
 func (tr *RTree) insert(item *rect) {
+	tr.version++
 	if tr.root.data == nil {
 		fit(item.min, item.max, new(node), &tr.root)
 	}
@@ -141,6 +160,9 @@ func (tr *RTree) insert(item *rect) {
 		tr.root.splitLargestAxisEdgeSnap(&newRoot.rects[1])
 		newRoot.rects[0] = tr.root
 		newRoot.count = 2
+		newRoot.rects[0].data.(*node).recount()
+		newRoot.rects[1].data.(*node).recount()
+		newRoot.recount()
 		tr.root.data = newRoot
 		tr.root.recalc()
 		tr.height++
@@ -266,6 +288,7 @@ func (r *rect) splitLargestAxisEdgeSnap(right *rect) {
 
 func (r *rect) insert(item *rect, height int) (grown bool) {
 	n := r.data.(*node)
+	n.items++
 	if height == 0 {
 		n.rects[n.count] = *item
 		n.count++
@@ -300,6 +323,8 @@ func (r *rect) insert(item *rect, height int) (grown bool) {
 	if child.data.(*node).count == maxEntries+1 {
 		child.splitLargestAxisEdgeSnap(&n.rects[n.count])
 		n.count++
+		child.data.(*node).recount()
+		n.rects[n.count-1].data.(*node).recount()
 	}
 	return grown
 }
@@ -398,16 +423,24 @@ func (tr *RTree) Scan(iter func(min, max [2]float64, data interface{}) bool) {
 
 // Delete data from tree
 func (tr *RTree) Delete(min, max [2]float64, data interface{}) {
+	tr.DeleteOne(min, max, data)
+}
+
+// DeleteOne deletes one item that has the data, and returns true when an item
+// was deleted. Only the first item that's found is deleted when there are
+// many items with the same box and data.
+func (tr *RTree) DeleteOne(min, max [2]float64, data interface{}) bool {
 	var item rect
 	fit(min, max, data, &item)
 	if tr.root.data == nil || !tr.root.contains(&item) {
-		return
+		return false
 	}
 	var removed, recalced bool
 	removed, recalced = tr.root.delete(tr, &item, tr.height)
 	if !removed {
-		return
+		return false
 	}
+	tr.version++
 	tr.count -= len(tr.reinsert) + 1
 	if tr.count == 0 {
 		tr.root = rect{}
@@ -429,6 +462,7 @@ func (tr *RTree) Delete(min, max [2]float64, data interface{}) {
 		}
 		tr.reinsert = tr.reinsert[:0]
 	}
+	return true
 }
 
 func (r *rect) delete(tr *RTree, item *rect, height int,
@@ -443,6 +477,7 @@ func (r *rect) delete(tr *RTree, item *rect, height int,
 				rects[i] = rects[len(rects)-1]
 				rects[len(rects)-1].data = nil
 				n.count--
+				n.items--
 				if recalced {
 					r.recalc()
 				}
@@ -454,15 +489,19 @@ func (r *rect) delete(tr *RTree, item *rect, height int,
 			if !rects[i].contains(item) {
 				continue
 			}
+			items := rects[i].data.(*node).items
 			removed, recalced = rects[i].delete(tr, item, height-1)
 			if !removed {
 				continue
 			}
+			// the child may have lost more items to the reinsert list
+			n.items -= items - rects[i].data.(*node).items
 			if rects[i].data.(*node).count < minEntries {
 				// underflow
 				if !recalced {
 					recalced = r.onEdge(&rects[i])
 				}
+				n.items -= rects[i].data.(*node).items
 				tr.reinsert = rects[i].flatten(tr.reinsert, height-1)
 				rects[i] = rects[len(rects)-1]
 				rects[len(rects)-1].data = nil
@@ -563,6 +602,100 @@ func (tr *RTree) Replace(
 	oldMin, oldMax [2]float64, oldData interface{},
 	newMin, newMax [2]float64, newData interface{},
 ) {
-	tr.Delete(oldMin, oldMax, oldData)
+	tr.ReplaceR(oldMin, oldMax, oldData, newMin, newMax, newData)
+}
+
+// ReplaceR replaces an item, and returns true when the old item was deleted.
+// The new item is inserted either way.
+func (tr *RTree) ReplaceR(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) (deleted bool) {
+	deleted = tr.DeleteOne(oldMin, oldMax, oldData)
 	tr.Insert(newMin, newMax, newData)
+	return deleted
+}
+
+// ReplaceInPlace replaces an item without a delete and insert, when the new
+// rect is contained by the leaf that holds the old item. Returns false when
+// the old item was not found, or when the new rect does not fit in the leaf,
+// in which case the tree is not changed.
+func (tr *RTree) ReplaceInPlace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) bool {
+	var old, item rect
+	fit(oldMin, oldMax, oldData, &old)
+	fit(newMin, newMax, newData, &item)
+	if tr.root.data == nil || !tr.root.contains(&old) {
+		return false
+	}
+	replaced, recalced := tr.root.replaceInPlace(&old, &item, tr.height)
+	if recalced {
+		tr.root.recalc()
+	}
+	if replaced {
+		tr.version++
+	}
+	return replaced
+}
+
+// CountedChildren is like Children but also returns the number of items
+// under each child, where an item counts as one.
+func (tr *RTree) CountedChildren(
+	parent interface{},
+	reuse []child.Child,
+	counts []int,
+) ([]child.Child, []int) {
+	children := tr.Children(parent, reuse)
+	counts = counts[:0]
+	for _, c := range children {
+		if c.Item {
+			counts = append(counts, 1)
+		} else {
+			counts = append(counts, c.Data.(*node).items)
+		}
+	}
+	return children, counts
+}
+
+// Version returns a number that changes each time the tree is modified
+func (tr *RTree) Version() uint64 {
+	return tr.version
+}
+
+func (r *rect) replaceInPlace(old, item *rect, height int,
+) (replaced, recalced bool) {
+	n := r.data.(*node)
+	rects := n.rects[0:n.count]
+	if height == 0 {
+		for i := 0; i < len(rects); i++ {
+			if rects[i].data == old.data {
+				if !r.contains(item) {
+					return false, false
+				}
+				recalced = r.onEdge(&rects[i])
+				rects[i] = *item
+				if recalced {
+					r.recalc()
+				}
+				return true, recalced
+			}
+		}
+	} else {
+		for i := 0; i < len(rects); i++ {
+			if !rects[i].contains(old) {
+				continue
+			}
+			replaced, recalced = rects[i].replaceInPlace(old, item, height-1)
+			if !replaced {
+				continue
+			}
+			if recalced {
+				r.recalc()
+			}
+			return replaced, recalced
+		}
+	}
+	return false, false
 }
@@ -0,0 +1,266 @@
+package internal_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex"
+	"github.com/tidwall/geoindex/internal"
+)
+
+// FuzzDeleteReinsert runs sequences of operations against the RTree that are
+// biased towards deletes which underflow nodes and the reinsertion of their
+// remaining items. After each operation the tree is checked for lost items and
+// stale bounds.
+//
+//	go test ./internal -fuzz FuzzDeleteReinsert
+func FuzzDeleteReinsert(f *testing.F) {
+	f.Add(int64(1), []byte{0, 1, 2, 3, 4, 5, 6, 7})
+	f.Add(int64(2), []byte{2, 2, 2, 2, 7, 2, 2, 2, 2, 7})
+	f.Add(int64(3), []byte{0, 0, 0, 3, 3, 3, 5, 5, 5, 6, 6, 6})
+	f.Fuzz(func(t *testing.T, seed int64, ops []byte) {
+		rng := rand.New(rand.NewSource(seed))
+		// Items are placed on a small integer grid so that many of them
+		// share edges with their nodes, which is where the bounds are
+		// recalculated.
+		newItem := func() [2][2]float64 {
+			var box [2][2]float64
+			box[0] = [2]float64{float64(rng.Intn(64)), float64(rng.Intn(64))}
+			box[1] = box[0]
+			if rng.Intn(2) == 0 {
+				box[1][0] += float64(rng.Intn(4))
+				box[1][1] += float64(rng.Intn(4))
+			}
+			return box
+		}
+		var tr internal.RTree
+		var boxes [][2][2]float64
+		live := make(map[int]bool)
+		var deleted []int
+		insert := func(id int) {
+			tr.Insert(boxes[id][0], boxes[id][1], id)
+			live[id] = true
+		}
+		remove := func(id int) {
+			tr.Delete(boxes[id][0], boxes[id][1], id)
+			delete(live, id)
+			deleted = append(deleted, id)
+		}
+		// start with enough items for a few levels
+		for i := 0; i < 500; i++ {
+			boxes = append(boxes, newItem())
+			insert(i)
+		}
+		for i, op := range ops {
+			switch op % 8 {
+			case 0:
+				// insert a batch of new items
+				for j := 0; j < 50; j++ {
+					boxes = append(boxes, newItem())
+					insert(len(boxes) - 1)
+				}
+			case 1, 2, 3:
+				// delete the items near a point, which empties their
+				// leaves and causes underflows
+				min := newItem()[0]
+				max := [2]float64{min[0] + 8, min[1] + 8}
+				var ids []int
+				tr.Search(min, max,
+					func(_, _ [2]float64, data interface{}) bool {
+						ids = append(ids, data.(int))
+						return true
+					},
+				)
+				for _, id := range ids {
+					remove(id)
+				}
+			case 4, 5:
+				// delete random items
+				for j := 0; j < 50 && len(live) > 0; j++ {
+					id := rng.Intn(len(boxes))
+					if live[id] {
+						remove(id)
+					}
+				}
+			case 6:
+				// delete everything but a few items
+				for id := range live {
+					if len(live) <= 5 {
+						break
+					}
+					remove(id)
+				}
+			case 7:
+				// reinsert the deleted items
+				for _, id := range deleted {
+					if !live[id] {
+						insert(id)
+					}
+				}
+				deleted = deleted[:0]
+			}
+			check(t, i, &tr, boxes, live)
+		}
+	})
+}
+
+func check(t *testing.T, op int, tr *internal.RTree, boxes [][2][2]float64,
+	live map[int]bool,
+) {
+	t.Helper()
+	if tr.Len() != len(live) {
+		t.Fatalf("op %d: expected %d items, got %d", op, len(live), tr.Len())
+	}
+	var min, max [2]float64
+	var i int
+	for id := range live {
+		if i == 0 {
+			min, max = boxes[id][0], boxes[id][1]
+		}
+		for j := 0; j < 2; j++ {
+			if boxes[id][0][j] < min[j] {
+				min[j] = boxes[id][0][j]
+			}
+			if boxes[id][1][j] > max[j] {
+				max[j] = boxes[id][1][j]
+			}
+		}
+		i++
+	}
+	bmin, bmax := tr.Bounds()
+	if bmin != min || bmax != max {
+		t.Fatalf("op %d: stale bounds: expected %v %v, got %v %v",
+			op, min, max, bmin, bmax)
+	}
+	if err := geoindex.Wrap(tr).Validate(); err != nil {
+		t.Fatalf("op %d: %v", op, err)
+	}
+	for id := range boxes {
+		var found bool
+		tr.Search(boxes[id][0], boxes[id][1],
+			func(_, _ [2]float64, data interface{}) bool {
+				found = data.(int) == id
+				return !found
+			},
+		)
+		if found != live[id] {
+			t.Fatalf("op %d: item %d: expected found=%v", op, id, live[id])
+		}
+	}
+}
+
+func TestReplaceInPlace(t *testing.T) {
+	var tr internal.RTree
+	rng := rand.New(rand.NewSource(1))
+	boxes := make([][2][2]float64, 10000)
+	for i := range boxes {
+		p := [2]float64{rng.Float64() * 100, rng.Float64() * 100}
+		boxes[i] = [2][2]float64{p, p}
+		tr.Insert(p, p, i)
+	}
+	var inPlace int
+	for i := range boxes {
+		// small moves usually stay in the leaf
+		p := boxes[i][0]
+		q := [2]float64{p[0] + rng.Float64()*0.2 - 0.1,
+			p[1] + rng.Float64()*0.2 - 0.1}
+		if tr.ReplaceInPlace(p, p, i, q, q, i) {
+			inPlace++
+			boxes[i] = [2][2]float64{q, q}
+		}
+	}
+	if inPlace == 0 || inPlace == len(boxes) {
+		t.Fatalf("unexpected %d in place replacements", inPlace)
+	}
+	if tr.ReplaceInPlace(boxes[0][0], boxes[0][1], -1, boxes[0][0],
+		boxes[0][1], -1) {
+		t.Fatal("expected false for a missing item")
+	}
+	if tr.Len() != len(boxes) {
+		t.Fatalf("expected %d, got %d", len(boxes), tr.Len())
+	}
+	if err := geoindex.Wrap(&tr).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for i, box := range boxes {
+		var found bool
+		tr.Search(box[0], box[1],
+			func(min, max [2]float64, data interface{}) bool {
+				found = data == i
+				return !found
+			},
+		)
+		if !found {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	var tr internal.RTree
+	v := tr.Version()
+	tr.Insert([2]float64{1, 1}, [2]float64{2, 2}, 1)
+	if tr.Version() == v {
+		t.Fatal("expected a new version after insert")
+	}
+	v = tr.Version()
+	tr.Delete([2]float64{5, 5}, [2]float64{6, 6}, 2)
+	if tr.Version() != v {
+		t.Fatal("expected the same version after a delete of nothing")
+	}
+	if !tr.ReplaceInPlace([2]float64{1, 1}, [2]float64{2, 2}, 1,
+		[2]float64{1, 1}, [2]float64{2, 2}, 3) || tr.Version() == v {
+		t.Fatal("expected a new version after replace")
+	}
+	v = tr.Version()
+	tr.Delete([2]float64{1, 1}, [2]float64{2, 2}, 3)
+	if tr.Version() == v {
+		t.Fatal("expected a new version after delete")
+	}
+}
+
+func TestCountedChildren(t *testing.T) {
+	var tr internal.RTree
+	rng := rand.New(rand.NewSource(1))
+	// checkCounts returns the number of items under the parent after checking
+	// the counts of each of its children
+	var checkCounts func(parent interface{}) int
+	checkCounts = func(parent interface{}) int {
+		children, counts := tr.CountedChildren(parent, nil, nil)
+		if len(counts) != len(children) {
+			t.Fatalf("expected %d counts, got %d", len(children), len(counts))
+		}
+		var total int
+		for i, c := range children {
+			n := 1
+			if !c.Item {
+				n = checkCounts(c.Data)
+			}
+			if counts[i] != n {
+				t.Fatalf("expected %d, got %d", n, counts[i])
+			}
+			total += n
+		}
+		return total
+	}
+	boxes := make([][2][2]float64, 5000)
+	for i := range boxes {
+		p := [2]float64{float64(rng.Intn(100)), float64(rng.Intn(100))}
+		boxes[i] = [2][2]float64{p, p}
+		tr.Insert(p, p, i)
+	}
+	if n := checkCounts(nil); n != len(boxes) {
+		t.Fatalf("expected %d, got %d", len(boxes), n)
+	}
+	for _, i := range rng.Perm(len(boxes)) {
+		tr.Delete(boxes[i][0], boxes[i][1], i)
+		if i%100 == 0 {
+			if n := checkCounts(nil); n != tr.Len() {
+				t.Fatalf("expected %d, got %d", tr.Len(), n)
+			}
+		}
+	}
+	if n := checkCounts(nil); n != 0 {
+		t.Fatalf("expected %d, got %d", 0, n)
+	}
+}
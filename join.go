@@ -0,0 +1,138 @@
+package geoindex
+
+import (
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/pqueue"
+)
+
+// JoinAlgo calculates the distance between a child of one index and a child
+// of another, for NearestJoin. The item params are true when the child is an
+// item, otherwise it's a node, and the distance for a node must never be
+// larger than the distance for any item in the node, in the same way as the
+// `algo` function of Nearby.
+type JoinAlgo func(
+	aMin, aMax [2]float64, aData interface{}, aItem bool,
+	bMin, bMax [2]float64, bData interface{}, bItem bool,
+) (dist float64)
+
+type joinPair struct {
+	a, b child.Child
+	// path is the position of a in the Children of each of its parents,
+	// which identifies the items of a without hashing their data.
+	path []int
+}
+
+// NearestJoin finds the nearest item in b for each item in a, such as for
+// finding the nearest warehouse for every store. Each item in a is passed to
+// iter once, along with its nearest item in b, from the smallest dist to the
+// largest dist.
+//
+// This is an incremental distance join that walks the Children of both trees
+// at the same time, which is usually much faster than performing a Nearby
+// operation on b for each item in a.
+func NearestJoin(a, b *Index, algo JoinAlgo,
+	iter func(aItem, bItem Item, dist float64) bool,
+) {
+	done := make(map[string]bool)
+	item := func(c child.Child) Item {
+		return Item{c.Min, c.Max, c.Data}
+	}
+	var q pqueue.Queue[joinPair]
+	push := func(pa, pb child.Child, path []int) {
+		if pa.Item && done[sessionKey(path)] {
+			return
+		}
+		q.Push(joinPair{pa, pb, path}, algo(pa.Min, pa.Max, pa.Data, pa.Item,
+			pb.Min, pb.Max, pb.Data, pb.Item))
+	}
+	// childPath returns the path of the i-th child of the node at path
+	childPath := func(path []int, i int) []int {
+		npath := make([]int, len(path)+1)
+		copy(npath, path)
+		npath[len(path)] = i
+		return npath
+	}
+	var aChildren, bChildren []child.Child
+	aChildren = a.tree.Children(nil, aChildren[:0])
+	bChildren = b.tree.Children(nil, bChildren[:0])
+	for i, ca := range aChildren {
+		for _, cb := range bChildren {
+			push(ca, cb, childPath(nil, i))
+		}
+	}
+	for {
+		pair, dist, ok := q.Pop()
+		if !ok {
+			return
+		}
+		pa, pb := pair.a, pair.b
+		switch {
+		case pa.Item && pb.Item:
+			key := sessionKey(pair.path)
+			if done[key] {
+				continue
+			}
+			done[key] = true
+			if !iter(item(pa), item(pb), dist) {
+				return
+			}
+		case !pa.Item && (pb.Item || boxArea(pa) >= boxArea(pb)):
+			// expand the larger node
+			aChildren = a.tree.Children(pa.Data, aChildren[:0])
+			for i, ca := range aChildren {
+				push(ca, pb, childPath(pair.path, i))
+			}
+		default:
+			if pa.Item && done[sessionKey(pair.path)] {
+				continue
+			}
+			bChildren = b.tree.Children(pb.Data, bChildren[:0])
+			for _, cb := range bChildren {
+				push(pa, cb, pair.path)
+			}
+		}
+	}
+}
+
+// IntersectsJoin finds all pairs of items from a and b whose boxes intersect,
+// such as for finding the parcels that each flood zone touches. The pairs are
+// returned in no specified order.
+//
+// This is a synchronized traversal of the Children of both trees, where only
+// the pairs of nodes that intersect are visited, which is usually much faster
+// than performing a Search on b for each item in a.
+func IntersectsJoin(a, b *Index, iter func(aItem, bItem Item) bool) {
+	var join func(pa, pb child.Child) bool
+	join = func(pa, pb child.Child) bool {
+		if !intersects(pa.Min, pa.Max, pb.Min, pb.Max) {
+			return true
+		}
+		switch {
+		case pa.Item && pb.Item:
+			return iter(Item{pa.Min, pa.Max, pa.Data},
+				Item{pb.Min, pb.Max, pb.Data})
+		case !pa.Item && (pb.Item || boxArea(pa) >= boxArea(pb)):
+			// expand the larger node
+			for _, ca := range a.tree.Children(pa.Data, nil) {
+				if !join(ca, pb) {
+					return false
+				}
+			}
+		default:
+			for _, cb := range b.tree.Children(pb.Data, nil) {
+				if !join(pa, cb) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	bRoots := b.tree.Children(nil, nil)
+	for _, ca := range a.tree.Children(nil, nil) {
+		for _, cb := range bRoots {
+			if !join(ca, cb) {
+				return
+			}
+		}
+	}
+}
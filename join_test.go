@@ -0,0 +1,132 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func joinBoxDist(
+	aMin, aMax [2]float64, aData interface{}, aItem bool,
+	bMin, bMax [2]float64, bData interface{}, bItem bool,
+) float64 {
+	return algo.BoxDistCalc(aMin, aMax, bMin, bMax, false)
+}
+
+func TestNearestJoin(t *testing.T) {
+	a, b := Wrap(&internal.RTree{}), Wrap(&internal.RTree{})
+	aBoxes, bBoxes := randBoxes(2000), randPoints(1000)
+	for i, box := range aBoxes {
+		a.Insert(box.min, box.max, i)
+	}
+	for i, box := range bBoxes {
+		b.Insert(box.min, box.max, i)
+	}
+	nearest := make([]float64, len(aBoxes))
+	for i, abox := range aBoxes {
+		b.Nearby(algo.Box(abox.min, abox.max, false, nil),
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				nearest[i] = dist
+				return false
+			},
+		)
+	}
+	seen := make(map[int]bool)
+	last := -1.0
+	NearestJoin(a, b, joinBoxDist,
+		func(aItem, bItem Item, dist float64) bool {
+			i := aItem.Data.(int)
+			if seen[i] {
+				t.Fatalf("duplicate %d", i)
+			}
+			seen[i] = true
+			if dist < last {
+				t.Fatal("out of order")
+			}
+			last = dist
+			if dist != nearest[i] {
+				t.Fatalf("item %d: expected %v, got %v", i, nearest[i], dist)
+			}
+			if d := algo.BoxDistCalc(aItem.Min, aItem.Max, bItem.Min,
+				bItem.Max, false); d != dist {
+				t.Fatalf("item %d: expected %v, got %v", i, dist, d)
+			}
+			return true
+		},
+	)
+	if len(seen) != len(aBoxes) {
+		t.Fatalf("expected %d, got %d", len(aBoxes), len(seen))
+	}
+	var n int
+	NearestJoin(a, b, joinBoxDist, func(_, _ Item, _ float64) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Fatalf("expected %d, got %d", 10, n)
+	}
+	NearestJoin(a, Wrap(&internal.RTree{}), joinBoxDist,
+		func(_, _ Item, _ float64) bool {
+			t.Fatal("expected no pairs")
+			return false
+		},
+	)
+	// data that isn't comparable, and items with the same box and data
+	a = Wrap(&internal.RTree{})
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i % 50), 0}
+		a.Insert(p, p, []string{"item"})
+	}
+	n = 0
+	NearestJoin(a, b, joinBoxDist, func(aItem, _ Item, _ float64) bool {
+		if aItem.Data.([]string)[0] != "item" {
+			t.Fatalf("unexpected data %v", aItem.Data)
+		}
+		n++
+		return true
+	})
+	if n != 100 {
+		t.Fatalf("expected %d, got %d", 100, n)
+	}
+}
+
+func TestIntersectsJoin(t *testing.T) {
+	a, b := Wrap(&internal.RTree{}), Wrap(&internal.RTree{})
+	aBoxes, bBoxes := randBoxes(5000), randBoxes(5000)
+	for i, box := range aBoxes {
+		a.Insert(box.min, box.max, i)
+	}
+	for i, box := range bBoxes {
+		b.Insert(box.min, box.max, i)
+	}
+	expect := make(map[[2]int]bool)
+	for i, abox := range aBoxes {
+		b.Search(abox.min, abox.max,
+			func(min, max [2]float64, data interface{}) bool {
+				expect[[2]int{i, data.(int)}] = true
+				return true
+			},
+		)
+	}
+	got := make(map[[2]int]bool)
+	IntersectsJoin(a, b, func(aItem, bItem Item) bool {
+		pair := [2]int{aItem.Data.(int), bItem.Data.(int)}
+		if got[pair] || !expect[pair] {
+			t.Fatalf("unexpected pair %v", pair)
+		}
+		got[pair] = true
+		return true
+	})
+	if len(got) != len(expect) || len(got) == 0 {
+		t.Fatalf("expected %d, got %d", len(expect), len(got))
+	}
+	var n int
+	IntersectsJoin(a, b, func(_, _ Item) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Fatalf("expected %d, got %d", 1, n)
+	}
+}
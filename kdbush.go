@@ -0,0 +1,266 @@
+package geoindex
+
+import (
+	"github.com/tidwall/geoindex/child"
+)
+
+// KDBush is a static point index that uses the implicit kd-tree layout of
+// the kdbush library, where the point ids and coordinates are stored in flat
+// slices that are sorted around the median of each range.
+// It satisfies Interface in read-only mode, with the Children synthesized
+// from the implicit tree, allowing for it to be used with Index.
+// Calling Insert, Delete, or Replace will panic.
+type KDBush struct {
+	ids      []int
+	coords   []float64
+	nodeSize int
+	data     func(id int) interface{}
+	root     *kdNode
+}
+
+type kdNode struct {
+	left, right int // range of points
+	min, max    [2]float64
+	kids        [2]*kdNode // nil for leaves
+}
+
+// NewKDBush returns a KDBush for points that are already in the kdbush
+// layout. The coords slice holds the x,y pairs for each id. The data function
+// returns the item data for an id, or the id is used as the data when data is
+// nil.
+func NewKDBush(ids []int, coords []float64, nodeSize int,
+	data func(id int) interface{},
+) *KDBush {
+	if nodeSize < 1 {
+		nodeSize = 1
+	}
+	kd := &KDBush{ids: ids, coords: coords, nodeSize: nodeSize, data: data}
+	if len(ids) > 0 {
+		kd.root = kd.build(0, len(ids)-1)
+	}
+	return kd
+}
+
+// BuildKDBush sorts the points into the kdbush layout and returns a KDBush.
+// The id for each point is its position in the points slice.
+func BuildKDBush(points [][2]float64, nodeSize int,
+	data func(id int) interface{},
+) *KDBush {
+	if nodeSize < 1 {
+		nodeSize = 1
+	}
+	ids := make([]int, len(points))
+	coords := make([]float64, len(points)*2)
+	for i, p := range points {
+		ids[i] = i
+		coords[i*2+0] = p[0]
+		coords[i*2+1] = p[1]
+	}
+	kdSort(ids, coords, nodeSize, 0, len(ids)-1, 0)
+	return NewKDBush(ids, coords, nodeSize, data)
+}
+
+func kdSort(ids []int, coords []float64, nodeSize, left, right, axis int) {
+	if right-left <= nodeSize {
+		return
+	}
+	m := (left + right) >> 1
+	kdSelect(ids, coords, m, left, right, axis)
+	kdSort(ids, coords, nodeSize, left, m-1, 1-axis)
+	kdSort(ids, coords, nodeSize, m+1, right, 1-axis)
+}
+
+// kdSelect partially sorts the range so that the k-th element is in its
+// sorted position on the axis.
+func kdSelect(ids []int, coords []float64, k, left, right, axis int) {
+	for right > left {
+		pivot := coords[k*2+axis]
+		kdSwap(ids, coords, k, right)
+		store := left
+		for i := left; i < right; i++ {
+			if coords[i*2+axis] < pivot {
+				kdSwap(ids, coords, i, store)
+				store++
+			}
+		}
+		kdSwap(ids, coords, store, right)
+		if store == k {
+			return
+		}
+		if store < k {
+			left = store + 1
+		} else {
+			right = store - 1
+		}
+	}
+}
+
+func kdSwap(ids []int, coords []float64, i, j int) {
+	ids[i], ids[j] = ids[j], ids[i]
+	coords[i*2+0], coords[j*2+0] = coords[j*2+0], coords[i*2+0]
+	coords[i*2+1], coords[j*2+1] = coords[j*2+1], coords[i*2+1]
+}
+
+func (kd *KDBush) point(i int) [2]float64 {
+	return [2]float64{kd.coords[i*2+0], kd.coords[i*2+1]}
+}
+
+func (kd *KDBush) item(i int) interface{} {
+	if kd.data == nil {
+		return kd.ids[i]
+	}
+	return kd.data(kd.ids[i])
+}
+
+func (kd *KDBush) build(left, right int) *kdNode {
+	n := &kdNode{left: left, right: right}
+	if right-left <= kd.nodeSize {
+		n.min = kd.point(left)
+		n.max = n.min
+		for i := left + 1; i <= right; i++ {
+			p := kd.point(i)
+			for j := 0; j < 2; j++ {
+				if p[j] < n.min[j] {
+					n.min[j] = p[j]
+				}
+				if p[j] > n.max[j] {
+					n.max[j] = p[j]
+				}
+			}
+		}
+		return n
+	}
+	m := (left + right) >> 1
+	n.kids[0] = kd.build(left, m)
+	n.kids[1] = kd.build(m+1, right)
+	n.min, n.max = n.kids[0].min, n.kids[0].max
+	for j := 0; j < 2; j++ {
+		if n.kids[1].min[j] < n.min[j] {
+			n.min[j] = n.kids[1].min[j]
+		}
+		if n.kids[1].max[j] > n.max[j] {
+			n.max[j] = n.kids[1].max[j]
+		}
+	}
+	return n
+}
+
+// Insert panics. The KDBush is read-only.
+func (kd *KDBush) Insert(min, max [2]float64, data interface{}) {
+	panic("geoindex: KDBush is read-only")
+}
+
+// Delete panics. The KDBush is read-only.
+func (kd *KDBush) Delete(min, max [2]float64, data interface{}) {
+	panic("geoindex: KDBush is read-only")
+}
+
+// Replace panics. The KDBush is read-only.
+func (kd *KDBush) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	panic("geoindex: KDBush is read-only")
+}
+
+// Search the index for points that intersects the rect param
+func (kd *KDBush) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	if len(kd.ids) == 0 {
+		return
+	}
+	inside := func(i int) bool {
+		p := kd.point(i)
+		return p[0] >= min[0] && p[0] <= max[0] &&
+			p[1] >= min[1] && p[1] <= max[1]
+	}
+	stack := []int{0, len(kd.ids) - 1, 0}
+	for len(stack) > 0 {
+		axis := stack[len(stack)-1]
+		right := stack[len(stack)-2]
+		left := stack[len(stack)-3]
+		stack = stack[:len(stack)-3]
+		if right-left <= kd.nodeSize {
+			for i := left; i <= right; i++ {
+				if inside(i) {
+					p := kd.point(i)
+					if !iter(p, p, kd.item(i)) {
+						return
+					}
+				}
+			}
+			continue
+		}
+		m := (left + right) >> 1
+		if inside(m) {
+			p := kd.point(m)
+			if !iter(p, p, kd.item(m)) {
+				return
+			}
+		}
+		v := kd.coords[m*2+axis]
+		if min[axis] <= v {
+			stack = append(stack, left, m-1, 1-axis)
+		}
+		if max[axis] >= v {
+			stack = append(stack, m+1, right, 1-axis)
+		}
+	}
+}
+
+// Scan iterates through all points in the index.
+func (kd *KDBush) Scan(iter func(min, max [2]float64, data interface{}) bool) {
+	for i := range kd.ids {
+		p := kd.point(i)
+		if !iter(p, p, kd.item(i)) {
+			return
+		}
+	}
+}
+
+// Len returns the number of points in the index
+func (kd *KDBush) Len() int {
+	return len(kd.ids)
+}
+
+// Bounds returns the minimum bounding box
+func (kd *KDBush) Bounds() (min, max [2]float64) {
+	if kd.root == nil {
+		return
+	}
+	return kd.root.min, kd.root.max
+}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root node is returned.
+func (kd *KDBush) Children(parent interface{}, reuse []child.Child) (
+	children []child.Child,
+) {
+	children = reuse
+	if parent == nil {
+		if kd.root != nil {
+			children = append(children, child.Child{
+				Min: kd.root.min, Max: kd.root.max, Data: kd.root,
+			})
+		}
+		return children
+	}
+	n := parent.(*kdNode)
+	if n.kids[0] == nil {
+		for i := n.left; i <= n.right; i++ {
+			p := kd.point(i)
+			children = append(children, child.Child{
+				Min: p, Max: p, Data: kd.item(i), Item: true,
+			})
+		}
+		return children
+	}
+	for _, kid := range n.kids {
+		children = append(children, child.Child{
+			Min: kid.min, Max: kid.max, Data: kid,
+		})
+	}
+	return children
+}
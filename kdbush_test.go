@@ -0,0 +1,71 @@
+package geoindex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+)
+
+func TestKDBush(t *testing.T) {
+	N := 10000
+	points := make([][2]float64, N)
+	for i, box := range randPoints(N) {
+		points[i] = box.min
+	}
+	kd := BuildKDBush(points, 16, nil)
+	if kd.Len() != N {
+		t.Fatalf("expected %d, got %d", N, kd.Len())
+	}
+	var count int
+	kd.Scan(func(min, max [2]float64, data interface{}) bool {
+		if points[data.(int)] != min {
+			t.Fatalf("wrong point for %d", data)
+		}
+		count++
+		return true
+	})
+	if count != N {
+		t.Fatalf("expected %d, got %d", N, count)
+	}
+	for i := 0; i < 100; i++ {
+		min := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		max := [2]float64{min[0] + 20, min[1] + 10}
+		var expect int
+		for _, p := range points {
+			if p[0] >= min[0] && p[0] <= max[0] &&
+				p[1] >= min[1] && p[1] <= max[1] {
+				expect++
+			}
+		}
+		count = 0
+		kd.Search(min, max, func(_, _ [2]float64, _ interface{}) bool {
+			count++
+			return true
+		})
+		if count != expect {
+			t.Fatalf("expected %d, got %d", expect, count)
+		}
+	}
+
+	index := Wrap(kd)
+	target := [2]float64{10, 10}
+	var ldist float64
+	count = 0
+	index.Nearby(algo.Box(target, target, false, nil),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			if dist < ldist {
+				t.Fatal("out of order")
+			}
+			ldist = dist
+			count++
+			return true
+		},
+	)
+	if count != N {
+		t.Fatalf("expected %d, got %d", N, count)
+	}
+	if len(index.SVG()) == 0 {
+		t.Fatal("expected svg")
+	}
+}
@@ -0,0 +1,116 @@
+package geoindex
+
+// KeyedIndex is an Index of items that each have a unique key, such as a
+// vehicle ID, along with a map from the key to the item. The map is kept in
+// sync with the tree, so an item can be found, moved, or deleted by its key
+// without knowing its box. The keys must be comparable.
+type KeyedIndex struct {
+	index *Index
+	items map[interface{}]*keyedItem
+}
+
+// keyedItem is the data of an item in the tree, which is unique for each key
+// even when the user data are the same.
+type keyedItem struct {
+	min, max [2]float64
+	key      interface{}
+	data     interface{}
+}
+
+// KeyedWrap wraps a tree-like geospatial interface in a KeyedIndex. The tree
+// should be empty, and it must only be modified by the KeyedIndex.
+func KeyedWrap(tree Interface) *KeyedIndex {
+	return &KeyedIndex{
+		index: Wrap(tree),
+		items: make(map[interface{}]*keyedItem),
+	}
+}
+
+// Len returns the number of items.
+func (ki *KeyedIndex) Len() int {
+	return len(ki.items)
+}
+
+// InsertKey inserts an item with the key. When there's already an item with
+// the key, it's replaced by the new item and true is returned.
+func (ki *KeyedIndex) InsertKey(key interface{}, min, max [2]float64,
+	data interface{},
+) (replaced bool) {
+	item := &keyedItem{min, max, key, data}
+	if old, ok := ki.items[key]; ok {
+		ki.index.Replace(old.min, old.max, old, min, max, item)
+		replaced = true
+	} else {
+		ki.index.Insert(min, max, item)
+	}
+	ki.items[key] = item
+	return replaced
+}
+
+// DeleteKey deletes the item with the key. Returns false when there's no
+// item with the key.
+func (ki *KeyedIndex) DeleteKey(key interface{}) bool {
+	item, ok := ki.items[key]
+	if !ok {
+		return false
+	}
+	ki.index.Delete(item.min, item.max, item)
+	delete(ki.items, key)
+	return true
+}
+
+// MoveKey moves the item with the key to a new box, keeping its data.
+// Returns false when there's no item with the key.
+func (ki *KeyedIndex) MoveKey(key interface{}, min, max [2]float64) bool {
+	item, ok := ki.items[key]
+	if !ok {
+		return false
+	}
+	ki.index.Replace(item.min, item.max, item, min, max, item)
+	item.min, item.max = min, max
+	return true
+}
+
+// GetKey returns the box and the data of the item with the key. Returns
+// false when there's no item with the key.
+func (ki *KeyedIndex) GetKey(key interface{}) (min, max [2]float64,
+	data interface{}, ok bool,
+) {
+	item, ok := ki.items[key]
+	if !ok {
+		return min, max, nil, false
+	}
+	return item.min, item.max, item.data, true
+}
+
+// Search the index for items that intersect the rect param.
+func (ki *KeyedIndex) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, key, data interface{}) bool,
+) {
+	ki.index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		item := data.(*keyedItem)
+		return iter(min, max, item.key, item.data)
+	})
+}
+
+// Nearby performs a kNN-type operation on the index. The algo is called with
+// the data of the items, in the same way as Index.Nearby.
+// See Nearby for a description of the `algo` function.
+func (ki *KeyedIndex) Nearby(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, key, data interface{}, dist float64) bool,
+) {
+	ki.index.Nearby(
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			if item {
+				data = data.(*keyedItem).data
+			}
+			return algo(min, max, data, item)
+		},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			item := data.(*keyedItem)
+			return iter(min, max, item.key, item.data, dist)
+		},
+	)
+}
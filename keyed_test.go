@@ -0,0 +1,81 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestKeyedIndex(t *testing.T) {
+	ki := KeyedWrap(&internal.RTree{})
+	boxes := randPoints(1000)
+	for i, box := range boxes {
+		// the data is the same for many keys
+		if ki.InsertKey(i, box.min, box.max, i%10) {
+			t.Fatalf("key %d: expected an insert", i)
+		}
+	}
+	if ki.Len() != len(boxes) {
+		t.Fatalf("expected %d, got %d", len(boxes), ki.Len())
+	}
+	// move half of the items, and replace the rest
+	for i := range boxes {
+		p := [2]float64{boxes[i].min[0] / 2, boxes[i].min[1] / 2}
+		if i%2 == 0 {
+			if !ki.MoveKey(i, p, p) {
+				t.Fatalf("key %d: expected a move", i)
+			}
+		} else if !ki.InsertKey(i, p, p, i%10) {
+			t.Fatalf("key %d: expected a replace", i)
+		}
+		boxes[i] = tBox{p, p}
+	}
+	if ki.Len() != len(boxes) || ki.index.Len() != len(boxes) {
+		t.Fatalf("expected %d, got %d", len(boxes), ki.index.Len())
+	}
+	if err := ki.index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for i, box := range boxes {
+		min, max, data, ok := ki.GetKey(i)
+		if !ok || min != box.min || max != box.max || data != i%10 {
+			t.Fatalf("key %d: unexpected %v %v %v", i, min, max, data)
+		}
+		var found bool
+		ki.Search(box.min, box.max,
+			func(min, max [2]float64, key, data interface{}) bool {
+				found = key == i
+				return !found
+			},
+		)
+		if !found {
+			t.Fatalf("key %d not found", i)
+		}
+	}
+	// the nearest item is the one at the target
+	var nearest interface{}
+	ki.Nearby(algo.Box(boxes[7].min, boxes[7].max, false, nil),
+		func(min, max [2]float64, key, data interface{}, dist float64) bool {
+			nearest = key
+			return false
+		},
+	)
+	if nearest != 7 {
+		t.Fatalf("expected %v, got %v", 7, nearest)
+	}
+	for i := range boxes {
+		if !ki.DeleteKey(i) || ki.DeleteKey(i) {
+			t.Fatalf("key %d: expected one delete", i)
+		}
+	}
+	if ki.Len() != 0 || ki.index.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, ki.index.Len())
+	}
+	if ki.MoveKey(1, boxes[1].min, boxes[1].max) {
+		t.Fatal("expected false for a missing key")
+	}
+	if _, _, _, ok := ki.GetKey(1); ok {
+		t.Fatal("expected false for a missing key")
+	}
+}
@@ -0,0 +1,144 @@
+package geoindex
+
+import (
+	"math"
+	"sort"
+)
+
+// BulkLoader is an optional interface for a tree that can load many items at
+// once, such as by performing a packed STR or OMT build, which is usually
+// much faster than inserting the items one at a time.
+type BulkLoader interface {
+	// Load the items into the tree. The mins, maxs, and data slices all
+	// have the same length, and each element is associated with the
+	// elements at the same position in the other slices.
+	Load(mins, maxs [][2]float64, data []interface{})
+}
+
+// Load inserts many items into the index. When the wrapped tree is a
+// BulkLoader then its Load is used, otherwise the items are inserted one at
+// a time. The mins, maxs, and data slices must have the same length.
+func (index *Index) Load(mins, maxs [][2]float64, data []interface{}) {
+	if len(mins) != len(maxs) || len(mins) != len(data) {
+		panic("geoindex: Load called with slices of different lengths")
+	}
+	defer index.stats.end(opLoad, index.stats.begin())
+	if tr, ok := index.tree.(BulkLoader); ok {
+		tr.Load(mins, maxs, data)
+		return
+	}
+	for i := range mins {
+		index.tree.Insert(mins[i], maxs[i], data[i])
+	}
+}
+
+// loadChunk is the number of items that LoadStream buffers for each Load
+const loadChunk = 4096
+
+// LoadStream receives items from the channel until it's closed, and loads
+// them into the index in chunks using Load. Each chunk is sorted by the x
+// coordinate of the item centers, so that nearby items are loaded together,
+// which keeps the tree compact when the wrapped tree is not a BulkLoader.
+// Only a single chunk is held in memory at a time.
+func (index *Index) LoadStream(ch <-chan Item) {
+	items := make([]Item, 0, loadChunk)
+	mins := make([][2]float64, 0, loadChunk)
+	maxs := make([][2]float64, 0, loadChunk)
+	data := make([]interface{}, 0, loadChunk)
+	flush := func() {
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Min[0]+items[i].Max[0] <
+				items[j].Min[0]+items[j].Max[0]
+		})
+		for _, item := range items {
+			mins = append(mins, item.Min)
+			maxs = append(maxs, item.Max)
+			data = append(data, item.Data)
+		}
+		index.Load(mins, maxs, data)
+		for i := range items {
+			// release the data for the garbage collector
+			items[i].Data, data[i] = nil, nil
+		}
+		items, mins, maxs, data = items[:0], mins[:0], maxs[:0], data[:0]
+	}
+	for item := range ch {
+		items = append(items, item)
+		if len(items) == loadChunk {
+			flush()
+		}
+	}
+	if len(items) > 0 {
+		flush()
+	}
+}
+
+// PackHilbert inserts the items into the tree in the order of the Hilbert
+// value of their box centers, so that items that are near each other are
+// inserted together. The tree is loaded using its Load when it is a
+// BulkLoader. The Hilbert curve covers the bounds of the item centers, which
+// produces trees with much better query locality than inserting the items in
+// any arbitrary order. The items slice is not modified.
+func PackHilbert(items []Item, tr Interface) {
+	if len(items) == 0 {
+		return
+	}
+	cmin := [2]float64{math.Inf(1), math.Inf(1)}
+	cmax := [2]float64{math.Inf(-1), math.Inf(-1)}
+	for _, item := range items {
+		for i := 0; i < 2; i++ {
+			c := (item.Min[i] + item.Max[i]) / 2
+			cmin[i] = math.Min(cmin[i], c)
+			cmax[i] = math.Max(cmax[i], c)
+		}
+	}
+	scale := func(c float64, i int) uint32 {
+		if cmax[i] == cmin[i] {
+			return 0
+		}
+		return uint32((c - cmin[i]) / (cmax[i] - cmin[i]) * math.MaxUint32)
+	}
+	order := make([]int, len(items))
+	values := make([]uint64, len(items))
+	for i, item := range items {
+		order[i] = i
+		values[i] = hilbertXY(scale((item.Min[0]+item.Max[0])/2, 0),
+			scale((item.Min[1]+item.Max[1])/2, 1))
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+	mins := make([][2]float64, len(items))
+	maxs := make([][2]float64, len(items))
+	data := make([]interface{}, len(items))
+	for i, j := range order {
+		mins[i], maxs[i], data[i] = items[j].Min, items[j].Max, items[j].Data
+	}
+	Wrap(tr).Load(mins, maxs, data)
+}
+
+// loadItems loads the items into the tree using Index.Load
+func loadItems(tr Interface, items []Item) {
+	mins := make([][2]float64, len(items))
+	maxs := make([][2]float64, len(items))
+	data := make([]interface{}, len(items))
+	for i, item := range items {
+		mins[i], maxs[i], data[i] = item.Min, item.Max, item.Data
+	}
+	Wrap(tr).Load(mins, maxs, data)
+}
+
+// extent is the bounding box of the points that have been added to it
+type extent struct {
+	min, max [2]float64
+	ok       bool
+}
+
+func (e *extent) add(p [2]float64) {
+	if !e.ok {
+		e.min, e.max, e.ok = p, p, true
+		return
+	}
+	e.min = [2]float64{math.Min(e.min[0], p[0]), math.Min(e.min[1], p[1])}
+	e.max = [2]float64{math.Max(e.max[0], p[0]), math.Max(e.max[1], p[1])}
+}
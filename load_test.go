@@ -0,0 +1,136 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// loaderTree is an RTree that counts its bulk loads
+type loaderTree struct {
+	internal.RTree
+	loads int
+}
+
+func (tr *loaderTree) Load(mins, maxs [][2]float64, data []interface{}) {
+	tr.loads++
+	for i := range mins {
+		tr.Insert(mins[i], maxs[i], data[i])
+	}
+}
+
+func TestLoad(t *testing.T) {
+	boxes := randBoxes(1000)
+	var mins, maxs [][2]float64
+	var data []interface{}
+	for i, box := range boxes {
+		mins = append(mins, box.min)
+		maxs = append(maxs, box.max)
+		data = append(data, i)
+	}
+	loader := &loaderTree{}
+	for _, tr := range []Interface{loader, &internal.RTree{}} {
+		index := Wrap(tr)
+		index.Load(mins, maxs, data)
+		if index.Len() != len(boxes) {
+			t.Fatalf("expected %d, got %d", len(boxes), index.Len())
+		}
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if loader.loads != 1 {
+		t.Fatalf("expected %d, got %d", 1, loader.loads)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	Wrap(loader).Load(mins, maxs[:1], data)
+}
+
+func TestLoadStream(t *testing.T) {
+	boxes := randBoxes(loadChunk*2 + 100)
+	loader := &loaderTree{}
+	for _, tr := range []Interface{loader, &internal.RTree{}} {
+		ch := make(chan Item, 100)
+		go func() {
+			for i, box := range boxes {
+				ch <- Item{box.min, box.max, i}
+			}
+			close(ch)
+		}()
+		index := Wrap(tr)
+		index.LoadStream(ch)
+		if index.Len() != len(boxes) {
+			t.Fatalf("expected %d, got %d", len(boxes), index.Len())
+		}
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[interface{}]bool)
+		index.Scan(func(min, max [2]float64, data interface{}) bool {
+			i := data.(int)
+			if min != boxes[i].min || max != boxes[i].max || seen[i] {
+				t.Fatalf("unexpected item %d", i)
+			}
+			seen[i] = true
+			return true
+		})
+	}
+	if loader.loads != 3 {
+		t.Fatalf("expected %d, got %d", 3, loader.loads)
+	}
+	ch := make(chan Item)
+	close(ch)
+	Wrap(loader).LoadStream(ch)
+	if loader.loads != 3 {
+		t.Fatalf("expected %d, got %d", 3, loader.loads)
+	}
+}
+
+func TestPackHilbert(t *testing.T) {
+	boxes := randBoxes(1000)
+	var items []Item
+	for i, box := range boxes {
+		items = append(items, Item{box.min, box.max, i})
+	}
+	orig := append([]Item(nil), items...)
+	loader := &loaderTree{}
+	for _, tr := range []Interface{loader, &internal.RTree{}} {
+		PackHilbert(items, tr)
+		index := Wrap(tr)
+		if index.Len() != len(items) {
+			t.Fatalf("expected %d, got %d", len(items), index.Len())
+		}
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[interface{}]bool)
+		index.Scan(func(min, max [2]float64, data interface{}) bool {
+			i := data.(int)
+			if min != boxes[i].min || max != boxes[i].max || seen[i] {
+				t.Fatalf("unexpected item %d", i)
+			}
+			seen[i] = true
+			return true
+		})
+	}
+	if loader.loads != 1 {
+		t.Fatalf("expected %d, got %d", 1, loader.loads)
+	}
+	for i := range items {
+		if items[i] != orig[i] {
+			t.Fatal("items were modified")
+		}
+	}
+	// the same center everywhere
+	p := [2]float64{1, 2}
+	tr := &internal.RTree{}
+	PackHilbert([]Item{{p, p, 1}, {p, p, 2}}, tr)
+	if tr.Len() != 2 {
+		t.Fatalf("expected %d, got %d", 2, tr.Len())
+	}
+	PackHilbert(nil, tr)
+}
@@ -0,0 +1,61 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/child"
+)
+
+// DistancePair is a single entry of a distance matrix, holding the data of
+// an item from each index and the distance between them.
+type DistancePair struct {
+	A, B interface{}
+	Dist float64
+}
+
+// DistanceMatrix returns the distances between all items in index a and all
+// items in index b that are no farther than maxDist from each other. The
+// distance is the planar distance between the item boxes.
+// Both trees are traversed together and any pair of nodes that are farther
+// apart than maxDist are skipped, which avoids comparing every item in a to
+// every item in b.
+func DistanceMatrix(a, b *Index, maxDist float64) []DistancePair {
+	var pairs []DistancePair
+	maxDist2 := maxDist * maxDist
+	var join func(ca, cb child.Child)
+	join = func(ca, cb child.Child) {
+		dist := algo.BoxDistCalc(ca.Min, ca.Max, cb.Min, cb.Max, false)
+		if dist > maxDist2 {
+			return
+		}
+		if ca.Item && cb.Item {
+			pairs = append(pairs, DistancePair{
+				A:    ca.Data,
+				B:    cb.Data,
+				Dist: math.Sqrt(dist),
+			})
+			return
+		}
+		// expand the node with the larger area, or the only node
+		if cb.Item || (!ca.Item && boxArea(ca) >= boxArea(cb)) {
+			for _, c := range a.Children(ca.Data, nil) {
+				join(c, cb)
+			}
+		} else {
+			for _, c := range b.Children(cb.Data, nil) {
+				join(ca, c)
+			}
+		}
+	}
+	for _, ca := range a.Children(nil, nil) {
+		for _, cb := range b.Children(nil, nil) {
+			join(ca, cb)
+		}
+	}
+	return pairs
+}
+
+func boxArea(c child.Child) float64 {
+	return (c.Max[0] - c.Min[0]) * (c.Max[1] - c.Min[1])
+}
@@ -0,0 +1,44 @@
+package geoindex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestDistanceMatrix(t *testing.T) {
+	a := Wrap(&internal.RTree{})
+	b := Wrap(&internal.RTree{})
+	pa := randPoints(1000)
+	pb := randPoints(500)
+	for _, p := range pa {
+		a.Insert(p.min, p.max, p)
+	}
+	for _, p := range pb {
+		b.Insert(p.min, p.max, p)
+	}
+	maxDist := rand.Float64()*10 + 1
+	pairs := DistanceMatrix(a, b, maxDist)
+
+	// brute force
+	expect := make(map[[2]tBox]float64)
+	for _, p := range pa {
+		for _, q := range pb {
+			dist := math.Hypot(p.min[0]-q.min[0], p.min[1]-q.min[1])
+			if dist <= maxDist {
+				expect[[2]tBox{p, q}] = dist
+			}
+		}
+	}
+	if len(pairs) != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), len(pairs))
+	}
+	for _, pair := range pairs {
+		dist, ok := expect[[2]tBox{pair.A.(tBox), pair.B.(tBox)}]
+		if !ok || math.Abs(dist-pair.Dist) > 1e-9 {
+			t.Fatalf("unexpected pair %v", pair)
+		}
+	}
+}
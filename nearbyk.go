@@ -0,0 +1,113 @@
+package geoindex
+
+import (
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/pqueue"
+)
+
+// NearbyK performs a kNN-type operation on the index that returns no more
+// than k items, from the smallest dist to the largest dist. Unlike Nearby,
+// the k best distances of the items that have been seen so far are tracked,
+// and nodes and items that are further away than the kth distance are never
+// queued. This reduces the size of the queue and the number of visited nodes
+// for large trees.
+// See Nearby for a description of the `algo` and `iter` functions. Like
+// NearbyAux, this function always uses the Children of the wrapped tree.
+func (index *Index) NearbyK(
+	k int,
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	if k < 1 {
+		return
+	}
+	defer index.stats.end(opNearby, index.stats.begin())
+	var q queue
+	// best holds the negated distances of the k nearest items seen so far,
+	// so the kth distance is at the top
+	var best pqueue.Queue[struct{}]
+	var children []child.Child
+	children = index.tree.Children(nil, children[:0])
+	for n := 0; n < k; {
+		for _, child := range children {
+			dist := algo(child.Min, child.Max, child.Data, child.Item)
+			if best.Len() == k {
+				_, kth, _ := best.Peek()
+				if dist > -kth {
+					// prune
+					continue
+				}
+			}
+			if child.Item {
+				best.Push(struct{}{}, -dist)
+				if best.Len() > k {
+					best.Pop()
+				}
+			}
+			q.push(qnode{dist: dist, child: child})
+		}
+		children = children[:0]
+		for len(children) == 0 && n < k {
+			node, ok := q.pop()
+			if !ok {
+				// nothing left in queue
+				return
+			}
+			if node.child.Item {
+				n++
+				if !iter(node.child.Min, node.child.Max, node.child.Data,
+					node.dist) {
+					return
+				}
+			} else {
+				// gather more children
+				children = index.tree.Children(node.child.Data, children)
+			}
+		}
+	}
+}
+
+// NearbyMaxDist performs a kNN-type operation on the index that only returns
+// the items that are no further than maxDist, from the smallest dist to the
+// largest dist. Nodes and items that are further away are never queued, and
+// the operation stops once the queue is empty, rather than walking the whole
+// tree in the way that stopping Nearby from the `iter` function would.
+// See Nearby for a description of the `algo` and `iter` functions. Like
+// NearbyAux, this function always uses the Children of the wrapped tree.
+func (index *Index) NearbyMaxDist(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	maxDist float64,
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	defer index.stats.end(opNearby, index.stats.begin())
+	var q queue
+	var children []child.Child
+	children = index.tree.Children(nil, children[:0])
+	for {
+		for _, child := range children {
+			dist := algo(child.Min, child.Max, child.Data, child.Item)
+			if dist > maxDist {
+				// prune
+				continue
+			}
+			q.push(qnode{dist: dist, child: child})
+		}
+		children = children[:0]
+		for len(children) == 0 {
+			node, ok := q.pop()
+			if !ok {
+				// nothing left in queue
+				return
+			}
+			if node.child.Item {
+				if !iter(node.child.Min, node.child.Max, node.child.Data,
+					node.dist) {
+					return
+				}
+			} else {
+				// gather more children
+				children = index.tree.Children(node.child.Data, children)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package geoindex
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbyK(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	// duplicates
+	for i := 0; i < 100; i++ {
+		index.Insert(boxes[0].min, boxes[0].max, -i-1)
+	}
+	target := [2]float64{10, 10}
+	var expect []float64
+	index.Scan(func(min, max [2]float64, data interface{}) bool {
+		expect = append(expect,
+			algo.BoxDistCalc(target, target, min, max, false))
+		return true
+	})
+	sort.Float64s(expect)
+	for _, k := range []int{0, 1, 10, 100, 1000, 20000} {
+		var got []float64
+		index.NearbyK(k, algo.Box(target, target, false, nil),
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				got = append(got, dist)
+				return true
+			},
+		)
+		n := k
+		if n > len(expect) {
+			n = len(expect)
+		}
+		if len(got) != n {
+			t.Fatalf("k %d: expected %d, got %d", k, n, len(got))
+		}
+		for i := range got {
+			if got[i] != expect[i] {
+				t.Fatalf("k %d: result %d: expected %v, got %v",
+					k, i, expect[i], got[i])
+			}
+		}
+	}
+	var count int
+	index.NearbyK(10, algo.Box(target, target, false, nil),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			count++
+			return count < 5
+		},
+	)
+	if count != 5 {
+		t.Fatalf("expected %d, got %d", 5, count)
+	}
+}
+
+func TestNearbyMaxDist(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(10000) {
+		index.Insert(box.min, box.max, i)
+	}
+	target := [2]float64{10, 10}
+	for _, maxDist := range []float64{-1, 0, 1, 10, 100, 1000} {
+		var expect []float64
+		index.Scan(func(min, max [2]float64, data interface{}) bool {
+			dist := algo.BoxDistCalc(target, target, min, max, false)
+			if dist <= maxDist {
+				expect = append(expect, dist)
+			}
+			return true
+		})
+		sort.Float64s(expect)
+		var got []float64
+		var visits, allVisits int
+		calc := algo.Box(target, target, false, nil)
+		index.NearbyMaxDist(
+			func(min, max [2]float64, data interface{}, item bool) float64 {
+				visits++
+				return calc(min, max, data, item)
+			},
+			maxDist,
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				got = append(got, dist)
+				return true
+			},
+		)
+		if len(got) != len(expect) {
+			t.Fatalf("maxDist %v: expected %d, got %d",
+				maxDist, len(expect), len(got))
+		}
+		for i := range got {
+			if got[i] != expect[i] {
+				t.Fatalf("maxDist %v: result %d: expected %v, got %v",
+					maxDist, i, expect[i], got[i])
+			}
+		}
+		index.Nearby(
+			func(min, max [2]float64, data interface{}, item bool) float64 {
+				allVisits++
+				return calc(min, max, data, item)
+			},
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				return true
+			},
+		)
+		if maxDist < 100 && visits >= allVisits {
+			t.Fatalf("maxDist %v: expected fewer than %d visits, got %d",
+				maxDist, allVisits, visits)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package geoindex
+
+import "sort"
+
+// NearbyOptions are the options for Index.NearbyOptions.
+type NearbyOptions struct {
+	// TieBreak orders the items that have the same dist, and should return
+	// true when item a comes before item b. When nil, the items with the same
+	// dist are returned in the order of the wrapped tree.
+	TieBreak func(a, b Item) bool
+	// DistinctData skips the items that have the same data as an earlier
+	// item, such as when a multi-box object was inserted as many items. Only
+	// the nearest item for each data is returned. The data must be
+	// comparable.
+	DistinctData bool
+	// GroupBy returns the group of an item, such as a category, and
+	// PerGroupLimit is the max number of items that are returned for each
+	// group. The nearest items of each group are returned and the rest are
+	// skipped, so "the nearest 3 restaurants per cuisine" is a single
+	// traversal. The groups must be comparable. GroupBy is ignored when
+	// PerGroupLimit is less than one.
+	GroupBy       func(data interface{}) interface{}
+	PerGroupLimit int
+}
+
+// NearbyOptions performs a kNN-type operation on the index, like Nearby,
+// using the provided options. The items with the same dist are gathered
+// together before they are passed to iter, so a TieBreak gives a
+// deterministic order even when many items are at the same distance.
+// See Nearby for a description of the `algo` and `iter` functions.
+func (index *Index) NearbyOptions(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	opts NearbyOptions,
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	var seen map[interface{}]bool
+	if opts.DistinctData {
+		seen = make(map[interface{}]bool)
+	}
+	var groups map[interface{}]int
+	if opts.GroupBy != nil && opts.PerGroupLimit > 0 {
+		groups = make(map[interface{}]int)
+	}
+	emit := func(min, max [2]float64, data interface{}, dist float64) bool {
+		if seen != nil {
+			if seen[data] {
+				return true
+			}
+			seen[data] = true
+		}
+		if groups != nil {
+			group := opts.GroupBy(data)
+			if groups[group] == opts.PerGroupLimit {
+				return true
+			}
+			groups[group]++
+		}
+		return iter(min, max, data, dist)
+	}
+	if opts.TieBreak == nil {
+		index.Nearby(algo, emit)
+		return
+	}
+	var group []Result // items with the same dist
+	flush := func() bool {
+		sort.SliceStable(group, func(i, j int) bool {
+			return opts.TieBreak(
+				Item{group[i].Min, group[i].Max, group[i].Data},
+				Item{group[j].Min, group[j].Max, group[j].Data},
+			)
+		})
+		for _, r := range group {
+			if !emit(r.Min, r.Max, r.Data, r.Dist) {
+				return false
+			}
+		}
+		group = group[:0]
+		return true
+	}
+	done := false
+	index.Nearby(algo,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			if len(group) > 0 && dist != group[0].Dist && !flush() {
+				done = true
+				return false
+			}
+			group = append(group, Result{min, max, data, dist})
+			return true
+		},
+	)
+	if !done {
+		flush()
+	}
+}
@@ -0,0 +1,98 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbyOptions(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 1000; i++ {
+		// lots of items with the same distance, and each data in 2 items
+		p := [2]float64{float64(i % 10), 0}
+		index.Insert(p, p, i%500)
+	}
+	target := [2]float64{0, 0}
+	algo := algo.Box(target, target, false, nil)
+	// the data in descending order for the same dist
+	tieBreak := func(a, b Item) bool {
+		return a.Data.(int) > b.Data.(int)
+	}
+	var results []Result
+	index.NearbyOptions(algo, NearbyOptions{TieBreak: tieBreak},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			results = append(results, Result{min, max, data, dist})
+			return true
+		},
+	)
+	if len(results) != 1000 {
+		t.Fatalf("expected %d, got %d", 1000, len(results))
+	}
+	for i := 1; i < len(results); i++ {
+		a, b := results[i-1], results[i]
+		if a.Dist > b.Dist || (a.Dist == b.Dist &&
+			a.Data.(int) < b.Data.(int)) {
+			t.Fatalf("unexpected order at %d: %v %v", i, a, b)
+		}
+	}
+	for _, opts := range []NearbyOptions{
+		{DistinctData: true},
+		{DistinctData: true, TieBreak: tieBreak},
+	} {
+		seen := make(map[interface{}]bool)
+		index.NearbyOptions(algo, opts,
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				if seen[data] {
+					t.Fatalf("duplicate data %v", data)
+				}
+				seen[data] = true
+				return true
+			},
+		)
+		if len(seen) != 500 {
+			t.Fatalf("expected %d, got %d", 500, len(seen))
+		}
+	}
+	// stop in the middle of a group
+	var count int
+	index.NearbyOptions(algo, NearbyOptions{TieBreak: tieBreak},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			count++
+			return count < 150
+		},
+	)
+	if count != 150 {
+		t.Fatalf("expected %d, got %d", 150, count)
+	}
+}
+
+func TestNearbyGroupBy(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{float64(i), 0}
+		index.Insert(p, p, i)
+	}
+	target := [2]float64{0, 0}
+	opts := NearbyOptions{
+		GroupBy:       func(data interface{}) interface{} { return data.(int) % 7 },
+		PerGroupLimit: 3,
+	}
+	var results []int
+	index.NearbyOptions(algo.Box(target, target, false, nil), opts,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			results = append(results, data.(int))
+			return true
+		},
+	)
+	// the nearest 3 of each group, which are the first 21 items
+	if len(results) != 21 {
+		t.Fatalf("expected %d, got %d", 21, len(results))
+	}
+	for i, data := range results {
+		if data != i {
+			t.Fatalf("expected %d, got %d", i, data)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package geoindex
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// OpStat holds the statistics for one type of operation.
+type OpStat struct {
+	// Count is the number of operations.
+	Count uint64
+	// Elapsed is the total time spent in the operations, including the time
+	// spent in the caller's iter functions. This is only collected while
+	// timing is enabled, see SetOpTiming.
+	Elapsed time.Duration
+}
+
+// OpStats are the operation statistics of an Index since it was created.
+// Nearby includes the NearbyAux operations, and Search includes the
+// SearchSorted operations. Load counts each call to Load, including the
+// calls made by LoadStream, and not the individual items.
+type OpStats struct {
+	Insert  OpStat
+	Delete  OpStat
+	Replace OpStat
+	Load    OpStat
+	Search  OpStat
+	Scan    OpStat
+	Nearby  OpStat
+}
+
+const (
+	opInsert = iota
+	opDelete
+	opReplace
+	opLoad
+	opSearch
+	opScan
+	opNearby
+	numOps
+)
+
+// opStats must be the first field of the Index, and the 64-bit fields must
+// come first, so that they're 64-bit aligned for the atomic operations on
+// 32-bit platforms.
+type opStats struct {
+	counts  [numOps]uint64
+	elapsed [numOps]int64
+	timing  int32
+}
+
+// begin starts an operation, returning the start time when timing is enabled
+func (s *opStats) begin() time.Time {
+	if atomic.LoadInt32(&s.timing) == 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (s *opStats) end(op int, start time.Time) {
+	atomic.AddUint64(&s.counts[op], 1)
+	if !start.IsZero() {
+		atomic.AddInt64(&s.elapsed[op], int64(time.Since(start)))
+	}
+}
+
+// SetOpTiming enables or disables the collection of the elapsed times for
+// the OpStats. Timing is disabled by default, because reading the clock for
+// every operation isn't free. The counts are always collected.
+func (index *Index) SetOpTiming(enabled bool) {
+	var timing int32
+	if enabled {
+		timing = 1
+	}
+	atomic.StoreInt32(&index.stats.timing, timing)
+}
+
+// OpStats returns the cumulative operation statistics for the index. It's
+// safe to call concurrently with other operations.
+func (index *Index) OpStats() OpStats {
+	stat := func(op int) OpStat {
+		return OpStat{
+			Count:   atomic.LoadUint64(&index.stats.counts[op]),
+			Elapsed: time.Duration(atomic.LoadInt64(&index.stats.elapsed[op])),
+		}
+	}
+	return OpStats{
+		Insert:  stat(opInsert),
+		Delete:  stat(opDelete),
+		Replace: stat(opReplace),
+		Load:    stat(opLoad),
+		Search:  stat(opSearch),
+		Scan:    stat(opScan),
+		Nearby:  stat(opNearby),
+	}
+}
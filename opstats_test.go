@@ -0,0 +1,67 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestOpStats(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	iter := func(min, max [2]float64, data interface{}) bool { return true }
+	for i := 0; i < 10; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		index.Insert(p, p, i)
+	}
+	index.Delete([2]float64{0, 0}, [2]float64{0, 0}, 0)
+	index.Search([2]float64{0, 0}, [2]float64{5, 5}, iter)
+	index.Scan(iter)
+	index.Nearby(
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			return 0
+		},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			return true
+		},
+	)
+	stats := index.OpStats()
+	if stats.Insert.Count != 10 || stats.Delete.Count != 1 ||
+		stats.Search.Count != 1 || stats.Scan.Count != 1 ||
+		stats.Nearby.Count != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.Insert.Elapsed != 0 {
+		t.Fatal("expected no timing")
+	}
+	index.SetOpTiming(true)
+	for i := 0; i < 100; i++ {
+		index.Search([2]float64{0, 0}, [2]float64{5, 5}, iter)
+	}
+	index.SetOpTiming(false)
+	index.Search([2]float64{0, 0}, [2]float64{5, 5}, iter)
+	stats2 := index.OpStats()
+	if stats2.Search.Count != 102 || stats2.Search.Elapsed <= 0 {
+		t.Fatalf("unexpected stats %+v", stats2)
+	}
+	if stats2.Insert != stats.Insert {
+		t.Fatalf("expected %v, got %v", stats.Insert, stats2.Insert)
+	}
+}
+
+func TestOpStatsLoadReplace(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	index.Load(
+		[][2]float64{{0, 0}, {1, 1}, {2, 2}},
+		[][2]float64{{0, 0}, {1, 1}, {2, 2}},
+		[]interface{}{0, 1, 2},
+	)
+	index.Replace([2]float64{0, 0}, [2]float64{0, 0}, 0,
+		[2]float64{3, 3}, [2]float64{3, 3}, 3)
+	index.SearchSorted([2]float64{0, 0}, [2]float64{5, 5}, OrderArea,
+		func(min, max [2]float64, data interface{}) bool { return true })
+	stats := index.OpStats()
+	if stats.Load.Count != 1 || stats.Insert.Count != 0 ||
+		stats.Replace.Count != 1 || stats.Search.Count != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,168 @@
+// Package orbindex provides a geoindex.Interface for a quadtree from the
+// github.com/paulmach/orb package.
+package orbindex
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/quadtree"
+	"github.com/tidwall/geoindex/child"
+)
+
+// item is a box stored in the quadtree at its center point
+type item struct {
+	min, max [2]float64
+	data     interface{}
+}
+
+func (it *item) Point() orb.Point {
+	return orb.Point{(it.min[0] + it.max[0]) / 2, (it.min[1] + it.max[1]) / 2}
+}
+
+// Quadtree is a geoindex.Interface that stores its items in an orb
+// quadtree.Quadtree.
+// The quadtree only holds points, so each item is stored at the center of its
+// box, and searches are expanded by the largest box size that has been
+// inserted. Items must be within the bounds of the quadtree, otherwise Insert
+// will panic. The Children are a single root node holding all items.
+type Quadtree struct {
+	qt    *quadtree.Quadtree
+	count int
+	ext   [2]float64 // largest half size of inserted boxes
+}
+
+// New returns a new Quadtree for the bound.
+func New(bound orb.Bound) *Quadtree {
+	return Wrap(quadtree.New(bound))
+}
+
+// Wrap returns a Quadtree for an existing quadtree.Quadtree. The pointers
+// already in the quadtree become items with their point as the box and the
+// pointer as the data.
+func Wrap(qt *quadtree.Quadtree) *Quadtree {
+	return &Quadtree{qt: qt, count: len(qt.InBound(nil, qt.Bound()))}
+}
+
+// Quadtree returns the underlying quadtree.Quadtree.
+func (q *Quadtree) Quadtree() *quadtree.Quadtree {
+	return q.qt
+}
+
+func unpack(p orb.Pointer) (min, max [2]float64, data interface{}) {
+	if it, ok := p.(*item); ok {
+		return it.min, it.max, it.data
+	}
+	pt := p.Point()
+	return pt, pt, p
+}
+
+// Insert an item into the index
+func (q *Quadtree) Insert(min, max [2]float64, data interface{}) {
+	if err := q.qt.Add(&item{min: min, max: max, data: data}); err != nil {
+		panic(err)
+	}
+	q.ext[0] = math.Max(q.ext[0], (max[0]-min[0])/2)
+	q.ext[1] = math.Max(q.ext[1], (max[1]-min[1])/2)
+	q.count++
+}
+
+// Delete an item from the index
+func (q *Quadtree) Delete(min, max [2]float64, data interface{}) {
+	target := &item{min: min, max: max, data: data}
+	if q.qt.Remove(target, func(p orb.Pointer) bool {
+		pmin, pmax, pdata := unpack(p)
+		return pmin == min && pmax == max && pdata == data
+	}) {
+		q.count--
+	}
+}
+
+// Replace an item in the index
+func (q *Quadtree) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	q.Delete(oldMin, oldMax, oldData)
+	q.Insert(newMin, newMax, newData)
+}
+
+// Search the index for items that intersects the rect param
+func (q *Quadtree) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	bound := orb.Bound{
+		Min: orb.Point{min[0] - q.ext[0], min[1] - q.ext[1]},
+		Max: orb.Point{max[0] + q.ext[0], max[1] + q.ext[1]},
+	}
+	for _, p := range q.qt.InBound(nil, bound) {
+		pmin, pmax, data := unpack(p)
+		if pmin[0] > max[0] || pmax[0] < min[0] ||
+			pmin[1] > max[1] || pmax[1] < min[1] {
+			continue
+		}
+		if !iter(pmin, pmax, data) {
+			return
+		}
+	}
+}
+
+// Scan iterates through all data in the index in no specified order.
+func (q *Quadtree) Scan(iter func(min, max [2]float64, data interface{}) bool) {
+	for _, p := range q.qt.InBound(nil, q.qt.Bound()) {
+		if !iter(unpack(p)) {
+			return
+		}
+	}
+}
+
+// Len returns the number of items in the index
+func (q *Quadtree) Len() int {
+	return q.count
+}
+
+// Bounds returns the minimum bounding box
+func (q *Quadtree) Bounds() (min, max [2]float64) {
+	var i int
+	q.Scan(func(imin, imax [2]float64, _ interface{}) bool {
+		if i == 0 {
+			min, max = imin, imax
+		} else {
+			for j := 0; j < 2; j++ {
+				min[j] = math.Min(min[j], imin[j])
+				max[j] = math.Max(max[j], imax[j])
+			}
+		}
+		i++
+		return true
+	})
+	return min, max
+}
+
+// root is the single synthesized node holding all items
+type root struct{}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root node is returned.
+func (q *Quadtree) Children(parent interface{}, reuse []child.Child) (
+	children []child.Child,
+) {
+	children = reuse
+	if parent == nil {
+		if q.count > 0 {
+			min, max := q.Bounds()
+			children = append(children, child.Child{
+				Min: min, Max: max, Data: root{},
+			})
+		}
+		return children
+	}
+	q.Scan(func(min, max [2]float64, data interface{}) bool {
+		children = append(children, child.Child{
+			Min: min, Max: max, Data: data, Item: true,
+		})
+		return true
+	})
+	return children
+}
@@ -0,0 +1,52 @@
+package orbindex
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/quadtree"
+	"github.com/tidwall/geoindex"
+)
+
+// the conformance tests may move items slightly outside of the world bounds
+var world = orb.Bound{Min: orb.Point{-181, -91}, Max: orb.Point{181, 91}}
+
+func TestGeoIndex(t *testing.T) {
+	t.Run("RandomRects", func(t *testing.T) {
+		geoindex.Tests.TestRandomRects(t, New(world), 10000)
+	})
+	t.Run("RandomPoints", func(t *testing.T) {
+		geoindex.Tests.TestRandomPoints(t, New(world), 10000)
+	})
+	t.Run("CitiesSVG", func(t *testing.T) {
+		geoindex.Tests.TestCitiesSVG(t, New(world))
+	})
+}
+
+func TestWrap(t *testing.T) {
+	qt := quadtree.New(world)
+	for i := 0; i < 100; i++ {
+		qt.Add(orb.Point{float64(i), float64(i) / 2})
+	}
+	q := Wrap(qt)
+	if q.Len() != 100 {
+		t.Fatalf("expected %d, got %d", 100, q.Len())
+	}
+	var count int
+	q.Search([2]float64{10, 0}, [2]float64{19, 90},
+		func(min, max [2]float64, data interface{}) bool {
+			if _, ok := data.(orb.Point); !ok {
+				t.Fatalf("unexpected data %v", data)
+			}
+			count++
+			return true
+		},
+	)
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+	q.Delete([2]float64{10, 5}, [2]float64{10, 5}, orb.Point{10, 5})
+	if q.Len() != 99 {
+		t.Fatalf("expected %d, got %d", 99, q.Len())
+	}
+}
@@ -0,0 +1,173 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// OversizeTree is an Interface that keeps very large items, such as world
+// spanning geofences, in a small side list rather than in the wrapped tree.
+// A few huge items can otherwise cause most of the nodes of a tree to
+// overlap, which destroys the pruning for all queries. The side list is
+// checked by every query.
+//
+// The Children of the root are the root nodes of the wrapped tree followed by
+// a node for the items in the side list. The side node is a chain of nodes
+// with the same height as the wrapped tree, so that all of the items are at
+// the same depth and no node has both items and nodes.
+type OversizeTree struct {
+	tree    Interface
+	maxSize float64
+	side    []child.Child
+}
+
+// NewOversizeTree returns an OversizeTree for an empty tree. Items that have
+// a width or height larger than maxSize are put in the side list.
+func NewOversizeTree(tree Interface, maxSize float64) *OversizeTree {
+	return &OversizeTree{tree: tree, maxSize: maxSize}
+}
+
+func (tr *OversizeTree) oversized(min, max [2]float64) bool {
+	return max[0]-min[0] > tr.maxSize || max[1]-min[1] > tr.maxSize
+}
+
+// Oversized returns the number of items in the side list.
+func (tr *OversizeTree) Oversized() int {
+	return len(tr.side)
+}
+
+// Insert an item into the tree
+func (tr *OversizeTree) Insert(min, max [2]float64, data interface{}) {
+	if tr.oversized(min, max) {
+		tr.side = append(tr.side, child.Child{
+			Min: min, Max: max, Data: data, Item: true,
+		})
+		return
+	}
+	tr.tree.Insert(min, max, data)
+}
+
+// Delete an item from the tree
+func (tr *OversizeTree) Delete(min, max [2]float64, data interface{}) {
+	if !tr.oversized(min, max) {
+		tr.tree.Delete(min, max, data)
+		return
+	}
+	for i, c := range tr.side {
+		if c.Min == min && c.Max == max && c.Data == data {
+			tr.side[i] = tr.side[len(tr.side)-1]
+			tr.side[len(tr.side)-1] = child.Child{}
+			tr.side = tr.side[:len(tr.side)-1]
+			return
+		}
+	}
+}
+
+// Replace an item in the tree
+func (tr *OversizeTree) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	if !tr.oversized(oldMin, oldMax) && !tr.oversized(newMin, newMax) {
+		tr.tree.Replace(oldMin, oldMax, oldData, newMin, newMax, newData)
+		return
+	}
+	tr.Delete(oldMin, oldMax, oldData)
+	tr.Insert(newMin, newMax, newData)
+}
+
+// Search the tree for items that intersects the rect param
+func (tr *OversizeTree) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	for _, c := range tr.side {
+		if intersects(min, max, c.Min, c.Max) {
+			if !iter(c.Min, c.Max, c.Data) {
+				return
+			}
+		}
+	}
+	tr.tree.Search(min, max, iter)
+}
+
+func intersects(aMin, aMax, bMin, bMax [2]float64) bool {
+	return !(bMin[0] > aMax[0] || bMax[0] < aMin[0] ||
+		bMin[1] > aMax[1] || bMax[1] < aMin[1])
+}
+
+// Scan iterates through all data in tree in no specified order.
+func (tr *OversizeTree) Scan(
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	for _, c := range tr.side {
+		if !iter(c.Min, c.Max, c.Data) {
+			return
+		}
+	}
+	tr.tree.Scan(iter)
+}
+
+// Len returns the number of items in tree
+func (tr *OversizeTree) Len() int {
+	return tr.tree.Len() + len(tr.side)
+}
+
+// Bounds returns the minimum bounding box
+func (tr *OversizeTree) Bounds() (min, max [2]float64) {
+	if tr.tree.Len() > 0 {
+		min, max = tr.tree.Bounds()
+	} else if len(tr.side) > 0 {
+		min, max = tr.side[0].Min, tr.side[0].Max
+	}
+	for _, c := range tr.side {
+		min, max = expand(min, max, c.Min, c.Max)
+	}
+	return min, max
+}
+
+// oversizeNode is a node of the chain that holds the side list, where the
+// children of the last node of the chain are the items in the side list.
+type oversizeNode struct {
+	height int
+}
+
+// height returns the number of levels of nodes above the items of the
+// wrapped tree.
+func (tr *OversizeTree) height() int {
+	var height int
+	children := tr.tree.Children(nil, nil)
+	for len(children) > 0 && !children[0].Item {
+		height++
+		children = tr.tree.Children(children[0].Data, nil)
+	}
+	return height
+}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root nodes should be returned.
+func (tr *OversizeTree) Children(parent interface{}, reuse []child.Child) (
+	children []child.Child,
+) {
+	if n, ok := parent.(oversizeNode); ok {
+		if n.height == 1 {
+			return append(reuse, tr.side...)
+		}
+		return append(reuse, tr.sideNode(n.height-1))
+	}
+	children = tr.tree.Children(parent, reuse)
+	if parent == nil && len(tr.side) > 0 {
+		if height := tr.height(); height == 0 {
+			children = append(children, tr.side...)
+		} else {
+			children = append(children, tr.sideNode(height))
+		}
+	}
+	return children
+}
+
+// sideNode returns the child for a node of the side list chain
+func (tr *OversizeTree) sideNode(height int) child.Child {
+	min, max := tr.side[0].Min, tr.side[0].Max
+	for _, c := range tr.side[1:] {
+		min, max = expand(min, max, c.Min, c.Max)
+	}
+	return child.Child{Min: min, Max: max, Data: oversizeNode{height}}
+}
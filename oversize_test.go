@@ -0,0 +1,81 @@
+package geoindex
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestOversizeTree(t *testing.T) {
+	t.Run("RandomRects", func(t *testing.T) {
+		Tests.TestRandomRects(t, NewOversizeTree(&internal.RTree{}, 0.005),
+			10000)
+	})
+	t.Run("ChildrenInvariants", func(t *testing.T) {
+		tr := NewOversizeTree(&internal.RTree{}, 10)
+		tr.Insert([2]float64{-180, -90}, [2]float64{180, 90}, "world")
+		Tests.TestChildrenInvariants(t, tr, 10000)
+		if tr.Oversized() != 1 {
+			t.Fatalf("expected %d, got %d", 1, tr.Oversized())
+		}
+	})
+	tr := NewOversizeTree(&internal.RTree{}, 10)
+	plain := &internal.RTree{}
+	boxes := randBoxes(10000)
+	for i := 0; i < 20; i++ {
+		// huge geofences
+		min := [2]float64{-170 + float64(i), -80}
+		boxes = append(boxes, tBox{min: min, max: [2]float64{170, 80}})
+	}
+	for i, box := range boxes {
+		tr.Insert(box.min, box.max, i)
+		plain.Insert(box.min, box.max, i)
+	}
+	if tr.Oversized() != 20 || tr.Len() != len(boxes) {
+		t.Fatalf("expected %d/%d, got %d/%d",
+			20, len(boxes), tr.Oversized(), tr.Len())
+	}
+	index := Wrap(tr)
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := index.Analyze(), Wrap(plain).Analyze(); a.Levels[1].Overlap >=
+		b.Levels[1].Overlap {
+		t.Fatalf("expected less overlap, got %v >= %v",
+			a.Levels[1].Overlap, b.Levels[1].Overlap)
+	}
+	search := func(tr Interface, min, max [2]float64) []int {
+		var ids []int
+		tr.Search(min, max, func(_, _ [2]float64, data interface{}) bool {
+			ids = append(ids, data.(int))
+			return true
+		})
+		sort.Ints(ids)
+		return ids
+	}
+	for i := 0; i < 100; i++ {
+		min := [2]float64{float64(i*3 - 180), float64(i - 50)}
+		max := [2]float64{min[0] + 5, min[1] + 5}
+		a, b := search(tr, min, max), search(plain, min, max)
+		if len(a) != len(b) {
+			t.Fatalf("expected %d, got %d", len(b), len(a))
+		}
+		for j := range a {
+			if a[j] != b[j] {
+				t.Fatalf("expected %d, got %d", b[j], a[j])
+			}
+		}
+	}
+	for i := len(boxes) - 1; i >= 0; i-- {
+		tr.Delete(boxes[i].min, boxes[i].max, i)
+		if i%1000 == 0 {
+			if err := index.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if tr.Len() != 0 || tr.Oversized() != 0 {
+		t.Fatalf("expected %d, got %d", 0, tr.Len())
+	}
+}
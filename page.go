@@ -0,0 +1,209 @@
+package geoindex
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrBadCursor is returned by NearbyPage and ParseCursor when the cursor is
+// not valid.
+var ErrBadCursor = errors.New("geoindex: bad cursor")
+
+type pageCursor struct {
+	dist float64
+	key  uint64
+}
+
+func (c pageCursor) encode() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], math.Float64bits(c.dist))
+	binary.BigEndian.PutUint64(b[8:], c.key)
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != 16 {
+		return pageCursor{}, ErrBadCursor
+	}
+	return pageCursor{
+		dist: math.Float64frombits(binary.BigEndian.Uint64(b[:8])),
+		key:  binary.BigEndian.Uint64(b[8:]),
+	}, nil
+}
+
+// NearbyPage returns a page of up to limit items of a kNN-type operation,
+// from the smallest dist to the largest dist, and a cursor for the next page.
+// Pass an empty cursor for the first page. The returned cursor is empty when
+// there are no more items.
+//
+// The cursor is an opaque string that holds the dist and the Fingerprint of
+// the last item of the page, which makes it suitable for stateless APIs that
+// paginate across requests. Items with the same dist are ordered by their
+// Fingerprint, so the pages have a stable order as long as the index isn't
+// modified. Items that have both the same dist and Fingerprint may be skipped.
+// See Nearby for a description of the `algo` function.
+func (index *Index) NearbyPage(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	cursor string, limit int,
+) (page []Result, next string, err error) {
+	var start pageCursor
+	if cursor != "" {
+		if start, err = decodeCursor(cursor); err != nil {
+			return nil, "", err
+		}
+	}
+	if limit < 1 {
+		return nil, cursor, nil
+	}
+	skip := func(dist float64, key uint64) bool {
+		return cursor != "" &&
+			(dist < start.dist || (dist == start.dist && key <= start.key))
+	}
+	var last pageCursor
+	var group pageGroup // items with the same dist
+	more := false
+	flush := func() {
+		sort.Sort(&group)
+		for i, r := range group.results {
+			if skip(r.Dist, group.keys[i]) {
+				continue
+			}
+			if len(page) == limit {
+				more = true
+				break
+			}
+			page = append(page, r)
+			last = pageCursor{r.Dist, group.keys[i]}
+		}
+		group.results, group.keys = group.results[:0], group.keys[:0]
+	}
+	index.Nearby(algo,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			if cursor != "" && dist < start.dist {
+				return true
+			}
+			if len(group.results) > 0 && dist != group.results[0].Dist {
+				flush()
+				if len(page) == limit {
+					more = true
+					return false
+				}
+			}
+			group.results = append(group.results,
+				Result{min, max, data, dist})
+			group.keys = append(group.keys, Fingerprint(data))
+			return true
+		},
+	)
+	if !more {
+		flush()
+	}
+	if !more {
+		return page, "", nil
+	}
+	return page, last.encode(), nil
+}
+
+type pageGroup struct {
+	results []Result
+	keys    []uint64
+}
+
+func (g *pageGroup) Len() int           { return len(g.keys) }
+func (g *pageGroup) Less(i, j int) bool { return g.keys[i] < g.keys[j] }
+func (g *pageGroup) Swap(i, j int) {
+	g.results[i], g.results[j] = g.results[j], g.results[i]
+	g.keys[i], g.keys[j] = g.keys[j], g.keys[i]
+}
+
+// Cursor is a position in the results of SearchPage. The zero Cursor is the
+// start of the results.
+type Cursor struct {
+	hilbert uint64
+	key     uint64
+	valid   bool
+}
+
+// String returns the cursor as an opaque string, which can be parsed with
+// ParseCursor. The zero Cursor is an empty string.
+func (c Cursor) String() string {
+	if !c.valid {
+		return ""
+	}
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], c.hilbert)
+	binary.BigEndian.PutUint64(b[8:], c.key)
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// ParseCursor parses a cursor that was returned by Cursor.String. An empty
+// string is the zero Cursor.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != 16 {
+		return Cursor{}, ErrBadCursor
+	}
+	return Cursor{
+		hilbert: binary.BigEndian.Uint64(b[:8]),
+		key:     binary.BigEndian.Uint64(b[8:]),
+		valid:   true,
+	}, nil
+}
+
+type searchPageItem struct {
+	item         Item
+	hilbert, key uint64
+}
+
+// SearchPage returns a page of up to limit items that intersect the rect
+// param, and a cursor for the next page. Pass the zero Cursor for the first
+// page. The returned cursor is the zero Cursor when there are no more items.
+//
+// The items are ordered by the Hilbert value of the center of their boxes,
+// and then by their Fingerprint, so the pages have a deterministic order that
+// doesn't depend on the structure of the tree. Items that are inserted or
+// deleted between pages don't cause other items to be skipped or repeated,
+// except for items that have both the same center and Fingerprint.
+func (index *Index) SearchPage(
+	min, max [2]float64, cursor Cursor, limit int,
+) (results []Item, next Cursor) {
+	if limit < 1 {
+		return nil, cursor
+	}
+	var items []searchPageItem
+	index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		h := hilbertBox(min, max)
+		if cursor.valid && h < cursor.hilbert {
+			return true
+		}
+		key := Fingerprint(data)
+		if cursor.valid && h == cursor.hilbert && key <= cursor.key {
+			return true
+		}
+		items = append(items, searchPageItem{Item{min, max, data}, h, key})
+		return true
+	})
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].hilbert != items[j].hilbert {
+			return items[i].hilbert < items[j].hilbert
+		}
+		return items[i].key < items[j].key
+	})
+	if len(items) > limit {
+		last := items[limit-1]
+		next = Cursor{last.hilbert, last.key, true}
+		items = items[:limit]
+	}
+	results = make([]Item, len(items))
+	for i := range items {
+		results[i] = items[i].item
+	}
+	return results, next
+}
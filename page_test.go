@@ -0,0 +1,134 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbyPage(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 1000; i++ {
+		// lots of items with the same distance
+		p := [2]float64{float64(i % 37), float64(i % 37)}
+		index.Insert(p, p, i)
+	}
+	algo := func(min, max [2]float64, data interface{}, item bool) float64 {
+		return min[0]
+	}
+	for _, limit := range []int{1, 7, 27, 100, 1000, 2000} {
+		seen := make(map[interface{}]bool)
+		var cursor string
+		var last float64
+		var pages int
+		for {
+			page, next, err := index.NearbyPage(algo, cursor, limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages++
+			if len(page) > limit || (next != "" && len(page) != limit) {
+				t.Fatalf("limit %d: unexpected page size %d",
+					limit, len(page))
+			}
+			for _, r := range page {
+				if seen[r.Data] {
+					t.Fatalf("limit %d: duplicate %v", limit, r.Data)
+				}
+				if r.Dist < last {
+					t.Fatalf("limit %d: out of order", limit)
+				}
+				seen[r.Data] = true
+				last = r.Dist
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		if len(seen) != 1000 {
+			t.Fatalf("limit %d: expected %d, got %d", limit, 1000, len(seen))
+		}
+		if expect := (1000 + limit - 1) / limit; limit < 1000 &&
+			pages != expect && pages != expect+1 {
+			t.Fatalf("limit %d: expected %d pages, got %d",
+				limit, expect, pages)
+		}
+	}
+	if _, _, err := index.NearbyPage(algo, "bad!", 10); err != ErrBadCursor {
+		t.Fatalf("expected %v, got %v", ErrBadCursor, err)
+	}
+	if page, _, _ := Wrap(&internal.RTree{}).NearbyPage(algo, "", 10); len(
+		page) != 0 {
+		t.Fatal("expected no results")
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(1000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	// some items with the same center
+	for i := 0; i < 50; i++ {
+		p := [2]float64{1, 1}
+		index.Insert(p, p, len(boxes)+i)
+	}
+	min, max := [2]float64{-90, -45}, [2]float64{90, 45}
+	var expect int
+	index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		expect++
+		return true
+	})
+	var order []interface{}
+	for _, limit := range []int{1, 7, 100, 2000} {
+		seen := make(map[interface{}]bool)
+		var all []interface{}
+		var cursor Cursor
+		for {
+			page, next := index.SearchPage(min, max, cursor, limit)
+			if len(page) > limit || (next != (Cursor{}) && len(page) != limit) {
+				t.Fatalf("unexpected page size %d", len(page))
+			}
+			for _, item := range page {
+				if seen[item.Data] {
+					t.Fatalf("duplicate item %v", item.Data)
+				}
+				seen[item.Data] = true
+				all = append(all, item.Data)
+			}
+			if next == (Cursor{}) {
+				break
+			}
+			s := next.String()
+			if cursor, _ = ParseCursor(s); cursor != next {
+				t.Fatalf("expected %v, got %v", next, cursor)
+			}
+		}
+		if len(all) != expect {
+			t.Fatalf("expected %d, got %d", expect, len(all))
+		}
+		// the order doesn't depend on the page size
+		if order == nil {
+			order = all
+		}
+		for i := range all {
+			if all[i] != order[i] {
+				t.Fatalf("unexpected order at %d", i)
+			}
+		}
+	}
+	if page, next := index.SearchPage(min, max, Cursor{}, 0); page != nil ||
+		next != (Cursor{}) {
+		t.Fatal("expected empty page")
+	}
+	if c, err := ParseCursor(""); err != nil || c != (Cursor{}) {
+		t.Fatal("expected zero cursor")
+	}
+	for _, s := range []string{"!!", "AAAA"} {
+		if _, err := ParseCursor(s); err != ErrBadCursor {
+			t.Fatalf("expected %v, got %v", ErrBadCursor, err)
+		}
+	}
+}
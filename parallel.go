@@ -0,0 +1,91 @@
+package geoindex
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// ScanParallel iterates through all data in the index using multiple
+// goroutines. The tree is split into subtrees at an upper level of the
+// Children hierarchy, and the subtrees are scanned concurrently by the
+// workers. The iter function is called concurrently from different workers,
+// with the workerID being from 0 to workers-1. Returning false from iter will
+// cause all workers to stop.
+//
+// The wrapped tree must be safe for concurrent calls to Children, and it must
+// not be modified during the scan.
+func (index *Index) ScanParallel(workers int,
+	iter func(workerID int, min, max [2]float64, data interface{}) bool,
+) {
+	if workers < 1 {
+		workers = 1
+	}
+	// Split the tree until there are enough subtrees for all of the workers.
+	var items, nodes []child.Child
+	work := index.tree.Children(nil, nil)
+	for {
+		nodes = nodes[:0]
+		for _, c := range work {
+			if c.Item {
+				items = append(items, c)
+			} else {
+				nodes = append(nodes, c)
+			}
+		}
+		if len(nodes) == 0 || len(nodes) >= workers*4 {
+			break
+		}
+		work = work[:0]
+		for _, c := range nodes {
+			work = index.tree.Children(c.Data, work)
+		}
+	}
+	var stop int32
+	jobs := make(chan child.Child, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(workerID int) {
+			defer wg.Done()
+			var walk func(parent interface{}) bool
+			walk = func(parent interface{}) bool {
+				for _, c := range index.tree.Children(parent, nil) {
+					if atomic.LoadInt32(&stop) != 0 {
+						return false
+					}
+					if c.Item {
+						if !iter(workerID, c.Min, c.Max, c.Data) {
+							atomic.StoreInt32(&stop, 1)
+							return false
+						}
+					} else if !walk(c.Data) {
+						return false
+					}
+				}
+				return true
+			}
+			for c := range jobs {
+				if atomic.LoadInt32(&stop) != 0 {
+					continue
+				}
+				if c.Item {
+					if !iter(workerID, c.Min, c.Max, c.Data) {
+						atomic.StoreInt32(&stop, 1)
+					}
+				} else {
+					walk(c.Data)
+				}
+			}
+		}(i)
+	}
+	for _, c := range nodes {
+		jobs <- c
+	}
+	for _, c := range items {
+		jobs <- c
+	}
+	close(jobs)
+	wg.Wait()
+}
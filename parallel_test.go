@@ -0,0 +1,60 @@
+package geoindex
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestScanParallel(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	for _, workers := range []int{0, 1, 4, 100} {
+		var mu sync.Mutex
+		seen := make(map[interface{}]bool)
+		workerIDs := make(map[int]bool)
+		index.ScanParallel(workers,
+			func(workerID int, min, max [2]float64, data interface{}) bool {
+				mu.Lock()
+				defer mu.Unlock()
+				if seen[data] {
+					t.Errorf("duplicate item %v", data)
+				}
+				seen[data] = true
+				workerIDs[workerID] = true
+				return true
+			},
+		)
+		if len(seen) != len(boxes) {
+			t.Fatalf("expected %d, got %d", len(boxes), len(seen))
+		}
+		for id := range workerIDs {
+			if id < 0 || (workers > 0 && id >= workers) ||
+				(workers == 0 && id != 0) {
+				t.Fatalf("unexpected worker id %d", id)
+			}
+		}
+	}
+	// stop early
+	var count int32
+	index.ScanParallel(4,
+		func(workerID int, min, max [2]float64, data interface{}) bool {
+			return atomic.AddInt32(&count, 1) < 100
+		},
+	)
+	if n := atomic.LoadInt32(&count); n < 100 || n > 200 {
+		t.Fatalf("expected about %d, got %d", 100, n)
+	}
+	// empty
+	Wrap(&internal.RTree{}).ScanParallel(4,
+		func(workerID int, min, max [2]float64, data interface{}) bool {
+			t.Fatal("expected no items")
+			return true
+		},
+	)
+}
@@ -0,0 +1,47 @@
+package geoindex
+
+import (
+	"sync"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// maxPooled is the largest capacity of a buffer that is returned to a pool.
+// Larger buffers are left for the garbage collector, so that one very large
+// operation doesn't hold on to its memory for the life of the program.
+const maxPooled = 1 << 16
+
+// queuePool holds the queues of the Nearby operations
+var queuePool = sync.Pool{New: func() interface{} { return new(Queue) }}
+
+func getQueue() *Queue {
+	return queuePool.Get().(*Queue)
+}
+
+func putQueue(q *Queue) {
+	if q.q.Cap() > maxPooled || cap(q.children) > maxPooled {
+		return
+	}
+	q.Reset()
+	queuePool.Put(q)
+}
+
+// childrenPool holds the children buffers of the tree traversals
+var childrenPool = sync.Pool{
+	New: func() interface{} { return new([]child.Child) },
+}
+
+func getChildren() *[]child.Child {
+	return childrenPool.Get().(*[]child.Child)
+}
+
+func putChildren(buf *[]child.Child) {
+	if cap(*buf) > maxPooled {
+		return
+	}
+	for i := range *buf {
+		(*buf)[i] = child.Child{}
+	}
+	*buf = (*buf)[:0]
+	childrenPool.Put(buf)
+}
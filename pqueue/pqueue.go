@@ -0,0 +1,159 @@
+// Package pqueue provides a priority queue that is ordered by smallest
+// priority first. It's intended for implementing the kNN operation of a tree,
+// where the priority is the distance from the target.
+package pqueue
+
+type entry[T any] struct {
+	prio  float64
+	seq   uint64
+	value T
+}
+
+// Queue is a priority queue that pops values with the smallest priority
+// first. Values with the same priority are popped in the order that they were
+// pushed.
+//
+// The zero value is an empty unbounded queue that is ready to use.
+type Queue[T any] struct {
+	entries []entry[T]
+	seq     uint64
+	limit   int
+}
+
+// NewBounded returns a queue that holds no more than limit values. When the
+// queue is full, pushing a value will drop the value with the largest
+// priority, which may be the pushed value. A limit of zero or less is
+// unbounded.
+func NewBounded[T any](limit int) *Queue[T] {
+	return &Queue[T]{limit: limit}
+}
+
+func (q *Queue[T]) less(i, j int) bool {
+	a, b := &q.entries[i], &q.entries[j]
+	return a.prio < b.prio || (a.prio == b.prio && a.seq < b.seq)
+}
+
+// Len returns the number of values in the queue
+func (q *Queue[T]) Len() int {
+	return len(q.entries)
+}
+
+// Cap returns the number of values that the queue can hold without
+// allocating more space.
+func (q *Queue[T]) Cap() int {
+	return cap(q.entries)
+}
+
+// Limit returns the maximum number of values for a bounded queue, or zero if
+// the queue is unbounded.
+func (q *Queue[T]) Limit() int {
+	if q.limit < 0 {
+		return 0
+	}
+	return q.limit
+}
+
+// Reset removes all values from the queue, keeping the allocated space for
+// reuse.
+func (q *Queue[T]) Reset() {
+	var empty T
+	for i := range q.entries {
+		q.entries[i].value = empty
+	}
+	q.entries = q.entries[:0]
+	q.seq = 0
+}
+
+// Push a value onto the queue. Returns false if the queue is bounded and the
+// value was dropped because the queue is full of values with smaller priority.
+func (q *Queue[T]) Push(value T, priority float64) bool {
+	e := entry[T]{prio: priority, seq: q.seq, value: value}
+	q.seq++
+	if q.limit > 0 && len(q.entries) >= q.limit {
+		// The largest value is in one of the leaves, which can be replaced
+		// without breaking the heap order of the other entries.
+		n := len(q.entries)
+		j := n / 2
+		for i := j + 1; i < n; i++ {
+			if q.less(j, i) {
+				j = i
+			}
+		}
+		last := q.entries[j]
+		if priority >= last.prio {
+			return false
+		}
+		q.entries[j] = e
+		q.up(j)
+		return true
+	}
+	q.entries = append(q.entries, e)
+	q.up(len(q.entries) - 1)
+	return true
+}
+
+func (q *Queue[T]) up(i int) {
+	for i != 0 {
+		parent := (i - 1) / 2
+		if !q.less(i, parent) {
+			break
+		}
+		q.entries[parent], q.entries[i] = q.entries[i], q.entries[parent]
+		i = parent
+	}
+}
+
+// Rescore changes the priority of every value in the queue to the result of
+// the score function, and then restores the order of the queue. This is
+// useful when the priorities are distances to a target that has moved.
+func (q *Queue[T]) Rescore(score func(value T, priority float64) float64) {
+	for i := range q.entries {
+		q.entries[i].prio = score(q.entries[i].value, q.entries[i].prio)
+	}
+	for i := len(q.entries)/2 - 1; i >= 0; i-- {
+		q.down(i)
+	}
+}
+
+// Peek returns the value with the smallest priority without removing it.
+func (q *Queue[T]) Peek() (value T, priority float64, ok bool) {
+	if len(q.entries) == 0 {
+		return value, 0, false
+	}
+	return q.entries[0].value, q.entries[0].prio, true
+}
+
+// Pop removes and returns the value with the smallest priority. Returns false
+// if the queue is empty.
+func (q *Queue[T]) Pop() (value T, priority float64, ok bool) {
+	n := len(q.entries)
+	if n == 0 {
+		return value, 0, false
+	}
+	e := q.entries[0]
+	q.entries[0] = q.entries[n-1]
+	q.entries[n-1] = entry[T]{}
+	q.entries = q.entries[:n-1]
+	q.down(0)
+	return e.value, e.prio, true
+}
+
+func (q *Queue[T]) down(i int) {
+	n := len(q.entries)
+	for {
+		smallest := i
+		left := i*2 + 1
+		right := i*2 + 2
+		if left < n && q.less(left, smallest) {
+			smallest = left
+		}
+		if right < n && q.less(right, smallest) {
+			smallest = right
+		}
+		if smallest == i {
+			break
+		}
+		q.entries[smallest], q.entries[i] = q.entries[i], q.entries[smallest]
+		i = smallest
+	}
+}
@@ -0,0 +1,165 @@
+package pqueue
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	var q Queue[int]
+	for _, prio := range []float64{2, 1, 5, 3, 4} {
+		q.Push(int(prio), prio)
+	}
+	if v, prio, ok := q.Peek(); !ok || v != 1 || prio != 1 {
+		t.Fatalf("expected 1, got %v", v)
+	}
+	lastDist := float64(-1)
+	for i := 0; i < 3; i++ {
+		_, prio, ok := q.Pop()
+		if !ok {
+			t.Fatal("queue was empty")
+		}
+		if prio < lastDist {
+			t.Fatal("queue was out of order")
+		}
+		lastDist = prio
+	}
+	if q.Len() != 2 {
+		t.Fatal("queue was wrong size")
+	}
+	capBeforeInserts := cap(q.entries)
+	q.Push(1, 1)
+	q.Push(10, 10)
+	q.Push(11, 11)
+	if cap(q.entries) != capBeforeInserts {
+		t.Fatal("queue did not reuse space")
+	}
+	lastDist = -1
+	for i := 0; i < 5; i++ {
+		_, prio, ok := q.Pop()
+		if !ok {
+			t.Fatal("queue was empty")
+		}
+		if prio < lastDist {
+			t.Fatal("queue was out of order")
+		}
+		lastDist = prio
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Fatal("queue was not empty")
+	}
+	if _, _, ok := q.Peek(); ok {
+		t.Fatal("queue was not empty")
+	}
+}
+
+func TestStable(t *testing.T) {
+	var q Queue[int]
+	for i := 0; i < 1000; i++ {
+		q.Push(i, float64(i%3))
+	}
+	last := [3]int{-1, -1, -1}
+	var lastPrio float64
+	for q.Len() > 0 {
+		v, prio, _ := q.Pop()
+		if prio < lastPrio {
+			t.Fatal("queue was out of order")
+		}
+		if v <= last[v%3] {
+			t.Fatalf("value %d popped after %d", v, last[v%3])
+		}
+		last[v%3] = v
+		lastPrio = prio
+	}
+	q.Push(1, 1)
+	n := q.Cap()
+	q.Reset()
+	if q.Len() != 0 {
+		t.Fatal("queue was not empty")
+	}
+	if q.Cap() != n {
+		t.Fatalf("expected %d, got %d", n, q.Cap())
+	}
+}
+
+func TestBounded(t *testing.T) {
+	q := NewBounded[float64](10)
+	if q.Limit() != 10 {
+		t.Fatalf("expected %d, got %d", 10, q.Limit())
+	}
+	var all []float64
+	for i := 0; i < 1000; i++ {
+		prio := rand.Float64()
+		all = append(all, prio)
+		q.Push(prio, prio)
+		if q.Len() > 10 {
+			t.Fatalf("expected at most %d, got %d", 10, q.Len())
+		}
+	}
+	sort.Float64s(all)
+	for i := 0; i < 10; i++ {
+		v, _, ok := q.Pop()
+		if !ok || v != all[i] {
+			t.Fatalf("expected %v, got %v", all[i], v)
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatal("queue was not empty")
+	}
+	if NewBounded[int](1).Push(1, 1) != true {
+		t.Fatal("expected true")
+	}
+	q1 := NewBounded[int](1)
+	q1.Push(1, 1)
+	if q1.Push(2, 2) {
+		t.Fatal("expected false")
+	}
+	if !q1.Push(0, 0) {
+		t.Fatal("expected true")
+	}
+	if v, _, _ := q1.Pop(); v != 0 {
+		t.Fatalf("expected %d, got %d", 0, v)
+	}
+}
+
+func TestRescore(t *testing.T) {
+	var q Queue[float64]
+	for i := 0; i < 1000; i++ {
+		v := rand.Float64()
+		q.Push(v, v)
+	}
+	for i := 0; i < 100; i++ {
+		q.Pop()
+	}
+	// reverse the order
+	q.Rescore(func(v, prio float64) float64 {
+		if v != prio {
+			t.Fatalf("expected %v, got %v", v, prio)
+		}
+		return -v
+	})
+	last := -1.0
+	for q.Len() > 0 {
+		v, prio, _ := q.Pop()
+		if prio != -v {
+			t.Fatalf("expected %v, got %v", -v, prio)
+		}
+		if last >= 0 && v > last {
+			t.Fatal("queue was out of order")
+		}
+		last = v
+	}
+}
+
+func BenchmarkQueue(b *testing.B) {
+	var q Queue[float64]
+	for i := 0; i < b.N; i++ {
+		r := rand.Float64()
+		if r < 0.5 {
+			q.Push(r, r)
+		} else {
+			q.Pop()
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package proj provides conversions between WGS84 lon,lat coordinates in
+// degrees and the Web Mercator projection (EPSG:3857) in meters, for
+// applications that store projected coordinates in an index.
+package proj
+
+import "math"
+
+// Radius is the radius of the sphere of Web Mercator, in meters.
+const Radius = 6378137
+
+// MaxLatitude is the largest latitude that Web Mercator covers, where the
+// projection is a square. Larger latitudes are clamped.
+const MaxLatitude = 85.05112877980659
+
+// MaxXY is the largest x and y of Web Mercator, in meters.
+const MaxXY = math.Pi * Radius
+
+// ToWebMercator returns the Web Mercator x,y in meters of a lon,lat point.
+// The latitude is clamped to MaxLatitude.
+func ToWebMercator(lon, lat float64) (x, y float64) {
+	lat = math.Max(-MaxLatitude, math.Min(MaxLatitude, lat))
+	x = lon * math.Pi / 180 * Radius
+	y = math.Log(math.Tan(math.Pi/4+lat*math.Pi/360)) * Radius
+	return x, y
+}
+
+// ToWGS84 returns the lon,lat point of a Web Mercator x,y in meters.
+func ToWGS84(x, y float64) (lon, lat float64) {
+	lon = x / Radius * 180 / math.Pi
+	lat = (2*math.Atan(math.Exp(y/Radius)) - math.Pi/2) * 180 / math.Pi
+	return lon, lat
+}
+
+// WebMercatorProjection is the Web Mercator projection, for the geoindex
+// functions that take a geoindex.Projection, such as Index.SearchProjected.
+type WebMercatorProjection struct{}
+
+// WebMercator is the Web Mercator projection.
+var WebMercator WebMercatorProjection
+
+// Project returns the Web Mercator x,y of a lon,lat point.
+func (WebMercatorProjection) Project(lonlat [2]float64) [2]float64 {
+	x, y := ToWebMercator(lonlat[0], lonlat[1])
+	return [2]float64{x, y}
+}
+
+// Unproject returns the lon,lat point of a Web Mercator x,y.
+func (WebMercatorProjection) Unproject(p [2]float64) [2]float64 {
+	lon, lat := ToWGS84(p[0], p[1])
+	return [2]float64{lon, lat}
+}
@@ -0,0 +1,32 @@
+package proj
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWebMercator(t *testing.T) {
+	for _, p := range [][2]float64{
+		{0, 0}, {-112.07, 33.45}, {180, MaxLatitude}, {-180, -MaxLatitude},
+		{12.5, -60},
+	} {
+		x, y := ToWebMercator(p[0], p[1])
+		lon, lat := ToWGS84(x, y)
+		if math.Abs(lon-p[0]) > 1e-9 || math.Abs(lat-p[1]) > 1e-9 {
+			t.Fatalf("%v: got %v,%v", p, lon, lat)
+		}
+	}
+	if x, y := ToWebMercator(180, 90); math.Abs(x-MaxXY) > 1e-6 ||
+		math.Abs(y-MaxXY) > 1e-6 {
+		t.Fatalf("expected %v,%v, got %v,%v", MaxXY, MaxXY, x, y)
+	}
+	// a known point, from EPSG:3857
+	if x, y := ToWebMercator(-90, 45); math.Abs(x+10018754.171) > 1e-3 ||
+		math.Abs(y-5621521.486) > 1e-3 {
+		t.Fatalf("unexpected %v,%v", x, y)
+	}
+	p := WebMercator.Unproject(WebMercator.Project([2]float64{10, 20}))
+	if math.Abs(p[0]-10) > 1e-9 || math.Abs(p[1]-20) > 1e-9 {
+		t.Fatalf("unexpected %v", p)
+	}
+}
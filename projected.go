@@ -0,0 +1,49 @@
+package geoindex
+
+// Projection converts between lon,lat degrees and the projected coordinates
+// of an index, such as proj.WebMercator. The projection must keep the
+// meridians and parallels straight and axis aligned, like a cylindrical
+// projection, so that the corners of a box are enough to convert the box.
+type Projection interface {
+	// Project returns the projected point of a lon,lat point.
+	Project(lonlat [2]float64) [2]float64
+	// Unproject returns the lon,lat point of a projected point.
+	Unproject(p [2]float64) [2]float64
+}
+
+// SearchProjected searches an index of projected coordinates with the lon,lat
+// rect param, and passes the items to iter with their boxes in lon,lat.
+func (index *Index) SearchProjected(
+	p Projection, min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	index.Search(p.Project(min), p.Project(max),
+		func(min, max [2]float64, data interface{}) bool {
+			min, max = p.Unproject(min), p.Unproject(max)
+			return iter(min, max, data)
+		},
+	)
+}
+
+// NearbyProjected performs a kNN-type operation on an index of projected
+// coordinates, where the algo is called with the boxes of the nodes and
+// items in lon,lat, so that a spherical algo such as algo.Geodesic orders
+// the items by their real world distance. The boxes that are passed to iter
+// are also in lon,lat.
+// See Nearby for a description of the `algo` and `iter` functions.
+func (index *Index) NearbyProjected(
+	p Projection,
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	index.Nearby(
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			min, max = p.Unproject(min), p.Unproject(max)
+			return algo(min, max, data, item)
+		},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			min, max = p.Unproject(min), p.Unproject(max)
+			return iter(min, max, data, dist)
+		},
+	)
+}
@@ -0,0 +1,63 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+	"github.com/tidwall/geoindex/proj"
+)
+
+func TestProjected(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	index.SetCRS(Meters)
+	points := randPoints(2000)
+	for i, box := range points {
+		p := box.min
+		p[1] = math.Max(-80, math.Min(80, p[1]))
+		points[i] = tBox{p, p}
+		xy := proj.WebMercator.Project(p)
+		index.Insert(xy, xy, i)
+	}
+	near := func(a, b [2]float64) bool {
+		return math.Abs(a[0]-b[0]) < 1e-6 && math.Abs(a[1]-b[1]) < 1e-6
+	}
+	min, max := [2]float64{-50, -20}, [2]float64{30, 60}
+	expect := make(map[interface{}]bool)
+	for i, box := range points {
+		if intersects(min, max, box.min, box.max) {
+			expect[i] = true
+		}
+	}
+	var count int
+	index.SearchProjected(proj.WebMercator, min, max,
+		func(min, max [2]float64, data interface{}) bool {
+			if !expect[data] || !near(min, points[data.(int)].min) {
+				t.Fatalf("unexpected item %v %v", data, min)
+			}
+			count++
+			return true
+		},
+	)
+	if count != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), count)
+	}
+	// the spherical distances are ordered
+	var last float64
+	count = 0
+	index.NearbyProjected(proj.WebMercator, algo.Geodesic(10, 60),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			if dist < last || math.Abs(dist-algo.Haversine([2]float64{10, 60},
+				points[data.(int)].min)) > 1e-3 {
+				t.Fatalf("unexpected dist %v", dist)
+			}
+			last = dist
+			count++
+			return true
+		},
+	)
+	if count != len(points) {
+		t.Fatalf("expected %d, got %d", len(points), count)
+	}
+}
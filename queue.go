@@ -1,53 +1,48 @@
 package geoindex
 
-import "github.com/tidwall/geoindex/child"
+import (
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/pqueue"
+)
 
 // Priority Queue ordered by dist (smallest to largest)
 
 type qnode struct {
 	dist  float64
+	aux   interface{}
 	child child.Child
 }
 
-type queue []qnode
+type queue struct {
+	pqueue.Queue[qnode]
+}
 
 func (q *queue) push(node qnode) {
-	*q = append(*q, node)
-	nodes := *q
-	i := len(nodes) - 1
-	parent := (i - 1) / 2
-	for ; i != 0 && nodes[parent].dist > nodes[i].dist; parent = (i - 1) / 2 {
-		nodes[parent], nodes[i] = nodes[i], nodes[parent]
-		i = parent
-	}
+	q.Push(node, node.dist)
 }
 
 func (q *queue) pop() (qnode, bool) {
-	nodes := *q
-	if len(nodes) == 0 {
-		return qnode{}, false
-	}
-	var n qnode
-	n, nodes[0] = nodes[0], nodes[len(*q)-1]
-	nodes = nodes[:len(nodes)-1]
-	*q = nodes
-
-	i := 0
-	for {
-		smallest := i
-		left := i*2 + 1
-		right := i*2 + 2
-		if left < len(nodes) && nodes[left].dist <= nodes[smallest].dist {
-			smallest = left
-		}
-		if right < len(nodes) && nodes[right].dist <= nodes[smallest].dist {
-			smallest = right
-		}
-		if smallest == i {
-			break
-		}
-		nodes[smallest], nodes[i] = nodes[i], nodes[smallest]
-		i = smallest
+	node, dist, ok := q.Pop()
+	node.dist = dist
+	return node, ok
+}
+
+// Queue holds the priority queue and the children buffer of a kNN-type
+// operation. A Queue may be reused by many calls to NearbyReuse, such as by
+// keeping a Queue for each worker or in a sync.Pool, so the space that was
+// allocated by earlier operations is used again rather than growing new
+// buffers on the heap for each operation. The zero value is ready to use.
+type Queue struct {
+	q        queue
+	children []child.Child
+}
+
+// Reset removes all items and nodes from the queue, keeping the allocated
+// space for reuse.
+func (q *Queue) Reset() {
+	q.q.Reset()
+	for i := range q.children {
+		q.children[i] = child.Child{}
 	}
-	return n, true
+	q.children = q.children[:0]
 }
@@ -0,0 +1,124 @@
+package geoindex
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// RenderOptions are the options for Index.RenderPNG.
+type RenderOptions struct {
+	// Viewport is the min,max box of the coordinates that are visible. A
+	// zero Viewport fits the bounds of the tree, or the Bounds of the CRS
+	// of the index when the tree is empty.
+	Viewport [2][2]float64
+	// NodeColors are the colors of the nodes, by depth, where the root
+	// nodes use the first color. The colors wrap around for trees that are
+	// deeper than the number of colors. Empty uses the same colors as
+	// DefaultSVGOptions.
+	NodeColors []color.Color
+	// ItemColor is the color of the items. Nil uses black.
+	ItemColor color.Color
+	// Background is the color of the background. Nil is transparent.
+	Background color.Color
+	// MaxDepth is the number of levels that are drawn, where the root nodes
+	// are at depth zero and items are one level deeper than their node.
+	// Zero draws all levels.
+	MaxDepth int
+}
+
+var defaultNodeColors = []color.Color{
+	color.RGBA{0xff, 0x00, 0x00, 0xff}, // red
+	color.RGBA{0x00, 0x99, 0x00, 0xff}, // #009900
+	color.RGBA{0xcc, 0xcc, 0x00, 0xff}, // #cccc00
+	color.RGBA{0x00, 0x00, 0x00, 0xff}, // black
+	color.RGBA{0x80, 0x00, 0x80, 0xff}, // purple
+}
+
+// RenderPNG draws the outlines of the nodes and items of the tree to an image
+// that's width by height pixels, and writes it to w as a PNG. This works
+// for trees with far more items than what's practical with SVG.
+func (index *Index) RenderPNG(w io.Writer, width, height int,
+	opts RenderOptions,
+) error {
+	if width < 1 || height < 1 {
+		return errors.New("geoindex: invalid image size")
+	}
+	if opts.Viewport == [2][2]float64{} {
+		min, max := index.tree.Bounds()
+		if index.tree.Len() == 0 {
+			min, max = index.CRS().Bounds()
+		}
+		opts.Viewport = [2][2]float64{min, max}
+	}
+	if len(opts.NodeColors) == 0 {
+		opts.NodeColors = defaultNodeColors
+	}
+	if opts.ItemColor == nil {
+		opts.ItemColor = color.Black
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if opts.Background != nil {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				img.Set(x, y, opts.Background)
+			}
+		}
+	}
+	vmin, vmax := opts.Viewport[0], opts.Viewport[1]
+	sx, sy := 0.0, 0.0
+	if vmax[0] > vmin[0] {
+		sx = float64(width-1) / (vmax[0] - vmin[0])
+	}
+	if vmax[1] > vmin[1] {
+		sy = float64(height-1) / (vmax[1] - vmin[1])
+	}
+	// pixel returns the pixel for a coordinate, clamped to just outside of
+	// the image, where the y axis points down.
+	pixel := func(p [2]float64) (x, y int) {
+		fx := math.Round((p[0] - vmin[0]) * sx)
+		fy := math.Round((vmax[1] - p[1]) * sy)
+		fx = math.Max(-1, math.Min(float64(width), fx))
+		fy = math.Max(-1, math.Min(float64(height), fy))
+		return int(fx), int(fy)
+	}
+	rect := func(c child.Child, col color.Color) {
+		x0, y1 := pixel(c.Min)
+		x1, y0 := pixel(c.Max)
+		for x := x0; x <= x1; x++ {
+			img.Set(x, y0, col)
+			img.Set(x, y1, col)
+		}
+		for y := y0; y <= y1; y++ {
+			img.Set(x0, y, col)
+			img.Set(x1, y, col)
+		}
+	}
+	var draw func(children []child.Child, depth int)
+	draw = func(children []child.Child, depth int) {
+		if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+			return
+		}
+		for _, c := range children {
+			if !intersects(vmin, vmax, c.Min, c.Max) {
+				continue
+			}
+			if c.Item {
+				rect(c, opts.ItemColor)
+			} else {
+				rect(c, opts.NodeColors[depth%len(opts.NodeColors)])
+				buf := getChildren()
+				*buf = index.tree.Children(c.Data, *buf)
+				draw(*buf, depth+1)
+				putChildren(buf)
+			}
+		}
+	}
+	draw(index.tree.Children(nil, nil), 0)
+	return png.Encode(w, img)
+}
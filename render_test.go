@@ -0,0 +1,67 @@
+package geoindex
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestRenderPNG(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 10000; i++ {
+		p := [2]float64{float64(i % 100), float64(i / 100)}
+		index.Insert(p, p, i)
+	}
+	var buf bytes.Buffer
+	err := index.RenderPNG(&buf, 100, 100, RenderOptions{
+		Background: color.White,
+		ItemColor:  color.RGBA{0, 0, 0xff, 0xff},
+		NodeColors: []color.Color{color.RGBA{0xff, 0, 0, 0xff}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 100 || b.Dy() != 100 {
+		t.Fatalf("unexpected size %v", b)
+	}
+	// every pixel is a point, and items are drawn over their nodes
+	blue := color.RGBAModel.Convert(color.RGBA{0, 0, 0xff, 0xff})
+	for _, p := range [][2]int{{0, 99}, {99, 0}, {50, 50}} {
+		if c := color.RGBAModel.Convert(img.At(p[0], p[1])); c != blue {
+			t.Fatalf("%v: expected %v, got %v", p, blue, c)
+		}
+	}
+
+	// only the root nodes, which leave some of the background
+	buf.Reset()
+	err = index.RenderPNG(&buf, 100, 100, RenderOptions{
+		Background: color.White,
+		MaxDepth:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _ = png.Decode(&buf)
+	var white int
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			if color.GrayModel.Convert(img.At(x, y)) == color.GrayModel.
+				Convert(color.White) {
+				white++
+			}
+		}
+	}
+	if white == 0 || white == 100*100 {
+		t.Fatalf("unexpected %d white pixels", white)
+	}
+	if err := index.RenderPNG(&buf, 0, 100, RenderOptions{}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
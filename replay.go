@@ -0,0 +1,238 @@
+package geoindex
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// LogOp is the kind of an operation in a replay log.
+type LogOp byte
+
+// Operations that are written to a replay log
+const (
+	LogInsert LogOp = iota + 1
+	LogDelete
+	LogReplace
+	LogSearch
+	LogScan
+)
+
+func (op LogOp) String() string {
+	switch op {
+	case LogInsert:
+		return "insert"
+	case LogDelete:
+		return "delete"
+	case LogReplace:
+		return "replace"
+	case LogSearch:
+		return "search"
+	case LogScan:
+		return "scan"
+	}
+	return fmt.Sprintf("LogOp(%d)", byte(op))
+}
+
+// ErrBadLog is returned by Replay when the log is not valid.
+var ErrBadLog = errors.New("geoindex: bad replay log")
+
+const logMagic = "GEOIDXL1"
+
+// Recorder is an Interface that writes every Insert, Delete, Replace, Search,
+// and Scan operation to a log before passing it to the wrapped tree. The log
+// can later be reapplied to any Interface using Replay.
+//
+//	rec := geoindex.NewRecorder(tree, file)
+//	index := geoindex.Wrap(rec)
+//	... use index ...
+//	rec.Flush()
+//
+// The data of an item is not written to the log, only its Fingerprint.
+type Recorder struct {
+	tree Interface
+	w    *bufio.Writer
+	err  error
+	buf  []byte
+}
+
+// NewRecorder returns a Recorder that writes the operations on tree to w.
+func NewRecorder(tree Interface, w io.Writer) *Recorder {
+	rec := &Recorder{tree: tree, w: bufio.NewWriter(w)}
+	_, rec.err = rec.w.WriteString(logMagic)
+	return rec
+}
+
+// Fingerprint returns a hash of the data that is used to identify an item in
+// a replay log. Values are hashed by their type and formatted value, so
+// pointers are identified by their address.
+func Fingerprint(data interface{}) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%T:%v", data, data)
+	return h.Sum64()
+}
+
+func (rec *Recorder) write(op LogOp, boxes [][2]float64, data []interface{}) {
+	if rec.err != nil {
+		return
+	}
+	rec.buf = append(rec.buf[:0], byte(op))
+	for _, p := range boxes {
+		rec.buf = appendUint64(rec.buf, math.Float64bits(p[0]))
+		rec.buf = appendUint64(rec.buf, math.Float64bits(p[1]))
+	}
+	for _, data := range data {
+		rec.buf = appendUint64(rec.buf, Fingerprint(data))
+	}
+	_, rec.err = rec.w.Write(rec.buf)
+}
+
+// appendUint64 appends x to b in little-endian order, which is the same as
+// binary.LittleEndian.AppendUint64 but works with Go 1.18.
+func appendUint64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+// Flush writes any buffered operations to the underlying writer, and returns
+// the first error that occurred while writing the log.
+func (rec *Recorder) Flush() error {
+	if rec.err == nil {
+		rec.err = rec.w.Flush()
+	}
+	return rec.err
+}
+
+// Insert an item into the tree
+func (rec *Recorder) Insert(min, max [2]float64, data interface{}) {
+	rec.write(LogInsert, [][2]float64{min, max}, []interface{}{data})
+	rec.tree.Insert(min, max, data)
+}
+
+// Delete an item from the tree
+func (rec *Recorder) Delete(min, max [2]float64, data interface{}) {
+	rec.write(LogDelete, [][2]float64{min, max}, []interface{}{data})
+	rec.tree.Delete(min, max, data)
+}
+
+// Replace an item in the tree
+func (rec *Recorder) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	rec.write(LogReplace, [][2]float64{oldMin, oldMax, newMin, newMax},
+		[]interface{}{oldData, newData})
+	rec.tree.Replace(oldMin, oldMax, oldData, newMin, newMax, newData)
+}
+
+// Search the tree for items that intersects the rect param
+func (rec *Recorder) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	rec.write(LogSearch, [][2]float64{min, max}, nil)
+	rec.tree.Search(min, max, iter)
+}
+
+// Scan iterates through all data in tree in no specified order.
+func (rec *Recorder) Scan(
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	rec.write(LogScan, nil, nil)
+	rec.tree.Scan(iter)
+}
+
+// Len returns the number of items in tree
+func (rec *Recorder) Len() int {
+	return rec.tree.Len()
+}
+
+// Bounds returns the minimum bounding box
+func (rec *Recorder) Bounds() (min, max [2]float64) {
+	return rec.tree.Bounds()
+}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root nodes should be returned.
+func (rec *Recorder) Children(parent interface{}, reuse []child.Child) (
+	children []child.Child,
+) {
+	return rec.tree.Children(parent, reuse)
+}
+
+// Replay reads a log that was written by a Recorder and applies each
+// operation to tree. The data of the items are the uint64 fingerprints from
+// the log. The iter function, which may be nil, is called after each
+// operation with its position in the log, starting at zero. Return false
+// from iter to stop the replay early, such as when looking for the operation
+// that caused a problem.
+func Replay(r io.Reader, tree Interface, iter func(n int, op LogOp) bool,
+) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(logMagic))
+	if _, err := io.ReadFull(br, magic); err != nil ||
+		string(magic) != logMagic {
+		return ErrBadLog
+	}
+	buf := make([]byte, 80)
+	var data []byte
+	point := func(i int) [2]float64 {
+		return [2]float64{
+			math.Float64frombits(binary.LittleEndian.Uint64(data[i*16:])),
+			math.Float64frombits(binary.LittleEndian.Uint64(data[i*16+8:])),
+		}
+	}
+	fp := func(b []byte) uint64 {
+		return binary.LittleEndian.Uint64(b)
+	}
+	noop := func(min, max [2]float64, data interface{}) bool { return true }
+	for n := 0; ; n++ {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		op := LogOp(b)
+		var size int
+		switch op {
+		case LogInsert, LogDelete:
+			size = 40
+		case LogReplace:
+			size = 80
+		case LogSearch:
+			size = 32
+		case LogScan:
+		default:
+			return ErrBadLog
+		}
+		data = buf[:size]
+		if _, err := io.ReadFull(br, data); err != nil {
+			return ErrBadLog
+		}
+		switch op {
+		case LogInsert:
+			tree.Insert(point(0), point(1), fp(data[32:]))
+		case LogDelete:
+			tree.Delete(point(0), point(1), fp(data[32:]))
+		case LogReplace:
+			tree.Replace(point(0), point(1), fp(data[64:]),
+				point(2), point(3), fp(data[72:]))
+		case LogSearch:
+			tree.Search(point(0), point(1), noop)
+		case LogScan:
+			tree.Scan(noop)
+		}
+		if iter != nil && !iter(n, op) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package geoindex
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestReplay(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&internal.RTree{}, &buf)
+	index := Wrap(rec)
+	boxes := randBoxes(1000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	for i, box := range boxes[:500] {
+		index.Delete(box.min, box.max, i)
+	}
+	rec.Replace(boxes[500].min, boxes[500].max, 500,
+		boxes[0].min, boxes[0].max, "moved")
+	index.Search([2]float64{-10, -10}, [2]float64{10, 10},
+		func(min, max [2]float64, data interface{}) bool { return true })
+	rec.Scan(func(min, max [2]float64, data interface{}) bool { return true })
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	nops := 1000 + 500 + 3
+	if buf.Len() != len(logMagic)+1500*41+81+33+1 {
+		t.Fatalf("unexpected log size %d", buf.Len())
+	}
+
+	var tr internal.RTree
+	var ops []LogOp
+	err := Replay(bytes.NewReader(buf.Bytes()), &tr,
+		func(n int, op LogOp) bool {
+			ops = append(ops, op)
+			return true
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != nops || ops[0] != LogInsert || ops[1000] != LogDelete ||
+		ops[1500] != LogReplace || ops[1501] != LogSearch ||
+		ops[1502] != LogScan {
+		t.Fatalf("unexpected ops: %d", len(ops))
+	}
+	if tr.Len() != index.tree.Len() {
+		t.Fatalf("expected %d, got %d", index.tree.Len(), tr.Len())
+	}
+	fps := make(map[uint64]bool)
+	rec.Scan(func(min, max [2]float64, data interface{}) bool {
+		fps[Fingerprint(data)] = true
+		return true
+	})
+	tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		if !fps[data.(uint64)] {
+			t.Fatalf("unexpected item %v %v %v", min, max, data)
+		}
+		return true
+	})
+
+	// stop early
+	var tr2 internal.RTree
+	err = Replay(bytes.NewReader(buf.Bytes()), &tr2,
+		func(n int, op LogOp) bool { return n < 99 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr2.Len() != 100 {
+		t.Fatalf("expected %d, got %d", 100, tr2.Len())
+	}
+
+	// bad logs
+	if err := Replay(bytes.NewReader([]byte("nope")), &tr2,
+		nil); err != ErrBadLog {
+		t.Fatalf("expected %v, got %v", ErrBadLog, err)
+	}
+	if err := Replay(bytes.NewReader(buf.Bytes()[:len(logMagic)+20]), &tr2,
+		nil); err != ErrBadLog {
+		t.Fatalf("expected %v, got %v", ErrBadLog, err)
+	}
+}
@@ -0,0 +1,90 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/algo"
+
+// ReverseNearby performs a reverse nearest neighbor (RNN) operation, which
+// finds the items in the index that would have the target as their nearest
+// neighbor, rather than any of the items in candidates. For example, with an
+// index of customers and the existing stores as the candidates, the items are
+// the customers that would be closest to a new store at the target.
+//
+// The dist is the squared box distance, like algo.Box, and an item is
+// returned when its dist to the target is not larger than its dist to every
+// candidate. The dist passed to iter is the dist of the item to the target.
+// The items are returned in no specified order.
+//
+// A node is skipped when all of it is closer to the candidate that's nearest
+// to its center than to the target, so an operation usually visits far fewer
+// items than what's in the index.
+func (index *Index) ReverseNearby(
+	target [2]float64, candidates *Index,
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	defer index.stats.end(opNearby, index.stats.begin())
+	// nearest returns the candidate that's nearest to a box
+	nearest := func(min, max [2]float64) (cmin, cmax [2]float64,
+		dist float64, ok bool,
+	) {
+		candidates.Nearby(algo.Box(min, max, false, nil),
+			func(min, max [2]float64, data interface{}, d float64) bool {
+				cmin, cmax, dist, ok = min, max, d, true
+				return false
+			},
+		)
+		return cmin, cmax, dist, ok
+	}
+	// closer returns true when every point of the box is strictly closer to
+	// the farthest corner of the candidate than to the target. The points
+	// that are closer to each corner of the candidate than to the target are
+	// a half-plane, so it's enough to check the corners of the box.
+	closer := func(min, max, cmin, cmax [2]float64) bool {
+		for _, p := range [4][2]float64{
+			min, max, {min[0], max[1]}, {max[0], min[1]},
+		} {
+			dt := sqdist(p, target)
+			for _, q := range [4][2]float64{
+				cmin, cmax, {cmin[0], cmax[1]}, {cmax[0], cmin[1]},
+			} {
+				if sqdist(p, q) >= dt {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	var walk func(parent interface{}) bool
+	walk = func(parent interface{}) bool {
+		buf := getChildren()
+		defer putChildren(buf)
+		*buf = index.tree.Children(parent, *buf)
+		for _, c := range *buf {
+			if c.Item {
+				dist := algo.BoxDistCalc(target, target, c.Min, c.Max, false)
+				_, _, cdist, ok := nearest(c.Min, c.Max)
+				if ok && cdist < dist {
+					continue
+				}
+				if !iter(c.Min, c.Max, c.Data, dist) {
+					return false
+				}
+				continue
+			}
+			center := [2]float64{(c.Min[0] + c.Max[0]) / 2,
+				(c.Min[1] + c.Max[1]) / 2}
+			cmin, cmax, _, ok := nearest(center, center)
+			if ok && closer(c.Min, c.Max, cmin, cmax) {
+				continue
+			}
+			if !walk(c.Data) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(nil)
+}
+
+func sqdist(a, b [2]float64) float64 {
+	dx, dy := a[0]-b[0], a[1]-b[1]
+	return dx*dx + dy*dy
+}
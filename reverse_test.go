@@ -0,0 +1,66 @@
+package geoindex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestReverseNearby(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 5000; i++ {
+		p := [2]float64{rng.Float64() * 100, rng.Float64() * 100}
+		index.Insert(p, p, i)
+	}
+	candidates := Wrap(&internal.RTree{})
+	var stores []tBox
+	for i := 0; i < 50; i++ {
+		min := [2]float64{rng.Float64() * 100, rng.Float64() * 100}
+		max := [2]float64{min[0] + rng.Float64(), min[1] + rng.Float64()}
+		candidates.Insert(min, max, i)
+		stores = append(stores, tBox{min, max})
+	}
+	for _, target := range [][2]float64{{50, 50}, {0, 0}, {99, 12}} {
+		expect := make(map[interface{}]bool)
+		index.Scan(func(min, max [2]float64, data interface{}) bool {
+			dist := algo.BoxDistCalc(target, target, min, max, false)
+			for _, s := range stores {
+				if algo.BoxDistCalc(min, max, s.min, s.max, false) < dist {
+					return true
+				}
+			}
+			expect[data] = true
+			return true
+		})
+		if len(expect) == 0 {
+			t.Fatalf("%v: expected some items", target)
+		}
+		got := make(map[interface{}]bool)
+		index.ReverseNearby(target, candidates,
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				if !expect[data] || got[data] {
+					t.Fatalf("%v: unexpected item %v", target, data)
+				}
+				got[data] = true
+				return true
+			},
+		)
+		if len(got) != len(expect) {
+			t.Fatalf("%v: expected %d, got %d", target, len(expect), len(got))
+		}
+	}
+	// all items without candidates
+	var count int
+	index.ReverseNearby([2]float64{}, Wrap(&internal.RTree{}),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			count++
+			return count < 10
+		},
+	)
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/tidwall/geoindex/rtreegindex
+
+go 1.21
+
+replace github.com/tidwall/geoindex => ../
+
+require (
+	github.com/tidwall/geoindex v1.7.0
+	github.com/tidwall/rtree v1.11.1
+)
+
+require (
+	github.com/tidwall/cities v0.1.0 // indirect
+	github.com/tidwall/lotsa v1.0.2 // indirect
+)
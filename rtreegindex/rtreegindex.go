@@ -0,0 +1,5 @@
+// Package rtreegindex tests geoindex.WrapG and geoindex.WrapGN with the
+// generic trees of the github.com/tidwall/rtree package. It's a separate
+// module because the rtree package needs a newer version of Go than the
+// geoindex module.
+package rtreegindex
@@ -0,0 +1,81 @@
+package rtreegindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex"
+	"github.com/tidwall/rtree"
+)
+
+func TestGeoIndex(t *testing.T) {
+	t.Run("RandomRects", func(t *testing.T) {
+		geoindex.Tests.TestRandomRects(t,
+			geoindex.FromG[interface{}](&rtree.RTreeG[interface{}]{}), 10000)
+	})
+	t.Run("RandomPoints", func(t *testing.T) {
+		geoindex.Tests.TestRandomPoints(t,
+			geoindex.FromG[interface{}](&rtree.RTreeG[interface{}]{}), 10000)
+	})
+	t.Run("ZeroPoints", func(t *testing.T) {
+		geoindex.Tests.TestZeroPoints(t,
+			geoindex.FromG[interface{}](&rtree.RTreeG[interface{}]{}))
+	})
+}
+
+func TestInt32(t *testing.T) {
+	index := geoindex.WrapGN[int32, int](&rtree.RTreeGN[int32, int]{})
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		index.Insert(p, p, i)
+	}
+	// the rect is rounded outwards for the tree, but only the items in the
+	// rect are returned
+	var count int
+	index.Search([2]float64{9.5, 9.5}, [2]float64{19.5, 19.5},
+		func(min, max [2]float64, data interface{}) bool {
+			if data.(int) < 10 || data.(int) > 19 {
+				t.Fatalf("unexpected item %v", data)
+			}
+			count++
+			return true
+		},
+	)
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+	// the dists are not truncated to integers
+	target := [2]float64{50.25, 50.25}
+	var last float64
+	count = 0
+	index.Nearby(
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			var dist float64
+			for i := 0; i < 2; i++ {
+				if target[i] < min[i] {
+					dist += (min[i] - target[i]) * (min[i] - target[i])
+				} else if target[i] > max[i] {
+					dist += (target[i] - max[i]) * (target[i] - max[i])
+				}
+			}
+			return dist
+		},
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			if count == 0 && (data.(int) != 50 || dist != 0.125) {
+				t.Fatalf("expected 50 at 0.125, got %v at %v", data, dist)
+			}
+			if dist < last {
+				t.Fatal("out of order")
+			}
+			last = dist
+			count++
+			return true
+		},
+	)
+	if count != 100 {
+		t.Fatalf("expected %d, got %d", 100, count)
+	}
+	index.Delete([2]float64{50, 50}, [2]float64{50, 50}, 50)
+	if index.Len() != 99 {
+		t.Fatalf("expected %d, got %d", 99, index.Len())
+	}
+}
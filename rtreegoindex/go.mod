@@ -0,0 +1,15 @@
+module github.com/tidwall/geoindex/rtreegoindex
+
+go 1.18
+
+replace github.com/tidwall/geoindex => ../
+
+require (
+	github.com/dhconnelly/rtreego v1.2.0
+	github.com/tidwall/geoindex v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/tidwall/cities v0.1.0 // indirect
+	github.com/tidwall/lotsa v1.0.2 // indirect
+)
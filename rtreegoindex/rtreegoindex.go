@@ -0,0 +1,191 @@
+// Package rtreegoindex provides a geoindex.Interface for an Rtree from the
+// github.com/dhconnelly/rtreego package.
+package rtreegoindex
+
+import (
+	"math"
+
+	"github.com/dhconnelly/rtreego"
+	"github.com/tidwall/geoindex/child"
+)
+
+// item is a box stored in the rtree
+type item struct {
+	min, max [2]float64
+	data     interface{}
+}
+
+func (it *item) Bounds() rtreego.Rect {
+	return rect(it.min, it.max)
+}
+
+func rect(min, max [2]float64) rtreego.Rect {
+	r, _ := rtreego.NewRectFromPoints(
+		rtreego.Point{min[0], min[1]},
+		rtreego.Point{max[0], max[1]},
+	)
+	return r
+}
+
+// everything is a rect that intersects all items
+var everything = rect(
+	[2]float64{-math.MaxFloat64, -math.MaxFloat64},
+	[2]float64{math.MaxFloat64, math.MaxFloat64},
+)
+
+// Rtree is a geoindex.Interface that stores its items in a two dimensional
+// rtreego.Rtree.
+// The Children are a single root node holding all items, because the nodes
+// of an rtreego.Rtree are not exported.
+type Rtree struct {
+	tree *rtreego.Rtree
+}
+
+// New returns a new Rtree using the min and max branching factors.
+func New(minChildren, maxChildren int) *Rtree {
+	return Wrap(rtreego.NewTree(2, minChildren, maxChildren))
+}
+
+// Wrap returns an Rtree for an existing two dimensional rtreego.Rtree. The
+// objects already in the tree become items with their bounds as the box and
+// the object as the data.
+func Wrap(tree *rtreego.Rtree) *Rtree {
+	return &Rtree{tree: tree}
+}
+
+// Rtree returns the underlying rtreego.Rtree.
+func (tr *Rtree) Rtree() *rtreego.Rtree {
+	return tr.tree
+}
+
+func unpack(obj rtreego.Spatial) (min, max [2]float64, data interface{}) {
+	if it, ok := obj.(*item); ok {
+		return it.min, it.max, it.data
+	}
+	r := obj.Bounds()
+	for i := 0; i < 2; i++ {
+		min[i] = r.PointCoord(i)
+		max[i] = min[i] + r.LengthsCoord(i)
+	}
+	return min, max, obj
+}
+
+// Insert an item into the index
+func (tr *Rtree) Insert(min, max [2]float64, data interface{}) {
+	tr.tree.Insert(&item{min: min, max: max, data: data})
+}
+
+// Delete an item from the index
+func (tr *Rtree) Delete(min, max [2]float64, data interface{}) {
+	tr.tree.DeleteWithComparator(&item{min: min, max: max, data: data},
+		func(obj1, obj2 rtreego.Spatial) bool {
+			min1, max1, data1 := unpack(obj1)
+			min2, max2, data2 := unpack(obj2)
+			return min1 == min2 && max1 == max2 && data1 == data2
+		},
+	)
+}
+
+// Replace an item in the index
+func (tr *Rtree) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	tr.Delete(oldMin, oldMax, oldData)
+	tr.Insert(newMin, newMax, newData)
+}
+
+// Search the index for items that intersects the rect param
+func (tr *Rtree) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	// rtreego does not consider touching rects to be intersecting, so the
+	// search rect is grown a tiny bit and the results are checked here.
+	qmin, qmax := min, max
+	for i := 0; i < 2; i++ {
+		qmin[i] = math.Nextafter(qmin[i], math.Inf(-1))
+		qmax[i] = math.Nextafter(qmax[i], math.Inf(+1))
+	}
+	tr.search(rect(qmin, qmax), func(imin, imax [2]float64,
+		data interface{},
+	) bool {
+		if imin[0] > max[0] || imax[0] < min[0] ||
+			imin[1] > max[1] || imax[1] < min[1] {
+			return true
+		}
+		return iter(imin, imax, data)
+	})
+}
+
+func (tr *Rtree) search(bb rtreego.Rect,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	var stop bool
+	tr.tree.SearchIntersect(bb,
+		func(_ []rtreego.Spatial, obj rtreego.Spatial) (refuse, abort bool) {
+			// abort only stops the current node, so keep track of it here
+			if !stop && !iter(unpack(obj)) {
+				stop = true
+			}
+			return true, stop
+		},
+	)
+}
+
+// Scan iterates through all data in the index in no specified order.
+func (tr *Rtree) Scan(iter func(min, max [2]float64, data interface{}) bool) {
+	if tr.tree.Size() > 0 {
+		tr.search(everything, iter)
+	}
+}
+
+// Len returns the number of items in the index
+func (tr *Rtree) Len() int {
+	return tr.tree.Size()
+}
+
+// Bounds returns the minimum bounding box
+func (tr *Rtree) Bounds() (min, max [2]float64) {
+	var i int
+	tr.Scan(func(imin, imax [2]float64, _ interface{}) bool {
+		if i == 0 {
+			min, max = imin, imax
+		} else {
+			for j := 0; j < 2; j++ {
+				min[j] = math.Min(min[j], imin[j])
+				max[j] = math.Max(max[j], imax[j])
+			}
+		}
+		i++
+		return true
+	})
+	return min, max
+}
+
+// root is the single synthesized node holding all items
+type root struct{}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root node is returned.
+func (tr *Rtree) Children(parent interface{}, reuse []child.Child) (
+	children []child.Child,
+) {
+	children = reuse
+	if parent == nil {
+		if tr.tree.Size() > 0 {
+			min, max := tr.Bounds()
+			children = append(children, child.Child{
+				Min: min, Max: max, Data: root{},
+			})
+		}
+		return children
+	}
+	tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		children = append(children, child.Child{
+			Min: min, Max: max, Data: data, Item: true,
+		})
+		return true
+	})
+	return children
+}
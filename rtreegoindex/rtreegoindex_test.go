@@ -0,0 +1,54 @@
+package rtreegoindex
+
+import (
+	"testing"
+
+	"github.com/dhconnelly/rtreego"
+	"github.com/tidwall/geoindex"
+)
+
+func TestGeoIndex(t *testing.T) {
+	t.Run("RandomRects", func(t *testing.T) {
+		geoindex.Tests.TestRandomRects(t, New(8, 32), 10000)
+	})
+	t.Run("RandomPoints", func(t *testing.T) {
+		geoindex.Tests.TestRandomPoints(t, New(8, 32), 10000)
+	})
+	t.Run("ZeroPoints", func(t *testing.T) {
+		geoindex.Tests.TestZeroPoints(t, New(8, 32))
+	})
+}
+
+type thing struct {
+	where rtreego.Point
+	id    int
+}
+
+func (t *thing) Bounds() rtreego.Rect {
+	return t.where.ToRect(0.5)
+}
+
+func TestWrap(t *testing.T) {
+	tree := rtreego.NewTree(2, 8, 32)
+	for i := 0; i < 100; i++ {
+		tree.Insert(&thing{rtreego.Point{float64(i), float64(i)}, i})
+	}
+	tr := Wrap(tree)
+	if tr.Len() != 100 {
+		t.Fatalf("expected %d, got %d", 100, tr.Len())
+	}
+	var count int
+	tr.Search([2]float64{10, 10}, [2]float64{19, 19},
+		func(min, max [2]float64, data interface{}) bool {
+			count++
+			return true
+		},
+	)
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+	min, max := tr.Bounds()
+	if min != [2]float64{-0.5, -0.5} || max != [2]float64{99.5, 99.5} {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/tidwall/geoindex/s2index
+
+go 1.23.0
+
+replace github.com/tidwall/geoindex => ../
+
+require (
+	github.com/golang/geo v0.0.0-20260818125358-b200a1149890
+	github.com/tidwall/geoindex v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/tidwall/cities v0.1.0 // indirect
+	github.com/tidwall/lotsa v1.0.2 // indirect
+)
@@ -0,0 +1,375 @@
+// Package s2index provides a geoindex.Interface for an s2.ShapeIndex from the
+// github.com/golang/geo package.
+package s2index
+
+import (
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"github.com/tidwall/geoindex"
+	"github.com/tidwall/geoindex/child"
+)
+
+// densify is the maximum number of degrees of longitude between the vertices
+// of a box's parallel edges. Edges in S2 are geodesics, so the parallels must
+// be subdivided to keep the shape close to its lat/lng box
+const densify = 0.25
+
+type entry struct {
+	min, max [2]float64
+	data     interface{}
+	shape    s2.Shape
+}
+
+// Index is a geoindex.Interface that stores its items in an s2.ShapeIndex.
+// Coordinates are longitude, latitude in degrees.
+//
+// Items that have an s2.Shape as data are added to the ShapeIndex as is,
+// otherwise a shape is created from the item box. Search uses the ShapeIndex
+// to find candidates and then checks them against the item boxes. Items that
+// are inserted after the ShapeIndex has been built are kept in a pending list
+// that each search checks one by one, and the ShapeIndex is rebuilt once the
+// pending list grows past an eighth of the items. A rebuild costs O(n log n),
+// so the cost of an insert is amortized, but a search may check up to n/8
+// pending items, and this works best for workloads that are mostly reads. The
+// Children are a single root node holding all items, because the cells of a
+// ShapeIndex may hold parts of many shapes.
+type Index struct {
+	index   *s2.ShapeIndex
+	entries map[int32]*entry
+	boxes   map[[2][2]float64][]int32
+	dead    int     // deleted shapes still in the ShapeIndex
+	pending []int32 // entries inserted after the ShapeIndex was built
+	coverer *s2.RegionCoverer
+}
+
+// New returns a new Index using an empty s2.ShapeIndex.
+func New() *Index {
+	return Wrap(s2.NewShapeIndex())
+}
+
+// Wrap returns an Index for an existing s2.ShapeIndex. The shapes already in
+// the index become items with their rect bounds as the box and the shape as
+// the data.
+func Wrap(index *s2.ShapeIndex) *Index {
+	idx := &Index{
+		index:   index,
+		entries: make(map[int32]*entry),
+		boxes:   make(map[[2][2]float64][]int32),
+		coverer: &s2.RegionCoverer{MaxLevel: 30, MaxCells: 8},
+	}
+	for id := int32(0); int(id) < index.Len(); id++ {
+		shape := index.Shape(id)
+		if shape == nil {
+			continue
+		}
+		min, max := shapeBounds(shape)
+		idx.entries[id] = &entry{min: min, max: max, data: shape, shape: shape}
+		key := [2][2]float64{min, max}
+		idx.boxes[key] = append(idx.boxes[key], id)
+	}
+	return idx
+}
+
+// ShapeIndex returns the underlying s2.ShapeIndex. Modifying the index may
+// cause the ShapeIndex to be replaced with a new one, it may still contain
+// shapes for items that have been deleted, and it may not contain the shapes
+// of the pending items.
+func (idx *Index) ShapeIndex() *s2.ShapeIndex {
+	return idx.index
+}
+
+func shapeBounds(shape s2.Shape) (min, max [2]float64) {
+	rb := s2.NewRectBounder()
+	for i := 0; i < shape.NumEdges(); i++ {
+		e := shape.Edge(i)
+		rb.AddPoint(e.V0)
+		rb.AddPoint(e.V1)
+	}
+	r := rb.RectBound()
+	if shape.Dimension() == 0 {
+		// use the exact points, without the error padding of the bounder
+		r = s2.EmptyRect()
+		for i := 0; i < shape.NumEdges(); i++ {
+			r = r.AddPoint(s2.LatLngFromPoint(shape.Edge(i).V0))
+		}
+	}
+	return [2]float64{r.Lo().Lng.Degrees(), r.Lo().Lat.Degrees()},
+		[2]float64{r.Hi().Lng.Degrees(), r.Hi().Lat.Degrees()}
+}
+
+func point(lon, lat float64) s2.Point {
+	return s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lon))
+}
+
+// boxShape returns a shape that covers the lon/lat box
+func boxShape(min, max [2]float64) s2.Shape {
+	if min == max {
+		pts := s2.PointVector{point(min[0], min[1])}
+		return &pts
+	}
+	n := int(math.Ceil((max[0] - min[0]) / densify))
+	if n < 1 {
+		n = 1
+	}
+	var loop []s2.Point
+	for i := 0; i < n; i++ {
+		loop = append(loop,
+			point(min[0]+(max[0]-min[0])*float64(i)/float64(n), min[1]))
+	}
+	for i := 0; i < n; i++ {
+		loop = append(loop,
+			point(max[0]-(max[0]-min[0])*float64(i)/float64(n), max[1]))
+	}
+	return s2.LaxPolygonFromPoints([][]s2.Point{loop})
+}
+
+func intersects(aMin, aMax, bMin, bMax [2]float64) bool {
+	return !(bMin[0] > aMax[0] || bMax[0] < aMin[0] ||
+		bMin[1] > aMax[1] || bMax[1] < aMin[1])
+}
+
+// Insert an item into the index
+func (idx *Index) Insert(min, max [2]float64, data interface{}) {
+	shape, ok := data.(s2.Shape)
+	if !ok {
+		shape = boxShape(min, max)
+	}
+	var id int32
+	if idx.index.IsFresh() {
+		// The ShapeIndex has been built, so the shape waits to be added by
+		// the next rebuild, see rebuild. Pending ids are negative so that
+		// they never collide with the ids of the ShapeIndex.
+		id = -int32(len(idx.pending)) - 1
+		idx.pending = append(idx.pending, id)
+	} else {
+		id = idx.index.Add(shape)
+	}
+	idx.entries[id] = &entry{min: min, max: max, data: data, shape: shape}
+	key := [2][2]float64{min, max}
+	idx.boxes[key] = append(idx.boxes[key], id)
+}
+
+// Delete an item from the index
+func (idx *Index) Delete(min, max [2]float64, data interface{}) {
+	key := [2][2]float64{min, max}
+	ids := idx.boxes[key]
+	for i, id := range ids {
+		if idx.entries[id].data == data {
+			// The shape is left in the ShapeIndex, or in the pending list,
+			// until the next rebuild.
+			delete(idx.entries, id)
+			ids[i] = ids[len(ids)-1]
+			if len(ids) == 1 {
+				delete(idx.boxes, key)
+			} else {
+				idx.boxes[key] = ids[:len(ids)-1]
+			}
+			idx.dead++
+			return
+		}
+	}
+}
+
+// rebuild replaces the ShapeIndex with a new one that only has the shapes of
+// the live entries, including the pending ones.
+// The ShapeIndex is not modified once it has been built, because incremental
+// updates to an s2.ShapeIndex are not reliable.
+func (idx *Index) rebuild() {
+	index := s2.NewShapeIndex()
+	entries := make(map[int32]*entry, len(idx.entries))
+	boxes := make(map[[2][2]float64][]int32, len(idx.boxes))
+	for _, e := range idx.entries {
+		id := index.Add(e.shape)
+		entries[id] = e
+		key := [2][2]float64{e.min, e.max}
+		boxes[key] = append(boxes[key], id)
+	}
+	index.Build()
+	idx.index, idx.entries, idx.boxes = index, entries, boxes
+	idx.dead, idx.pending = 0, nil
+}
+
+// Replace an item in the index
+func (idx *Index) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	idx.Delete(oldMin, oldMax, oldData)
+	idx.Insert(newMin, newMax, newData)
+}
+
+func (idx *Index) search(min, max [2]float64,
+	iter func(id int32, e *entry) bool,
+) {
+	if len(idx.entries) == 0 {
+		return
+	}
+	if len(idx.pending) > len(idx.entries)/8 || idx.dead > len(idx.entries) {
+		idx.rebuild()
+	}
+	rect := s2.RectFromLatLng(s2.LatLngFromDegrees(min[1], min[0])).
+		AddPoint(s2.LatLngFromDegrees(max[1], max[0]))
+	covering := idx.coverer.Covering(rect)
+	opts := s2.NewClosestEdgeQueryOptions().
+		IncludeInteriors(true).
+		DistanceLimit(s1.ChordAngle(0).Successor())
+	query := s2.NewClosestEdgeQuery(idx.index, opts)
+	seen := make(map[int32]bool)
+	for _, cell := range covering {
+		target := s2.NewMinDistanceToCellTarget(s2.CellFromCellID(cell))
+		for _, r := range query.FindEdges(target) {
+			id := r.ShapeID()
+			if id < 0 || seen[id] {
+				continue
+			}
+			seen[id] = true
+			e := idx.entries[id]
+			if e == nil || !intersects(min, max, e.min, e.max) {
+				continue
+			}
+			if !iter(id, e) {
+				return
+			}
+		}
+	}
+	for _, id := range idx.pending {
+		e := idx.entries[id]
+		if e == nil || !intersects(min, max, e.min, e.max) {
+			continue
+		}
+		if !iter(id, e) {
+			return
+		}
+	}
+}
+
+// Search the index for items that intersects the rect param
+func (idx *Index) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	idx.search(min, max, func(id int32, e *entry) bool {
+		return iter(e.min, e.max, e.data)
+	})
+}
+
+// Scan iterates through all data in the index in no specified order.
+func (idx *Index) Scan(iter func(min, max [2]float64, data interface{}) bool) {
+	for _, e := range idx.entries {
+		if !iter(e.min, e.max, e.data) {
+			return
+		}
+	}
+}
+
+// Len returns the number of items in the index
+func (idx *Index) Len() int {
+	return len(idx.entries)
+}
+
+// Bounds returns the minimum bounding box
+func (idx *Index) Bounds() (min, max [2]float64) {
+	var i int
+	for _, e := range idx.entries {
+		if i == 0 {
+			min, max = e.min, e.max
+		} else {
+			for j := 0; j < 2; j++ {
+				min[j] = math.Min(min[j], e.min[j])
+				max[j] = math.Max(max[j], e.max[j])
+			}
+		}
+		i++
+	}
+	return min, max
+}
+
+// root is the single synthesized node holding all items
+type root struct{}
+
+// Children returns all children for parent node. If parent node is nil
+// then the root node is returned. The items of the root node are in the
+// order of their shape ids, so that the order is the same between calls.
+func (idx *Index) Children(parent interface{}, reuse []child.Child) (
+	children []child.Child,
+) {
+	children = reuse
+	if parent == nil {
+		if len(idx.entries) > 0 {
+			min, max := idx.Bounds()
+			children = append(children, child.Child{
+				Min: min, Max: max, Data: root{},
+			})
+		}
+		return children
+	}
+	ids := make([]int32, 0, len(idx.entries))
+	for id := range idx.entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		e := idx.entries[id]
+		children = append(children, child.Child{
+			Min: e.min, Max: e.max, Data: e.data, Item: true,
+		})
+	}
+	return children
+}
+
+type cell struct {
+	cell  s2.Cell
+	bound s2.Rect
+}
+
+// Cell returns a geoindex.Cell for an S2 cell, which is used to search a
+// geoindex.Index for the items that intersect the cell.
+func Cell(id s2.CellID) geoindex.Cell {
+	c := s2.CellFromCellID(id)
+	return &cell{cell: c, bound: c.RectBound()}
+}
+
+// CellUnion returns the geoindex.Cells of the cell union, such as the
+// covering of a region by an s2.RegionCoverer, for Index.SearchCellUnion.
+//
+//	covering := coverer.Covering(region)
+//	index.SearchCellUnion(s2index.CellUnion(s2.CellUnion(covering)), iter)
+func CellUnion(cu s2.CellUnion) []geoindex.Cell {
+	cells := make([]geoindex.Cell, len(cu))
+	for i, id := range cu {
+		cells[i] = Cell(id)
+	}
+	return cells
+}
+
+// Bounds returns the lon,lat bounding box of the cell
+func (c *cell) Bounds() (min, max [2]float64) {
+	if c.bound.Lng.IsFull() {
+		return [2]float64{-180, c.bound.Lo().Lat.Degrees()},
+			[2]float64{180, c.bound.Hi().Lat.Degrees()}
+	}
+	return [2]float64{c.bound.Lo().Lng.Degrees(), c.bound.Lo().Lat.Degrees()},
+		[2]float64{c.bound.Hi().Lng.Degrees(), c.bound.Hi().Lat.Degrees()}
+}
+
+// Intersects returns true when the cell contains any part of the lon,lat box
+func (c *cell) Intersects(min, max [2]float64) bool {
+	if min == max {
+		return c.cell.ContainsPoint(point(min[0], min[1]))
+	}
+	rect := s2.Rect{
+		Lat: r1.Interval{
+			Lo: (s1.Angle(min[1]) * s1.Degree).Radians(),
+			Hi: (s1.Angle(max[1]) * s1.Degree).Radians(),
+		},
+		Lng: s1.IntervalFromEndpoints(
+			(s1.Angle(min[0]) * s1.Degree).Radians(),
+			(s1.Angle(max[0]) * s1.Degree).Radians(),
+		),
+	}
+	return rect.IntersectsCell(c.cell)
+}
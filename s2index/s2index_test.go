@@ -0,0 +1,153 @@
+package s2index
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"github.com/tidwall/geoindex"
+)
+
+func TestGeoIndex(t *testing.T) {
+	t.Run("RandomRects", func(t *testing.T) {
+		geoindex.Tests.TestRandomRects(t, New(), 200)
+	})
+	t.Run("RandomPoints", func(t *testing.T) {
+		geoindex.Tests.TestRandomPoints(t, New(), 200)
+	})
+}
+
+func TestWrap(t *testing.T) {
+	index := s2.NewShapeIndex()
+	for i := 0; i < 100; i++ {
+		pts := s2.PointVector{point(rand.Float64()*20, rand.Float64()*20)}
+		index.Add(&pts)
+	}
+	idx := Wrap(index)
+	if idx.Len() != 100 {
+		t.Fatalf("expected %d, got %d", 100, idx.Len())
+	}
+	var count int
+	idx.Search([2]float64{0, 0}, [2]float64{10, 20},
+		func(min, max [2]float64, data interface{}) bool {
+			if min[0] > 10 {
+				t.Fatalf("unexpected item %v", min)
+			}
+			count++
+			return true
+		},
+	)
+	var expect int
+	idx.Scan(func(min, max [2]float64, data interface{}) bool {
+		if min[0] <= 10 {
+			expect++
+		}
+		return true
+	})
+	if count != expect {
+		t.Fatalf("expected %d, got %d", expect, count)
+	}
+}
+
+func TestChildrenOrder(t *testing.T) {
+	idx := New()
+	for i := 0; i < 100; i++ {
+		p := [2]float64{rand.Float64() * 20, rand.Float64() * 20}
+		idx.Insert(p, p, i)
+	}
+	root := idx.Children(nil, nil)[0].Data
+	first := idx.Children(root, nil)
+	for i := 0; i < 10; i++ {
+		children := idx.Children(root, nil)
+		for j := range children {
+			if children[j] != first[j] {
+				t.Fatalf("expected %v, got %v", first[j], children[j])
+			}
+		}
+	}
+}
+
+func TestPending(t *testing.T) {
+	idx := New()
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i % 10), float64(i / 10)}
+		idx.Insert(p, p, i)
+	}
+	count := func(min, max [2]float64) int {
+		var n int
+		idx.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	if n := count([2]float64{0, 0}, [2]float64{20, 20}); n != 100 {
+		t.Fatalf("expected %d, got %d", 100, n)
+	}
+	shapes := idx.ShapeIndex()
+	for i := 0; i < 10; i++ {
+		p := [2]float64{15, float64(i)}
+		idx.Insert(p, p, 100+i)
+	}
+	idx.Delete([2]float64{15, 0}, [2]float64{15, 0}, 100)
+	if n := count([2]float64{14, 0}, [2]float64{16, 20}); n != 9 {
+		t.Fatalf("expected %d, got %d", 9, n)
+	}
+	if idx.ShapeIndex() != shapes {
+		t.Fatal("expected no rebuild")
+	}
+	for i := 0; i < 10; i++ {
+		p := [2]float64{16, float64(i)}
+		idx.Insert(p, p, 110+i)
+	}
+	if n := count([2]float64{14, 0}, [2]float64{16, 20}); n != 19 {
+		t.Fatalf("expected %d, got %d", 19, n)
+	}
+	if idx.ShapeIndex() == shapes || len(idx.pending) != 0 {
+		t.Fatal("expected a rebuild")
+	}
+}
+
+func TestCellUnion(t *testing.T) {
+	index := geoindex.Wrap(New())
+	type pt struct{ lon, lat float64 }
+	var pts []pt
+	for i := 0; i < 1000; i++ {
+		p := pt{rand.Float64()*40 - 20, rand.Float64()*40 - 20}
+		pts = append(pts, p)
+		index.Insert([2]float64{p.lon, p.lat}, [2]float64{p.lon, p.lat}, i)
+	}
+	// cover a cap with a few cells, which are larger than the cap
+	region := s2.CapFromCenterAngle(point(0, 0), 10*s1.Degree)
+	coverer := &s2.RegionCoverer{MaxLevel: 30, MaxCells: 8}
+	covering := coverer.Covering(region)
+	cells := CellUnion(covering)
+	seen := make(map[interface{}]bool)
+	index.SearchCellUnion(cells,
+		func(min, max [2]float64, data interface{}) bool {
+			if seen[data] {
+				t.Fatalf("duplicate item %v", data)
+			}
+			seen[data] = true
+			return true
+		},
+	)
+	for i, p := range pts {
+		if covering.ContainsPoint(point(p.lon, p.lat)) != seen[i] {
+			t.Fatalf("%v: expected %v", p, !seen[i])
+		}
+	}
+	if len(seen) == 0 {
+		t.Fatal("expected items")
+	}
+	// a cell that crosses the antimeridian, and a box that doesn't
+	c := Cell(s2.CellIDFromLatLng(s2.LatLngFromDegrees(0, 180)).Parent(3))
+	if min, max := c.Bounds(); min[0] <= max[0] {
+		t.Fatalf("expected a wrapped cell, got %v %v", min, max)
+	}
+	if !c.Intersects([2]float64{175, -1}, [2]float64{179, 1}) ||
+		c.Intersects([2]float64{0, 0}, [2]float64{1, 1}) {
+		t.Fatal("unexpected intersects")
+	}
+}
@@ -0,0 +1,139 @@
+package geoindex
+
+import "sync"
+
+// SafeIndex is an Index that can be shared by multiple goroutines. A
+// sync.RWMutex protects the wrapped tree, where operations that modify the
+// tree hold the write lock, and operations that read the tree hold the read
+// lock.
+//
+// Search and Scan copy the matching items while holding the read lock, and
+// then call the `iter` function without the lock. This means that `iter` may
+// take its time, and may even modify the index, without blocking writers.
+//
+// The rest of the Index API may be used with the Read or Write functions.
+type SafeIndex struct {
+	mu    sync.RWMutex
+	index *Index
+}
+
+// SafeWrap wraps a tree-like geospatial interface in an Index that's safe for
+// concurrent use.
+func SafeWrap(tree Interface) *SafeIndex {
+	return &SafeIndex{index: Wrap(tree)}
+}
+
+// Read calls fn with the wrapped Index while holding the read lock. The fn
+// function must not modify the index.
+func (s *SafeIndex) Read(fn func(index *Index)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.index)
+}
+
+// Write calls fn with the wrapped Index while holding the write lock.
+func (s *SafeIndex) Write(fn func(index *Index)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.index)
+}
+
+// Insert an item into the index
+func (s *SafeIndex) Insert(min, max [2]float64, data interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index.Insert(min, max, data)
+}
+
+// Delete an item from the index. Returns true when an item was deleted.
+func (s *SafeIndex) Delete(min, max [2]float64, data interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.index.Delete(min, max, data)
+}
+
+// Replace an item in the index. Returns true when the old item was deleted.
+func (s *SafeIndex) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) (deleted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.index.Replace(oldMin, oldMax, oldData, newMin, newMax, newData)
+}
+
+// Load inserts the items into the index. See Index.Load.
+func (s *SafeIndex) Load(mins, maxs [][2]float64, data []interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index.Load(mins, maxs, data)
+}
+
+// iterate calls iter for each of the items, stopping when iter returns false.
+func iterate(items []Item,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	for _, item := range items {
+		if !iter(item.Min, item.Max, item.Data) {
+			return
+		}
+	}
+}
+
+// Search the index for items that intersects the rect param. The items are
+// copied before calling `iter`.
+func (s *SafeIndex) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	var items []Item
+	s.mu.RLock()
+	s.index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		items = append(items, Item{min, max, data})
+		return true
+	})
+	s.mu.RUnlock()
+	iterate(items, iter)
+}
+
+// Scan iterates through all data in the index in no specified order. The
+// items are copied before calling `iter`.
+func (s *SafeIndex) Scan(
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	s.mu.RLock()
+	items := make([]Item, 0, s.index.Len())
+	s.index.Scan(func(min, max [2]float64, data interface{}) bool {
+		items = append(items, Item{min, max, data})
+		return true
+	})
+	s.mu.RUnlock()
+	iterate(items, iter)
+}
+
+// Nearby performs a kNN-type operation on the index. See Index.Nearby.
+// Unlike Search and Scan, the items are not copied, because the operation
+// is usually stopped early, so the read lock is held while calling `iter`
+// and it must not modify the index.
+func (s *SafeIndex) Nearby(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.index.Nearby(algo, iter)
+}
+
+// Len returns the number of items in the index
+func (s *SafeIndex) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.Len()
+}
+
+// Bounds returns the minimum bounding box
+func (s *SafeIndex) Bounds() (min, max [2]float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.Bounds()
+}
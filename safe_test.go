@@ -0,0 +1,84 @@
+package geoindex
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSafeIndex(t *testing.T) {
+	index := SafeWrap(&internal.RTree{})
+	boxes := randBoxes(4000)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(boxes); i += 4 {
+				index.Insert(boxes[i].min, boxes[i].max, i)
+				if i%3 == 0 {
+					index.Delete(boxes[i].min, boxes[i].max, i)
+				}
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				index.Search(boxes[i].min, boxes[i].max,
+					func(min, max [2]float64, data interface{}) bool {
+						return true
+					},
+				)
+				index.Nearby(algo.Box(boxes[i].min, boxes[i].max, false, nil),
+					func(min, max [2]float64, data interface{},
+						dist float64) bool {
+						return dist < 10
+					},
+				)
+				index.Len()
+				index.Bounds()
+			}
+		}()
+	}
+	wg.Wait()
+	var expect int
+	for i := range boxes {
+		if i%3 != 0 {
+			expect++
+		}
+	}
+	if index.Len() != expect {
+		t.Fatalf("expected %d, got %d", expect, index.Len())
+	}
+	index.Read(func(index *Index) {
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// iter may modify the index
+	var n int
+	index.Scan(func(min, max [2]float64, data interface{}) bool {
+		index.Delete(min, max, data)
+		n++
+		return true
+	})
+	if n != expect || index.Len() != 0 {
+		t.Fatalf("expected %d/%d, got %d/%d", expect, 0, n, index.Len())
+	}
+	index.Insert(boxes[1].min, boxes[1].max, 1)
+	index.Replace(boxes[1].min, boxes[1].max, 1, boxes[2].min, boxes[2].max, 2)
+	index.Search(boxes[2].min, boxes[2].max,
+		func(min, max [2]float64, data interface{}) bool {
+			index.Insert(min, max, -1)
+			return true
+		},
+	)
+	index.Write(func(index *Index) {
+		if index.Len() != 2 {
+			t.Fatalf("expected %d, got %d", 2, index.Len())
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// segmentIntersects returns true when segment AB crosses or touches the box,
+// using the slab method, where the segment is clipped to the range of the
+// box on each axis in turn.
+func segmentIntersects(a, b, min, max [2]float64) bool {
+	t0, t1 := 0.0, 1.0
+	for i := 0; i < 2; i++ {
+		d := b[i] - a[i]
+		if d == 0 {
+			if a[i] < min[i] || a[i] > max[i] {
+				return false
+			}
+			continue
+		}
+		lo, hi := (min[i]-a[i])/d, (max[i]-a[i])/d
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if lo > t0 {
+			t0 = lo
+		}
+		if hi < t1 {
+			t1 = hi
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchSegment searches the index for items whose box is crossed by the
+// line segment from A to B, such as for line-of-sight checks or for snapping
+// a route to the nearby roads. Only the nodes that the segment crosses are
+// visited, which is usually far fewer than a Search with the bounding box of
+// the segment, especially for long diagonal segments.
+// The items are returned in no specified order.
+// This function uses the Children of the wrapped tree.
+func (index *Index) SearchSegment(
+	a, b [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	defer index.stats.end(opSearch, index.stats.begin())
+	var search func(children []child.Child) bool
+	search = func(children []child.Child) bool {
+		for _, c := range children {
+			if !segmentIntersects(a, b, c.Min, c.Max) {
+				continue
+			}
+			if c.Item {
+				if !iter(c.Min, c.Max, c.Data) {
+					return false
+				}
+			} else if !search(index.tree.Children(c.Data, nil)) {
+				return false
+			}
+		}
+		return true
+	}
+	search(index.tree.Children(nil, nil))
+}
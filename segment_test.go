@@ -0,0 +1,70 @@
+package geoindex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSegmentIntersects(t *testing.T) {
+	min, max := [2]float64{0, 0}, [2]float64{10, 10}
+	for _, tc := range []struct {
+		a, b   [2]float64
+		expect bool
+	}{
+		{[2]float64{-5, 5}, [2]float64{15, 5}, true},    // through
+		{[2]float64{2, 2}, [2]float64{3, 3}, true},      // inside
+		{[2]float64{-5, -5}, [2]float64{-1, -1}, false}, // before
+		{[2]float64{-5, 6}, [2]float64{6, 17}, false},   // past the corner
+		{[2]float64{-5, 5}, [2]float64{5, 15}, true},    // touches the corner
+		{[2]float64{10, -5}, [2]float64{10, 15}, true},  // on the edge
+		{[2]float64{11, -5}, [2]float64{11, 15}, false},
+		{[2]float64{5, 5}, [2]float64{5, 5}, true},
+	} {
+		if got := segmentIntersects(tc.a, tc.b, min, max); got != tc.expect {
+			t.Fatalf("%v %v: expected %v, got %v", tc.a, tc.b, tc.expect, got)
+		}
+	}
+}
+
+func TestSearchSegment(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	for i := 0; i < 100; i++ {
+		a := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		b := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		expect := make(map[int]bool)
+		for j, box := range boxes {
+			if segmentIntersects(a, b, box.min, box.max) {
+				expect[j] = true
+			}
+		}
+		var n int
+		index.SearchSegment(a, b,
+			func(min, max [2]float64, data interface{}) bool {
+				if !expect[data.(int)] {
+					t.Fatalf("%v %v: unexpected %v", a, b, data)
+				}
+				n++
+				return true
+			},
+		)
+		if n != len(expect) {
+			t.Fatalf("%v %v: expected %d, got %d", a, b, len(expect), n)
+		}
+	}
+	var n int
+	index.SearchSegment([2]float64{-180, -90}, [2]float64{180, 90},
+		func(min, max [2]float64, data interface{}) bool {
+			n++
+			return false
+		},
+	)
+	if n != 1 {
+		t.Fatalf("expected %d, got %d", 1, n)
+	}
+}
@@ -0,0 +1,225 @@
+package geoindex
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+	"github.com/tidwall/geoindex/pqueue"
+)
+
+// ErrBadSession is returned by ResumeNearbySession when the state is not
+// valid or doesn't match the tree.
+var ErrBadSession = errors.New("geoindex: bad session")
+
+const sessionMagic = "GEOIDXN1"
+
+type sessionNode struct {
+	child child.Child
+	// path is the position of the child in the Children of each of its
+	// parents, starting from the root.
+	path []int
+}
+
+// NearbySession is a kNN-type operation that can be paused, serialized, and
+// resumed, such as for a paging API that returns the next nearest items
+// across many requests. The state of the session is the priority queue of
+// the operation, which holds the frontier of the nodes that haven't been
+// visited yet, so a resumed session continues from where it was paused
+// without recalculating the items that were already returned.
+//
+// The nodes in the serialized state are the positions of the nodes in the
+// Children of their parents, so a state may only be resumed by the same
+// tree, and the tree must not be modified in between. The tree's Children
+// must also return the children of a node in the same order on every call,
+// otherwise a resumed session visits the wrong nodes. When the tree is
+// Versioned, ResumeNearbySession returns ErrConcurrentModification for a
+// tree that has been modified.
+type NearbySession struct {
+	index *Index
+	algo  func(min, max [2]float64, data interface{}, item bool) (
+		dist float64)
+	q         pqueue.Queue[sessionNode]
+	started   bool
+	version   uint64
+	versioned bool
+}
+
+// NearbySession starts a new session of a kNN-type operation on the index.
+// See Nearby for a description of the `algo` function.
+func (index *Index) NearbySession(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+) *NearbySession {
+	s := &NearbySession{index: index, algo: algo}
+	s.version, s.versioned = index.Version()
+	return s
+}
+
+func (s *NearbySession) push(children []child.Child, path []int) {
+	for i, c := range children {
+		npath := make([]int, len(path)+1)
+		copy(npath, path)
+		npath[len(path)] = i
+		s.q.Push(sessionNode{c, npath}, s.algo(c.Min, c.Max, c.Data, c.Item))
+	}
+}
+
+// Next returns up to limit of the next nearest items, from the smallest dist
+// to the largest dist. Returns fewer than limit items when there are no more
+// items.
+func (s *NearbySession) Next(limit int) []Result {
+	defer s.index.stats.end(opNearby, s.index.stats.begin())
+	if !s.started {
+		s.push(s.index.tree.Children(nil, nil), nil)
+		s.started = true
+	}
+	var results []Result
+	for len(results) < limit {
+		node, dist, ok := s.q.Pop()
+		if !ok {
+			break
+		}
+		if node.child.Item {
+			results = append(results, Result{node.child.Min, node.child.Max,
+				node.child.Data, dist})
+			continue
+		}
+		s.push(s.index.tree.Children(node.child.Data, nil), node.path)
+	}
+	return results
+}
+
+// MarshalBinary returns the state of the session, which can be resumed with
+// ResumeNearbySession. The state has the dist and the position of each node
+// and item in the queue, but not the item data.
+func (s *NearbySession) MarshalBinary() ([]byte, error) {
+	b := append([]byte(nil), sessionMagic...)
+	var flags byte
+	if s.started {
+		flags |= 1
+	}
+	if s.versioned {
+		flags |= 2
+	}
+	b = append(b, flags)
+	b = appendUvarint(b, s.version)
+	b = appendUvarint(b, uint64(s.q.Len()))
+	// Pop all of the nodes and push them back in the same order, which
+	// keeps the order of the nodes that have the same dist.
+	nodes := make([]sessionNode, 0, s.q.Len())
+	dists := make([]float64, 0, s.q.Len())
+	for {
+		node, dist, ok := s.q.Pop()
+		if !ok {
+			break
+		}
+		nodes = append(nodes, node)
+		dists = append(dists, dist)
+	}
+	for i, node := range nodes {
+		s.q.Push(node, dists[i])
+		b = appendUint64(b, math.Float64bits(dists[i]))
+		b = appendUvarint(b, uint64(len(node.path)))
+		for _, j := range node.path {
+			b = appendUvarint(b, uint64(j))
+		}
+	}
+	return b, nil
+}
+
+// ResumeNearbySession resumes a session from a state that was returned by
+// MarshalBinary. The algo must be the same as the one of the paused session.
+// See NearbySession for the requirements of the tree.
+func (index *Index) ResumeNearbySession(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	state []byte,
+) (*NearbySession, error) {
+	if len(state) < len(sessionMagic)+1 ||
+		string(state[:len(sessionMagic)]) != sessionMagic {
+		return nil, ErrBadSession
+	}
+	flags := state[len(sessionMagic)]
+	b := state[len(sessionMagic)+1:]
+	uvarint := func() (uint64, bool) {
+		x, n := binary.Uvarint(b)
+		if n <= 0 {
+			return 0, false
+		}
+		b = b[n:]
+		return x, true
+	}
+	s := index.NearbySession(algo)
+	s.started = flags&1 != 0
+	version, ok := uvarint()
+	if !ok {
+		return nil, ErrBadSession
+	}
+	if flags&2 != 0 && (!s.versioned || version != s.version) {
+		return nil, ErrConcurrentModification
+	}
+	count, ok := uvarint()
+	if !ok || count > uint64(len(b)) {
+		return nil, ErrBadSession
+	}
+	// the children of the nodes that were already found, by their path
+	children := make(map[string][]child.Child)
+	var childrenOf func(path []int) ([]child.Child, bool)
+	childrenOf = func(path []int) ([]child.Child, bool) {
+		key := sessionKey(path)
+		if cs, ok := children[key]; ok {
+			return cs, true
+		}
+		var parent interface{}
+		if len(path) > 0 {
+			cs, ok := childrenOf(path[:len(path)-1])
+			if !ok || path[len(path)-1] >= len(cs) {
+				return nil, false
+			}
+			c := cs[path[len(path)-1]]
+			if c.Item {
+				return nil, false
+			}
+			parent = c.Data
+		}
+		cs := index.tree.Children(parent, nil)
+		children[key] = cs
+		return cs, true
+	}
+	for i := uint64(0); i < count; i++ {
+		if len(b) < 8 {
+			return nil, ErrBadSession
+		}
+		dist := math.Float64frombits(binary.LittleEndian.Uint64(b))
+		b = b[8:]
+		n, ok := uvarint()
+		if !ok || n == 0 || n > uint64(len(b)) {
+			return nil, ErrBadSession
+		}
+		path := make([]int, n)
+		for j := range path {
+			x, ok := uvarint()
+			if !ok || x > math.MaxInt32 {
+				return nil, ErrBadSession
+			}
+			path[j] = int(x)
+		}
+		cs, ok := childrenOf(path[:n-1])
+		if !ok || path[n-1] >= len(cs) {
+			return nil, ErrBadSession
+		}
+		s.q.Push(sessionNode{cs[path[n-1]], path}, dist)
+	}
+	if len(b) != 0 {
+		return nil, ErrBadSession
+	}
+	return s, nil
+}
+
+func sessionKey(path []int) string {
+	var b []byte
+	for _, i := range path {
+		b = appendUvarint(b, uint64(i))
+	}
+	return string(b)
+}
@@ -0,0 +1,70 @@
+package geoindex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbySession(t *testing.T) {
+	for _, tr := range []Interface{
+		&internal.RTree{}, struct{ Interface }{&internal.RTree{}},
+	} {
+		index := Wrap(tr)
+		for i, box := range randBoxes(1000) {
+			index.Insert(box.min, box.max, i)
+		}
+		target := [2]float64{10, 20}
+		algo := algo.Box(target, target, false, nil)
+		var expect []Result
+		index.Nearby(algo,
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				expect = append(expect, Result{min, max, data, dist})
+				return true
+			},
+		)
+		// pages of 30 items, with a serialized state between the pages
+		var results []Result
+		s := index.NearbySession(algo)
+		for {
+			page := s.Next(30)
+			results = append(results, page...)
+			if len(page) < 30 {
+				break
+			}
+			state, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s, err = index.ResumeNearbySession(algo, state); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(results) != len(expect) {
+			t.Fatalf("expected %d, got %d", len(expect), len(results))
+		}
+		for i := range results {
+			if results[i].Dist != expect[i].Dist {
+				t.Fatalf("%d: expected %v, got %v", i, expect[i], results[i])
+			}
+		}
+		s = index.NearbySession(algo)
+		s.Next(10)
+		state, _ := s.MarshalBinary()
+		for _, bad := range [][]byte{nil, state[:len(state)-1],
+			append(state[:len(state):len(state)], 0)} {
+			if _, err := index.ResumeNearbySession(algo, bad); err != ErrBadSession {
+				t.Fatalf("expected %v, got %v", ErrBadSession, err)
+			}
+		}
+		index.Insert(target, target, -1)
+		_, err := index.ResumeNearbySession(algo, state)
+		if _, ok := tr.(Versioned); ok {
+			if !errors.Is(err, ErrConcurrentModification) {
+				t.Fatalf("expected %v, got %v", ErrConcurrentModification, err)
+			}
+		}
+	}
+}
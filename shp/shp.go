@@ -0,0 +1,119 @@
+// Package shp loads the bounding boxes of the shapes in an ESRI shapefile
+// into a geoindex.Interface.
+package shp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+
+	"github.com/tidwall/geoindex"
+)
+
+// ErrBadShapefile is returned by LoadSHP when the file is not a valid
+// shapefile.
+var ErrBadShapefile = errors.New("shp: bad shapefile")
+
+const (
+	fileCode   = 9994
+	headerSize = 100
+)
+
+// LoadSHP loads the bounding boxes of the shapes in the .shp file at path
+// into the tree, where the data of each item is the record number of its
+// shape, starting at one, which is also the position of the shape's
+// attributes in the .dbf file. Only the header of each record is read, and
+// the rest of the record is skipped without decoding the geometry, which makes
+// this much faster than a complete decode for huge shapefiles. Records with a
+// null shape are skipped. Returns the number of items that were loaded.
+// Nothing is loaded when there's an error.
+func LoadSHP(path string, tr geoindex.Interface) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return load(f, tr)
+}
+
+func load(r io.Reader, tr geoindex.Interface) (int, error) {
+	br := bufio.NewReader(r)
+	var header [headerSize]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return 0, badFile(err)
+	}
+	if binary.BigEndian.Uint32(header[0:]) != fileCode ||
+		binary.LittleEndian.Uint32(header[28:]) != 1000 {
+		return 0, ErrBadShapefile
+	}
+	// the file length is in 16-bit words
+	remain := int64(binary.BigEndian.Uint32(header[24:]))*2 - headerSize
+	var mins, maxs [][2]float64
+	var data []interface{}
+	var rec [8 + 4 + 32]byte
+	for remain > 0 {
+		if _, err := io.ReadFull(br, rec[:12]); err != nil {
+			return 0, badFile(err)
+		}
+		num := int(binary.BigEndian.Uint32(rec[0:]))
+		size := int64(binary.BigEndian.Uint32(rec[4:])) * 2
+		if size < 4 || 8+size > remain {
+			return 0, ErrBadShapefile
+		}
+		remain -= 8 + size
+		size -= 4
+		var min, max [2]float64
+		switch binary.LittleEndian.Uint32(rec[8:]) {
+		case 0: // null
+			if _, err := br.Discard(int(size)); err != nil {
+				return 0, badFile(err)
+			}
+			continue
+		case 1, 11, 21: // points
+			if size < 16 {
+				return 0, ErrBadShapefile
+			}
+			if _, err := io.ReadFull(br, rec[12:28]); err != nil {
+				return 0, badFile(err)
+			}
+			min = [2]float64{float(rec[12:]), float(rec[20:])}
+			max = min
+			size -= 16
+		case 3, 5, 8, 13, 15, 18, 23, 25, 28, 31: // shapes with a box
+			if size < 32 {
+				return 0, ErrBadShapefile
+			}
+			if _, err := io.ReadFull(br, rec[12:44]); err != nil {
+				return 0, badFile(err)
+			}
+			min = [2]float64{float(rec[12:]), float(rec[20:])}
+			max = [2]float64{float(rec[28:]), float(rec[36:])}
+			size -= 32
+		default:
+			return 0, ErrBadShapefile
+		}
+		if _, err := br.Discard(int(size)); err != nil {
+			return 0, badFile(err)
+		}
+		mins = append(mins, min)
+		maxs = append(maxs, max)
+		data = append(data, num)
+	}
+	geoindex.Wrap(tr).Load(mins, maxs, data)
+	return len(data), nil
+}
+
+func float(b []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+// badFile returns ErrBadShapefile for a file that ended too early
+func badFile(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrBadShapefile
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package shp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// shapefile returns a shapefile that has the records
+func shapefile(records ...[]byte) []byte {
+	var body []byte
+	for i, content := range records {
+		var h [8]byte
+		binary.BigEndian.PutUint32(h[0:], uint32(i+1))
+		binary.BigEndian.PutUint32(h[4:], uint32(len(content)/2))
+		body = append(body, h[:]...)
+		body = append(body, content...)
+	}
+	header := make([]byte, headerSize)
+	binary.BigEndian.PutUint32(header[0:], fileCode)
+	binary.BigEndian.PutUint32(header[24:], uint32(headerSize+len(body))/2)
+	binary.LittleEndian.PutUint32(header[28:], 1000)
+	return append(header, body...)
+}
+
+// record returns the content of a record, which is the shape type and the
+// values
+func record(typ uint32, values ...float64) []byte {
+	b := appendUint32(nil, typ)
+	for _, v := range values {
+		var w [8]byte
+		binary.LittleEndian.PutUint64(w[:], math.Float64bits(v))
+		b = append(b, w[:]...)
+	}
+	return b
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var w [4]byte
+	binary.LittleEndian.PutUint32(w[:], v)
+	return append(b, w[:]...)
+}
+
+func TestLoadSHP(t *testing.T) {
+	// a polygon with its box, the counts, and the points, which are not read
+	polygon := record(5, 10, 20, 30, 40)
+	polygon = appendUint32(polygon, 1)
+	polygon = appendUint32(polygon, 2)
+	polygon = appendUint32(polygon, 0)
+	polygon = append(polygon, record(0, 10, 20, 30, 40)[4:]...)
+	data := shapefile(
+		record(1, 1, 2),
+		record(0),
+		polygon,
+		record(11, -5, -6, 7, 8),
+	)
+	path := filepath.Join(t.TempDir(), "test.shp")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	tr := &internal.RTree{}
+	n, err := LoadSHP(path, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[interface{}][2][2]float64{
+		1: {{1, 2}, {1, 2}},
+		3: {{10, 20}, {30, 40}},
+		4: {{-5, -6}, {-5, -6}},
+	}
+	if n != len(expect) || tr.Len() != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), n)
+	}
+	tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		if box, ok := expect[data]; !ok || box != [2][2]float64{min, max} {
+			t.Fatalf("unexpected %v %v %v", min, max, data)
+		}
+		return true
+	})
+	for _, bad := range [][]byte{
+		data[:50],
+		data[:len(data)-8],
+		shapefile(record(99, 1, 2)),
+		shapefile(record(5, 1, 2)),
+		append([]byte{1}, data[1:]...),
+	} {
+		tr := &internal.RTree{}
+		if n, err := load(bytes.NewReader(bad), tr); err != ErrBadShapefile ||
+			n != 0 || tr.Len() != 0 {
+			t.Fatalf("expected %v, got %v", ErrBadShapefile, err)
+		}
+	}
+	if _, err := LoadSHP(filepath.Join(t.TempDir(), "missing.shp"),
+		tr); !os.IsNotExist(err) {
+		t.Fatalf("expected a not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+type skylineItem struct {
+	child child.Child
+	score float64
+}
+
+// Skyline returns the items that are not dominated by any other item, where
+// an item dominates another when it is both nearer to the target and has a
+// higher score, or is equally near with a higher score, or is nearer with an
+// equal score. For example, the restaurants that have no other restaurant
+// that is both closer and better rated.
+// The `algo` function calculates distances in the same way as Nearby. The
+// `score` function returns the score of an item, and for nodes (item=false)
+// it must return an upper bound of the scores of all items in that node, or
+// math.Inf(+1) when unknown. Nodes that cannot contain a result are skipped.
+// The `iter` function is called for each result from the nearest to the
+// farthest.
+func (index *Index) Skyline(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	score func(min, max [2]float64, data interface{}, item bool) float64,
+	iter func(min, max [2]float64, data interface{}, dist, score float64) bool,
+) {
+	best, bestDist := math.Inf(-1), math.Inf(-1)
+	dominated := func(dist, score float64) bool {
+		return score < best || (score == best && dist > bestDist)
+	}
+	var group []skylineItem
+	var groupDist float64
+	// flush emits the undominated items at the current group distance
+	flush := func() bool {
+		if len(group) == 0 {
+			return true
+		}
+		gmax := math.Inf(-1)
+		for _, item := range group {
+			if item.score > gmax {
+				gmax = item.score
+			}
+		}
+		for _, item := range group {
+			if item.score == gmax && !dominated(groupDist, gmax) {
+				if !iter(item.child.Min, item.child.Max, item.child.Data,
+					groupDist, item.score) {
+					return false
+				}
+			}
+		}
+		if gmax > best {
+			best, bestDist = gmax, groupDist
+		}
+		group = group[:0]
+		return true
+	}
+	var q queue
+	var children []child.Child
+	children = index.tree.Children(nil, children[:0])
+	for {
+		for _, child := range children {
+			q.push(qnode{
+				dist:  algo(child.Min, child.Max, child.Data, child.Item),
+				aux:   score(child.Min, child.Max, child.Data, child.Item),
+				child: child,
+			})
+		}
+		children = children[:0]
+		for len(children) == 0 {
+			node, ok := q.pop()
+			if !ok {
+				// nothing left in queue
+				flush()
+				return
+			}
+			if node.dist > groupDist {
+				if !flush() {
+					return
+				}
+				groupDist = node.dist
+			}
+			nscore := node.aux.(float64)
+			if dominated(node.dist, nscore) {
+				continue
+			}
+			if node.child.Item {
+				group = append(group, skylineItem{node.child, nscore})
+			} else {
+				// gather more children
+				children = index.tree.Children(node.child.Data, children)
+			}
+		}
+	}
+}
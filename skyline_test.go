@@ -0,0 +1,79 @@
+package geoindex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSkyline(t *testing.T) {
+	type restaurant struct {
+		pt     [2]float64
+		rating float64
+	}
+	index := Wrap(&internal.RTree{})
+	var all []*restaurant
+	for i := 0; i < 5000; i++ {
+		r := &restaurant{
+			pt: [2]float64{rand.Float64()*360 - 180,
+				rand.Float64()*180 - 90},
+			rating: float64(rand.Intn(50)),
+		}
+		all = append(all, r)
+		index.Insert(r.pt, r.pt, r)
+	}
+	target := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+	dist := algo.Box(target, target, false, nil)
+	var results []*restaurant
+	var lastDist float64
+	index.Skyline(dist,
+		func(min, max [2]float64, data interface{}, item bool) float64 {
+			if item {
+				return data.(*restaurant).rating
+			}
+			return math.Inf(+1)
+		},
+		func(min, max [2]float64, data interface{}, dist, score float64) bool {
+			if dist < lastDist {
+				t.Fatal("out of order")
+			}
+			lastDist = dist
+			results = append(results, data.(*restaurant))
+			return true
+		},
+	)
+
+	// brute force
+	dominates := func(a, b *restaurant) bool {
+		da := dist(a.pt, a.pt, a, true)
+		db := dist(b.pt, b.pt, b, true)
+		return (da <= db && a.rating > b.rating) ||
+			(da < db && a.rating >= b.rating)
+	}
+	var expect int
+	for _, r := range all {
+		var isDominated bool
+		for _, o := range all {
+			if dominates(o, r) {
+				isDominated = true
+				break
+			}
+		}
+		if !isDominated {
+			expect++
+		}
+	}
+	if len(results) != expect {
+		t.Fatalf("expected %d, got %d", expect, len(results))
+	}
+	for _, r := range results {
+		for _, o := range all {
+			if dominates(o, r) {
+				t.Fatalf("%v is dominated by %v", r, o)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/algo"
+)
+
+// Segmenter is implemented by item data that represents a line segment, such
+// as a piece of a road.
+type Segmenter interface {
+	Segment() (a, b [2]float64)
+}
+
+// Segment is a line segment from A to B that can be used as item data.
+// The item should be inserted using the bounding box of the segment.
+type Segment struct {
+	A, B [2]float64
+}
+
+// Segment returns the segment endpoints.
+func (seg Segment) Segment() (a, b [2]float64) {
+	return seg.A, seg.B
+}
+
+// Snap returns the segment that is nearest to the point p, the projected
+// point on that segment, and the offset along the segment from its first
+// endpoint to the projected point.
+// Only items with data that implements Segmenter are considered. Returns
+// false when no segments are found.
+func (index *Index) Snap(p [2]float64) (
+	data interface{}, point [2]float64, offset float64, ok bool,
+) {
+	index.NearbyAux(
+		func(min, max [2]float64, data interface{}, item bool) (
+			float64, interface{},
+		) {
+			if item {
+				if seg, ok := data.(Segmenter); ok {
+					a, b := seg.Segment()
+					q, _ := algo.SegmentPoint(p, a, b)
+					return algo.BoxDistCalc(p, p, q, q, false), q
+				}
+			}
+			return algo.BoxDistCalc(p, p, min, max, false), nil
+		},
+		func(min, max [2]float64, item interface{}, dist float64,
+			aux interface{},
+		) bool {
+			seg, isSeg := item.(Segmenter)
+			if !isSeg {
+				return true
+			}
+			a, _ := seg.Segment()
+			point = aux.([2]float64)
+			offset = math.Hypot(point[0]-a[0], point[1]-a[1])
+			data, ok = item, true
+			return false
+		},
+	)
+	return data, point, offset, ok
+}
@@ -0,0 +1,47 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func segBounds(seg Segment) (min, max [2]float64) {
+	min, max = seg.A, seg.A
+	for i := 0; i < 2; i++ {
+		if seg.B[i] < min[i] {
+			min[i] = seg.B[i]
+		}
+		if seg.B[i] > max[i] {
+			max[i] = seg.B[i]
+		}
+	}
+	return min, max
+}
+
+func TestSnap(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	if _, _, _, ok := index.Snap([2]float64{0, 0}); ok {
+		t.Fatal("expected no segment")
+	}
+	segs := []Segment{
+		{[2]float64{0, 0}, [2]float64{10, 0}},
+		{[2]float64{10, 0}, [2]float64{10, 10}},
+		{[2]float64{0, 20}, [2]float64{20, 20}},
+	}
+	for _, seg := range segs {
+		min, max := segBounds(seg)
+		index.Insert(min, max, seg)
+	}
+	// non-segment items are ignored
+	index.Insert([2]float64{8, 4}, [2]float64{8, 4}, "point")
+
+	data, point, offset, ok := index.Snap([2]float64{8, 4})
+	if !ok || data != segs[1] || point != [2]float64{10, 4} || offset != 4 {
+		t.Fatalf("unexpected results: %v %v %v %v", data, point, offset, ok)
+	}
+	data, point, offset, ok = index.Snap([2]float64{3, -2})
+	if !ok || data != segs[0] || point != [2]float64{3, 0} || offset != 3 {
+		t.Fatalf("unexpected results: %v %v %v %v", data, point, offset, ok)
+	}
+}
@@ -0,0 +1,135 @@
+package geoindex
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// ErrBadSnapshot is returned by ReadFrom when the snapshot is not valid.
+var ErrBadSnapshot = errors.New("geoindex: bad snapshot")
+
+const snapshotMagic = "GEOIDXS1"
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// WriteTo writes a binary snapshot of the index to w, which can be read with
+// ReadFrom. The Children of the tree are walked to write the boxes of the
+// nodes and the items, followed by the item data using encoding/gob. The
+// data must be supported by gob, and types other than the basic types must
+// be registered with gob.Register.
+func (index *Index) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	var buf []byte
+	writeBox := func(tag byte, min, max [2]float64) {
+		buf = append(buf[:0], tag)
+		for _, v := range [4]float64{min[0], min[1], max[0], max[1]} {
+			buf = appendUint64(buf, math.Float64bits(v))
+		}
+		bw.Write(buf)
+	}
+	writeUvarint := func(x uint64) {
+		buf = appendUvarint(buf[:0], x)
+		bw.Write(buf)
+	}
+	var data []interface{}
+	var walk func(children []child.Child)
+	walk = func(children []child.Child) {
+		writeUvarint(uint64(len(children)))
+		for _, c := range children {
+			if c.Item {
+				writeBox(1, c.Min, c.Max)
+				data = append(data, c.Data)
+			} else {
+				writeBox(0, c.Min, c.Max)
+				walk(index.tree.Children(c.Data, nil))
+			}
+		}
+	}
+	bw.WriteString(snapshotMagic)
+	walk(index.tree.Children(nil, nil))
+	if err := gob.NewEncoder(bw).Encode(data); err != nil {
+		return cw.n, err
+	}
+	err = bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom reads a snapshot that was written by Index.WriteTo, and returns an
+// Index for a new tree that holds the items of the snapshot. The items are
+// loaded using Index.Load. The CRS is not part of the snapshot, so the CRS
+// of the new Index is Degrees until SetCRS is called.
+func ReadFrom(r io.Reader, newTree func() Interface) (*Index, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(br, magic); err != nil ||
+		string(magic) != snapshotMagic {
+		return nil, ErrBadSnapshot
+	}
+	var mins, maxs [][2]float64
+	var buf [33]byte
+	var read func(depth int) error
+	read = func(depth int) error {
+		n, err := binary.ReadUvarint(br)
+		if err != nil || depth > 64 {
+			return ErrBadSnapshot
+		}
+		for i := uint64(0); i < n; i++ {
+			if _, err := io.ReadFull(br, buf[:]); err != nil {
+				return ErrBadSnapshot
+			}
+			var v [4]float64
+			for j := range v {
+				v[j] = math.Float64frombits(
+					binary.LittleEndian.Uint64(buf[1+j*8:]))
+			}
+			switch buf[0] {
+			case 0:
+				if err := read(depth + 1); err != nil {
+					return err
+				}
+			case 1:
+				mins = append(mins, [2]float64{v[0], v[1]})
+				maxs = append(maxs, [2]float64{v[2], v[3]})
+			default:
+				return ErrBadSnapshot
+			}
+		}
+		return nil
+	}
+	if err := read(0); err != nil {
+		return nil, err
+	}
+	var data []interface{}
+	if err := gob.NewDecoder(br).Decode(&data); err != nil {
+		return nil, err
+	}
+	if len(data) != len(mins) {
+		return nil, ErrBadSnapshot
+	}
+	index := Wrap(newTree())
+	index.Load(mins, maxs, data)
+	return index, nil
+}
+
+// appendUvarint appends the varint-encoded x to b, which is the same as
+// binary.AppendUvarint but works with Go 1.18.
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], x)]...)
+}
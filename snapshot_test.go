@@ -0,0 +1,73 @@
+package geoindex
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSnapshot(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		if i%2 == 0 {
+			index.Insert(box.min, box.max, i)
+		} else {
+			index.Insert(box.min, box.max, "item")
+		}
+	}
+	var buf bytes.Buffer
+	n, err := index.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("expected %d, got %d", buf.Len(), n)
+	}
+	index2, err := ReadFrom(bytes.NewReader(buf.Bytes()),
+		func() Interface { return &internal.RTree{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index2.Len() != index.Len() {
+		t.Fatalf("expected %d, got %d", index.Len(), index2.Len())
+	}
+	if err := index2.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[Item]int)
+	index.Scan(func(min, max [2]float64, data interface{}) bool {
+		counts[Item{min, max, data}]++
+		return true
+	})
+	index2.Scan(func(min, max [2]float64, data interface{}) bool {
+		if counts[Item{min, max, data}] == 0 {
+			t.Fatalf("unexpected item %v %v %v", min, max, data)
+		}
+		counts[Item{min, max, data}]--
+		return true
+	})
+
+	// empty
+	buf.Reset()
+	if _, err := Wrap(&internal.RTree{}).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	index3, err := ReadFrom(&buf, func() Interface { return &internal.RTree{} })
+	if err != nil || index3.Len() != 0 {
+		t.Fatalf("expected empty index, got %v", err)
+	}
+
+	// bad snapshots
+	if _, err := ReadFrom(bytes.NewReader([]byte("nope")),
+		func() Interface { return &internal.RTree{} }); err != ErrBadSnapshot {
+		t.Fatalf("expected %v, got %v", ErrBadSnapshot, err)
+	}
+	buf.Reset()
+	index.WriteTo(&buf)
+	if _, err := ReadFrom(bytes.NewReader(buf.Bytes()[:100]),
+		func() Interface { return &internal.RTree{} }); err != ErrBadSnapshot {
+		t.Fatalf("expected %v, got %v", ErrBadSnapshot, err)
+	}
+}
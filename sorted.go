@@ -0,0 +1,84 @@
+package geoindex
+
+import (
+	"fmt"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/child"
+)
+
+// SearchOrder is the order of the results from SearchSorted.
+type SearchOrder int
+
+const (
+	// OrderNone returns the results in the order of the tree's Search.
+	OrderNone SearchOrder = iota
+	// OrderMinX returns the results by the smallest min x coordinate first.
+	OrderMinX
+	// OrderArea returns the results by the smallest area first.
+	OrderArea
+	// OrderCenterDist returns the results by the smallest distance from the
+	// center of the search rect first.
+	OrderCenterDist
+)
+
+func (order SearchOrder) String() string {
+	switch order {
+	case OrderNone:
+		return "none"
+	case OrderMinX:
+		return "min-x"
+	case OrderArea:
+		return "area"
+	case OrderCenterDist:
+		return "center-dist"
+	}
+	return fmt.Sprintf("SearchOrder(%d)", int(order))
+}
+
+// SearchSorted searches the index for items that intersects the rect param,
+// like Search, but the results are returned in the provided order. Items with
+// the same key are returned in the order of the tree's Search.
+func (index *Index) SearchSorted(
+	min, max [2]float64, order SearchOrder,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	defer index.stats.end(opSearch, index.stats.begin())
+	var key func(min, max [2]float64) float64
+	switch order {
+	case OrderNone:
+		index.tree.Search(min, max, iter)
+		return
+	case OrderMinX:
+		key = func(imin, imax [2]float64) float64 {
+			return imin[0]
+		}
+	case OrderArea:
+		key = func(imin, imax [2]float64) float64 {
+			return (imax[0] - imin[0]) * (imax[1] - imin[1])
+		}
+	case OrderCenterDist:
+		center := [2]float64{(min[0] + max[0]) / 2, (min[1] + max[1]) / 2}
+		key = func(imin, imax [2]float64) float64 {
+			return algo.BoxDistCalc(center, center, imin, imax, false)
+		}
+	default:
+		panic("geoindex: invalid search order")
+	}
+	var q queue
+	index.tree.Search(min, max,
+		func(min, max [2]float64, data interface{}) bool {
+			q.push(qnode{
+				dist:  key(min, max),
+				child: child.Child{Min: min, Max: max, Data: data, Item: true},
+			})
+			return true
+		},
+	)
+	for {
+		node, ok := q.pop()
+		if !ok || !iter(node.child.Min, node.child.Max, node.child.Data) {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSearchSorted(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	min, max := [2]float64{-50, -30}, [2]float64{50, 30}
+	var count int
+	index.Search(min, max, func(_, _ [2]float64, _ interface{}) bool {
+		count++
+		return true
+	})
+	center := [2]float64{0, 0}
+	keys := map[SearchOrder]func(min, max [2]float64) float64{
+		OrderMinX: func(min, max [2]float64) float64 { return min[0] },
+		OrderArea: func(min, max [2]float64) float64 {
+			return (max[0] - min[0]) * (max[1] - min[1])
+		},
+		OrderCenterDist: func(min, max [2]float64) float64 {
+			return algo.BoxDistCalc(center, center, min, max, false)
+		},
+	}
+	for order, key := range keys {
+		var n int
+		last := -1e300
+		index.SearchSorted(min, max, order,
+			func(min, max [2]float64, data interface{}) bool {
+				k := key(min, max)
+				if k < last {
+					t.Fatalf("%s: out of order", order)
+				}
+				last = k
+				n++
+				return true
+			},
+		)
+		if n != count {
+			t.Fatalf("%s: expected %d, got %d", order, count, n)
+		}
+	}
+	var n int
+	index.SearchSorted(min, max, OrderNone,
+		func(min, max [2]float64, data interface{}) bool {
+			n++
+			return n < 10
+		},
+	)
+	if n != 10 {
+		t.Fatalf("expected %d, got %d", 10, n)
+	}
+}
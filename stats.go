@@ -0,0 +1,105 @@
+package geoindex
+
+import (
+	"math"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// Stats are the structural statistics of a tree. See Index.Stats.
+type Stats struct {
+	// Height is the number of levels of nodes.
+	Height int
+	// Nodes is the number of nodes, including the leaves.
+	Nodes int
+	// Leaves is the number of nodes that have items as children.
+	Leaves int
+	// Items is the number of items.
+	Items int
+	// Fill is the average number of children per node, divided by the
+	// largest number of children of any node in the tree.
+	Fill float64
+	// Overlap is the sum of the areas where sibling nodes intersect each
+	// other, for all levels.
+	Overlap float64
+	// DeadSpace is the part of the area of the leaves that's not covered by
+	// their items, from zero to one. Items that overlap each other cover the
+	// same space more than once, so this is a lower bound.
+	DeadSpace float64
+	// Imbalance is the largest difference in depth between two leaves,
+	// which is zero for a balanced tree.
+	Imbalance int
+}
+
+// Stats returns the structural statistics of the tree, using the Children of
+// the wrapped tree, to help with diagnosing degenerate trees, such as those
+// produced by pathological insert orders. See Analyze for the metrics of each
+// level, and Fill for the node fill of each level.
+func (index *Index) Stats() Stats {
+	var s Stats
+	var children, maxChildren int
+	var leafArea, itemArea float64
+	minLeaf, maxLeaf := math.MaxInt, -1
+	var overlap func(kids []child.Child)
+	overlap = func(kids []child.Child) {
+		for i, c := range kids {
+			for _, sib := range kids[i+1:] {
+				if !c.Item && !sib.Item {
+					s.Overlap += overlapArea(c.Min, c.Max, sib.Min, sib.Max)
+				}
+			}
+		}
+	}
+	var visit func(node child.Child, depth int)
+	visit = func(node child.Child, depth int) {
+		s.Nodes++
+		if depth+1 > s.Height {
+			s.Height = depth + 1
+		}
+		kids := index.tree.Children(node.Data, nil)
+		children += len(kids)
+		if len(kids) > maxChildren {
+			maxChildren = len(kids)
+		}
+		overlap(kids)
+		leaf := false
+		for _, c := range kids {
+			if c.Item {
+				s.Items++
+				itemArea += boxArea(c)
+				leaf = true
+			} else {
+				visit(c, depth+1)
+			}
+		}
+		if leaf {
+			s.Leaves++
+			leafArea += boxArea(node)
+			if depth < minLeaf {
+				minLeaf = depth
+			}
+			if depth > maxLeaf {
+				maxLeaf = depth
+			}
+		}
+	}
+	roots := index.tree.Children(nil, nil)
+	overlap(roots)
+	for _, c := range roots {
+		if c.Item {
+			s.Items++
+		} else {
+			visit(c, 0)
+		}
+	}
+	if maxLeaf >= 0 {
+		s.Imbalance = maxLeaf - minLeaf
+	}
+	if s.Nodes > 0 && maxChildren > 0 {
+		s.Fill = float64(children) / float64(s.Nodes) / float64(maxChildren)
+	}
+	if leafArea > 0 {
+		s.DeadSpace = math.Max(0, 1-itemArea/leafArea)
+	}
+	return s
+}
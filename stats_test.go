@@ -0,0 +1,56 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestStats(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	if s := index.Stats(); s != (Stats{}) {
+		t.Fatalf("expected empty stats, got %+v", s)
+	}
+	for i, box := range randBoxes(10000) {
+		index.Insert(box.min, box.max, i)
+	}
+	s := index.Stats()
+	if s.Items != 10000 {
+		t.Fatalf("expected %d, got %d", 10000, s.Items)
+	}
+	fill := index.Fill()
+	if s.Height != len(fill) {
+		t.Fatalf("expected %d, got %d", len(fill), s.Height)
+	}
+	var nodes int
+	for _, l := range fill {
+		nodes += l.Nodes
+	}
+	if s.Nodes != nodes {
+		t.Fatalf("expected %d, got %d", nodes, s.Nodes)
+	}
+	if s.Leaves != fill[len(fill)-1].Nodes {
+		t.Fatalf("expected %d, got %d", fill[len(fill)-1].Nodes, s.Leaves)
+	}
+	if s.Imbalance != 0 {
+		t.Fatalf("expected %d, got %d", 0, s.Imbalance)
+	}
+	if s.Fill <= 0 || s.Fill > 1 {
+		t.Fatalf("unexpected fill %v", s.Fill)
+	}
+	if s.DeadSpace < 0 || s.DeadSpace >= 1 {
+		t.Fatalf("unexpected dead space %v", s.DeadSpace)
+	}
+	if s.Overlap <= 0 {
+		t.Fatalf("expected overlap, got %v", s.Overlap)
+	}
+
+	// points leave all of the leaf area as dead space
+	index = Wrap(&internal.RTree{})
+	for i, box := range randPoints(10000) {
+		index.Insert(box.min, box.max, i)
+	}
+	if s := index.Stats(); s.DeadSpace != 1 {
+		t.Fatalf("expected %v, got %v", 1, s.DeadSpace)
+	}
+}
@@ -0,0 +1,150 @@
+package geoindex
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// SVGOptions are the options for Index.SVGOptions.
+type SVGOptions struct {
+	// Scale is the number of SVG units per coordinate unit. Zero scales
+	// the viewport to be 1000 units wide.
+	Scale float64
+	// Viewport is the min,max box of the coordinates that are visible. A
+	// zero Viewport fits the bounds of the tree.
+	Viewport [2][2]float64
+	// NodeColors are the stroke colors of the nodes, by depth, where the
+	// root nodes use the first color. The colors wrap around for trees
+	// that are deeper than the number of colors.
+	NodeColors []string
+	// ItemColor is the stroke color of the items.
+	ItemColor string
+	// StrokeWidth is the width of the strokes in SVG units. Zero uses the
+	// SVG default.
+	StrokeWidth float64
+	// Opacity is the stroke opacity, from zero to one. Zero uses one.
+	Opacity float64
+	// MaxDepth is the number of levels that are drawn, where the root nodes
+	// are at depth zero and items are one level deeper than their node.
+	// Zero draws all levels.
+	MaxDepth int
+	// Filter, when not nil, only draws the nodes and items that intersect
+	// the box.
+	Filter *[2][2]float64
+	// Label, when not nil, returns the text that's drawn at the center of
+	// an item. Items with an empty label are not labeled.
+	Label func(data interface{}) string
+}
+
+// flipY negates a y coordinate for the flipped SVG space, without printing
+// zero as -0.
+func flipY(y float64) float64 {
+	return 0 - y
+}
+
+// DefaultSVGOptions are the options used by Index.SVG, which draws the tree
+// in wgs84 coordinate space.
+var DefaultSVGOptions = SVGOptions{
+	Scale:      5,
+	Viewport:   [2][2]float64{{-190, -90}, {190, 100}},
+	NodeColors: []string{"red", "#009900", "#cccc00", "black", "purple"},
+	ItemColor:  "black",
+}
+
+// SVG prints 2D rtree in wgs84 coordinate space. When the CRS of the index
+// isn't Degrees, the viewport is the Bounds of the CRS instead.
+func (index *Index) SVG() string {
+	opts := DefaultSVGOptions
+	if crs := index.CRS(); crs != Degrees {
+		min, max := crs.Bounds()
+		opts.Viewport = [2][2]float64{min, max}
+		opts.Scale = 0
+	}
+	return index.SVGOptions(opts)
+}
+
+// SVGOptions prints the tree as an SVG using the provided options. Options
+// that have a zero value use the defaults that are described in
+// SVGOptions, and colors that are empty use the DefaultSVGOptions.
+func (index *Index) SVGOptions(opts SVGOptions) string {
+	if opts.Viewport == [2][2]float64{} {
+		min, max := index.tree.Bounds()
+		opts.Viewport = [2][2]float64{min, max}
+	}
+	vmin, vmax := opts.Viewport[0], opts.Viewport[1]
+	if opts.Scale <= 0 {
+		opts.Scale = 1
+		if w := vmax[0] - vmin[0]; w > 0 {
+			opts.Scale = 1000 / w
+		}
+	}
+	if len(opts.NodeColors) == 0 {
+		opts.NodeColors = DefaultSVGOptions.NodeColors
+	}
+	if opts.ItemColor == "" {
+		opts.ItemColor = DefaultSVGOptions.ItemColor
+	}
+	if opts.Opacity <= 0 {
+		opts.Opacity = 1
+	}
+	scale := opts.Scale
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg viewBox=\"%.0f %.0f %.0f %.0f\" "+
+		"xmlns =\"http://www.w3.org/2000/svg\">\n",
+		vmin[0]*scale, flipY(vmax[1]*scale),
+		(vmax[0]-vmin[0])*scale, (vmax[1]-vmin[1])*scale)
+	sb.WriteString("<g transform=\"scale(1,-1)\">\n")
+	var stroke string
+	if opts.StrokeWidth > 0 {
+		stroke = fmt.Sprintf(" stroke-width=\"%g\"", opts.StrokeWidth)
+	}
+	rect := func(c child.Child, color string) {
+		fmt.Fprintf(&sb,
+			"<rect x=\"%.0f\" y=\"%.0f\" width=\"%.0f\" height=\"%.0f\" "+
+				"stroke=\"%s\"%s fill-opacity=\"0\" stroke-opacity=\"%g\"/>\n",
+			(c.Min[0])*scale,
+			(c.Min[1])*scale,
+			(c.Max[0]-c.Min[0]+1/scale)*scale,
+			(c.Max[1]-c.Min[1]+1/scale)*scale,
+			color, stroke, opts.Opacity)
+	}
+	var draw func(children []child.Child, depth int)
+	draw = func(children []child.Child, depth int) {
+		if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+			return
+		}
+		for _, c := range children {
+			if opts.Filter != nil &&
+				!intersects(opts.Filter[0], opts.Filter[1], c.Min, c.Max) {
+				continue
+			}
+			if !c.Item {
+				rect(c, opts.NodeColors[depth%len(opts.NodeColors)])
+				buf := getChildren()
+				*buf = index.tree.Children(c.Data, *buf)
+				draw(*buf, depth+1)
+				putChildren(buf)
+				continue
+			}
+			rect(c, opts.ItemColor)
+			if opts.Label == nil {
+				continue
+			}
+			if label := opts.Label(c.Data); label != "" {
+				// the text is flipped back to be upright
+				fmt.Fprintf(&sb, "<text x=\"%.0f\" y=\"%.0f\" "+
+					"transform=\"scale(1,-1)\">%s</text>\n",
+					(c.Min[0]+c.Max[0])/2*scale,
+					flipY((c.Min[1]+c.Max[1])/2*scale),
+					html.EscapeString(label))
+			}
+		}
+	}
+	draw(index.Children(nil, nil), 0)
+	sb.WriteString("</g>\n")
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
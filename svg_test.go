@@ -0,0 +1,70 @@
+package geoindex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSVGOptions(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i := 0; i < 1000; i++ {
+		// non-wgs84 coordinates
+		p := [2]float64{float64(i%50) * 1000, float64(i/50) * 1000}
+		index.Insert(p, p, i)
+	}
+	svg := index.SVGOptions(SVGOptions{})
+	if !strings.HasPrefix(svg, "<svg viewBox=\"0 -388 1000 388\"") {
+		t.Fatalf("unexpected viewBox %q", svg[:50])
+	}
+	if n := strings.Count(svg, "<rect"); n <= 1000 {
+		t.Fatalf("expected more than %d rects, got %d", 1000, n)
+	}
+	svg = index.SVGOptions(SVGOptions{
+		Viewport:    [2][2]float64{{-1000, -1000}, {50000, 20000}},
+		NodeColors:  []string{"blue"},
+		ItemColor:   "green",
+		StrokeWidth: 2.5,
+		Opacity:     0.5,
+		Label: func(data interface{}) string {
+			if data.(int) == 57 {
+				return "<seven>"
+			}
+			return ""
+		},
+	})
+	for _, s := range []string{
+		"viewBox=\"-20 -392 1000 412\"",
+		"stroke=\"blue\" stroke-width=\"2.5\"",
+		"stroke=\"green\"",
+		"stroke-opacity=\"0.5\"",
+		"<text x=\"137\" y=\"-20\" transform=\"scale(1,-1)\">" +
+			"&lt;seven&gt;</text>",
+	} {
+		if !strings.Contains(svg, s) {
+			t.Fatalf("expected %q", s)
+		}
+	}
+	if n := strings.Count(svg, "<text"); n != 1 {
+		t.Fatalf("expected %d labels, got %d", 1, n)
+	}
+	// only the root nodes
+	svg = index.SVGOptions(SVGOptions{MaxDepth: 1})
+	roots := len(index.Children(nil, nil))
+	if n := strings.Count(svg, "<rect"); n != roots {
+		t.Fatalf("expected %d rects, got %d", roots, n)
+	}
+	// only a few items
+	filter := [2][2]float64{{0, 0}, {1000, 1000}}
+	svg = index.SVGOptions(SVGOptions{Filter: &filter, ItemColor: "green"})
+	if n := strings.Count(svg, "stroke=\"green\""); n != 4 {
+		t.Fatalf("expected %d items, got %d\n%s", 4, n, svg)
+	}
+	if svg := Wrap(&internal.RTree{}).SVGOptions(SVGOptions{}); svg !=
+		"<svg viewBox=\"0 0 0 0\" "+
+			"xmlns =\"http://www.w3.org/2000/svg\">\n"+
+			"<g transform=\"scale(1,-1)\">\n</g>\n</svg>\n" {
+		t.Fatalf("unexpected %q", svg)
+	}
+}
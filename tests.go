@@ -1,15 +1,19 @@
 package geoindex
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"os"
 	"sort"
+	"sync"
 	"testing"
 
 	"github.com/tidwall/cities"
 	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/child"
 	"github.com/tidwall/lotsa"
 )
 
@@ -52,18 +56,45 @@ import (
 // 			})
 // 		}
 //
+// 		func FuzzOperations(f *testing.F) {
+// 			geoindex.Tests.FuzzOperations(f, func() geoindex.Interface {
+// 				return &RTree{}
+// 			})
+// 		}
+//
 // 		func BenchmarkRandomInsert(b *testing.B) {
 // 			geoindex.Tests.BenchmarkRandomInsert(b, &RTree{})
 // 		}
 //
+// 		func BenchmarkSearch(b *testing.B) {
+// 			for _, dataset := range geoindex.BenchmarkDatasets {
+// 				b.Run(dataset, func(b *testing.B) {
+// 					geoindex.Tests.BenchmarkSearch(b, &RTree{}, dataset)
+// 				})
+// 			}
+// 		}
+//
 var Tests = struct {
-	TestBenchVarious      func(t *testing.T, tr Interface, numPointOrRects int)
-	TestRandomPoints      func(t *testing.T, tr Interface, numPoints int)
-	TestRandomRects       func(t *testing.T, tr Interface, numRects int)
-	TestCitiesSVG         func(t *testing.T, tr Interface)
-	TestRandomSVG         func(t *testing.T, tr Interface)
-	TestZeroPoints        func(t *testing.T, tr Interface)
-	BenchmarkRandomInsert func(b *testing.B, tr Interface)
+	TestBenchVarious        func(t *testing.T, tr Interface, numPointOrRects int)
+	TestRandomPoints        func(t *testing.T, tr Interface, numPoints int)
+	TestRandomRects         func(t *testing.T, tr Interface, numRects int)
+	TestCitiesSVG           func(t *testing.T, tr Interface)
+	TestRandomSVG           func(t *testing.T, tr Interface)
+	TestZeroPoints          func(t *testing.T, tr Interface)
+	TestCitiesKNN           func(t *testing.T, tr Interface)
+	TestDatelinePoles       func(t *testing.T, tr Interface)
+	TestConcurrentReadWrite func(t *testing.T, tr Interface, numItems int)
+	TestNearbyCorrectness   func(t *testing.T, tr Interface, numPoints int)
+	TestEdgeOfWorld         func(t *testing.T, tr Interface)
+	FuzzOperations          func(f *testing.F, newTree func() Interface)
+	TestOperations          func(t *testing.T, newTree func() Interface)
+	TestChildrenInvariants  func(t *testing.T, tr Interface, numRects int)
+	TestDuplicates          func(t *testing.T, tr Interface)
+	BenchmarkRandomInsert   func(b *testing.B, tr Interface)
+	BenchmarkSearch         func(b *testing.B, tr Interface, dataset string)
+	BenchmarkNearby         func(b *testing.B, tr Interface, dataset string)
+	BenchmarkDelete         func(b *testing.B, tr Interface, dataset string)
+	BenchmarkMixedWorkload  func(b *testing.B, tr Interface, dataset string)
 }{
 	benchVarious,
 	func(t *testing.T, tr Interface, numRects int) {
@@ -75,7 +106,20 @@ var Tests = struct {
 	testCitiesSVG,
 	testRandomSVG,
 	testZeroPoints,
+	testCitiesKNN,
+	testDatelinePoles,
+	testConcurrentReadWrite,
+	testNearbyCorrectness,
+	testEdgeOfWorld,
+	fuzzOperations,
+	testOperationsSeeds,
+	testChildrenInvariants,
+	testDuplicates,
 	benchmarkRandomInsert,
+	benchmarkSearch,
+	benchmarkNearby,
+	benchmarkDelete,
+	benchmarkMixedWorkload,
 }
 
 type rect struct {
@@ -492,6 +536,632 @@ func testCitiesSVG(t *testing.T, tr Interface) {
 	}
 }
 
+func testCitiesKNN(t *testing.T, tr Interface) {
+	index := Wrap(tr)
+	for _, i := range rand.Perm(len(cities.Cities)) {
+		city := &cities.Cities[i]
+		p := [2]float64{city.Longitude, city.Latitude}
+		index.Insert(p, p, city)
+	}
+	probes := [][2]float64{
+		{-112.07, 33.45}, // Phoenix
+		{2.35, 48.86},    // Paris
+		{151.21, -33.87}, // Sydney
+		{179.9, -16.5},   // near the antimeridian
+		{-179.9, 65.0},   // near the antimeridian
+		{0, 89.9},        // north pole
+		{-70, -89.9},     // south pole
+		{-30, 0},         // Atlantic ocean
+		{103.82, 1.35},   // Singapore
+		{-157.86, 21.31}, // Honolulu
+	}
+	const k = 100
+	for _, probe := range probes {
+		expect := make([]float64, len(cities.Cities))
+		for i, city := range cities.Cities {
+			expect[i] = algo.Haversine(probe,
+				[2]float64{city.Longitude, city.Latitude})
+		}
+		sort.Float64s(expect)
+		var got []float64
+		index.Nearby(algo.Geodesic(probe[0], probe[1]),
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				city := data.(*cities.City)
+				if dist != algo.Haversine(probe,
+					[2]float64{city.Longitude, city.Latitude}) {
+					t.Fatalf("probe %v: wrong distance for %s",
+						probe, city.City)
+				}
+				got = append(got, dist)
+				return len(got) < k
+			},
+		)
+		if len(got) != k {
+			t.Fatalf("probe %v: expected %d, got %d", probe, k, len(got))
+		}
+		for i := range got {
+			if got[i] != expect[i] {
+				t.Fatalf("probe %v: result %d: expected %v, got %v",
+					probe, i, expect[i], got[i])
+			}
+		}
+	}
+}
+
+// testNearbyCorrectness compares the results of Nearby for random targets
+// with a brute-force sort of the distances of all points, using both the
+// planar and spherical distances.
+func testNearbyCorrectness(t *testing.T, tr Interface, numPoints int) {
+	index := Wrap(tr)
+	points := randPoints(numPoints)
+	for i, p := range points {
+		index.Insert(p.min, p.max, i)
+	}
+	for i := 0; i < 20; i++ {
+		target := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		for _, spherical := range []bool{false, true} {
+			dist := func(p [2]float64) float64 {
+				if spherical {
+					return algo.Haversine(target, p)
+				}
+				return algo.BoxDistCalc(target, target, p, p, true)
+			}
+			expect := make([]float64, len(points))
+			for i, p := range points {
+				expect[i] = dist(p.min)
+			}
+			sort.Float64s(expect)
+			var got []float64
+			index.Nearby(algo.Point(target[0], target[1], spherical),
+				func(min, max [2]float64, data interface{}, d float64) bool {
+					if d != dist(min) {
+						t.Fatalf("target %v (spherical=%v): item %v: "+
+							"expected dist %v, got %v",
+							target, spherical, data, dist(min), d)
+					}
+					got = append(got, d)
+					return true
+				},
+			)
+			if len(got) != len(expect) {
+				t.Fatalf("target %v (spherical=%v): expected %d, got %d",
+					target, spherical, len(expect), len(got))
+			}
+			for i := range got {
+				if got[i] != expect[i] {
+					t.Fatalf("target %v (spherical=%v): result %d: "+
+						"expected %v, got %v",
+						target, spherical, i, expect[i], got[i])
+				}
+			}
+		}
+	}
+}
+
+// testEdgeBoxes returns random points and small rects that are near the
+// antimeridian and the poles.
+func testEdgeBoxes(n int) []tBox {
+	boxes := make([]tBox, n)
+	for i := range boxes {
+		var p [2]float64
+		switch i % 3 {
+		case 0:
+			// antimeridian
+			p[0] = 180 - rand.Float64()*5
+			if rand.Int()%2 == 0 {
+				p[0] = -p[0]
+			}
+			p[1] = rand.Float64()*180 - 90
+		case 1:
+			// north pole
+			p[0] = rand.Float64()*360 - 180
+			p[1] = 90 - rand.Float64()*5
+		case 2:
+			// south pole
+			p[0] = rand.Float64()*360 - 180
+			p[1] = -90 + rand.Float64()*5
+		}
+		boxes[i].min = p
+		boxes[i].max = p
+		if i%2 == 0 {
+			boxes[i].max[0] = math.Min(180, p[0]+rand.Float64())
+			boxes[i].max[1] = math.Min(90, p[1]+rand.Float64())
+		}
+	}
+	return boxes
+}
+
+// testWrappedIntersects is the brute-force model for Index.SearchWrapped
+func testWrappedIntersects(min, max, imin, imax [2]float64) bool {
+	if imin[1] > max[1] || imax[1] < min[1] {
+		return false
+	}
+	if min[0] <= max[0] {
+		return !(imin[0] > max[0] || imax[0] < min[0])
+	}
+	return imax[0] >= min[0] || imin[0] <= max[0]
+}
+
+func testDatelinePoles(t *testing.T, tr Interface) {
+	index := Wrap(tr)
+	boxes := testEdgeBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	sortedIDs := func(ids []int) []int {
+		sort.Ints(ids)
+		return ids
+	}
+	// rects that straddle the antimeridian and that touch the poles
+	rects := [][2][2]float64{
+		{{175, -10}, {-175, 10}},
+		{{179.5, -90}, {-179.5, 90}},
+		{{170, 80}, {-170, 90}},
+		{{-180, 85}, {180, 90}},
+		{{-180, -90}, {180, -85}},
+		{{-10, 89}, {10, 90}},
+		{{90, -90}, {100, -89}},
+		{{180, -90}, {180, 90}},
+		{{-180, -90}, {-180, 90}},
+	}
+	for _, rect := range rects {
+		var expect []int
+		for i, box := range boxes {
+			if testWrappedIntersects(rect[0], rect[1], box.min, box.max) {
+				expect = append(expect, i)
+			}
+		}
+		var got []int
+		index.SearchWrapped(rect[0], rect[1],
+			func(min, max [2]float64, data interface{}) bool {
+				got = append(got, data.(int))
+				return true
+			},
+		)
+		expect, got = sortedIDs(expect), sortedIDs(got)
+		if len(got) != len(expect) {
+			t.Fatalf("rect %v: expected %d, got %d",
+				rect, len(expect), len(got))
+		}
+		for i := range got {
+			if got[i] != expect[i] {
+				t.Fatalf("rect %v: expected %d, got %d",
+					rect, expect[i], got[i])
+			}
+		}
+	}
+	// kNN targets on both sides of the antimeridian and near the poles,
+	// using the wrapped box distance and the haversine distance.
+	targets := [][2]float64{
+		{179.99, 0}, {-179.99, 0}, {180, 45}, {-180, -45},
+		{0, 89.99}, {120, -89.99}, {179.9, 89.9}, {-179.9, -89.9},
+	}
+	const k = 50
+	for _, target := range targets {
+		for _, haversine := range []bool{false, true} {
+			dist := func(min, max [2]float64) float64 {
+				if haversine {
+					return algo.HaversineBox(target, min, max)
+				}
+				return algo.BoxDistCalc(target, target, min, max, true)
+			}
+			expect := make([]float64, len(boxes))
+			for i, box := range boxes {
+				expect[i] = dist(box.min, box.max)
+			}
+			sort.Float64s(expect)
+			var got []float64
+			index.Nearby(
+				func(min, max [2]float64, data interface{}, item bool,
+				) float64 {
+					return dist(min, max)
+				},
+				func(min, max [2]float64, data interface{}, d float64) bool {
+					got = append(got, d)
+					return len(got) < k
+				},
+			)
+			if len(got) != k {
+				t.Fatalf("target %v: expected %d, got %d",
+					target, k, len(got))
+			}
+			for i := range got {
+				if got[i] != expect[i] {
+					t.Fatalf("target %v (haversine=%v): result %d: "+
+						"expected %v, got %v",
+						target, haversine, i, expect[i], got[i])
+				}
+			}
+		}
+	}
+}
+
+// testEdgeBoxesExact returns points and rects that are exactly on the
+// antimeridian and the poles, and at the corners of the world. The box that
+// crosses the antimeridian is the last two boxes.
+func testEdgeBoxesExact() []tBox {
+	return []tBox{
+		{[2]float64{180, 0}, [2]float64{180, 0}},
+		{[2]float64{-180, 0}, [2]float64{-180, 0}},
+		{[2]float64{0, 90}, [2]float64{0, 90}},
+		{[2]float64{0, -90}, [2]float64{0, -90}},
+		{[2]float64{180, 90}, [2]float64{180, 90}},
+		{[2]float64{-180, 90}, [2]float64{-180, 90}},
+		{[2]float64{180, -90}, [2]float64{180, -90}},
+		{[2]float64{-180, -90}, [2]float64{-180, -90}},
+		{[2]float64{-180, -90}, [2]float64{180, 90}},
+		{[2]float64{170, -10}, [2]float64{180, 10}},
+		{[2]float64{-180, -10}, [2]float64{-170, 10}},
+		{[2]float64{-180, 85}, [2]float64{180, 90}},
+		{[2]float64{-180, -90}, [2]float64{180, -85}},
+		{[2]float64{175, 20}, [2]float64{180, 30}},
+		{[2]float64{-180, 20}, [2]float64{-175, 30}},
+	}
+}
+
+func testEdgeOfWorld(t *testing.T, tr Interface) {
+	index := Wrap(tr)
+	exact := testEdgeBoxesExact()
+	boxes := append(append([]tBox(nil), exact...), testEdgeBoxes(1000)...)
+	// the two halves of the crossing box have the same data
+	data := func(i int) int {
+		if i == len(exact)-1 {
+			return i - 1
+		}
+		return i
+	}
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, data(i))
+	}
+	deleted := make(map[int]bool)
+	rects := [][2][2]float64{
+		{{180, -90}, {180, 90}},
+		{{-180, -90}, {-180, 90}},
+		{{-180, 90}, {180, 90}},
+		{{-180, -90}, {180, -90}},
+		{{180, 90}, {180, 90}},
+		{{-180, -90}, {-180, -90}},
+		{{-180, -90}, {180, 90}},
+		{{179, 25}, {180, 25}},
+	}
+	checkSearch := func() {
+		for _, rect := range rects {
+			expect := make(map[int]int)
+			for i, box := range boxes {
+				if !deleted[i] && intersects(rect[0], rect[1], box.min,
+					box.max) {
+					expect[data(i)]++
+				}
+			}
+			got := make(map[int]int)
+			index.Search(rect[0], rect[1],
+				func(min, max [2]float64, data interface{}) bool {
+					got[data.(int)]++
+					return true
+				},
+			)
+			if len(got) != len(expect) {
+				t.Fatalf("rect %v: expected %d, got %d",
+					rect, len(expect), len(got))
+			}
+			for id, n := range expect {
+				if got[id] != n {
+					t.Fatalf("rect %v: item %d: expected %d, got %d",
+						rect, id, n, got[id])
+				}
+			}
+		}
+	}
+	checkNearby := func() {
+		targets := [][2]float64{
+			{180, 0}, {-180, 0}, {180, 90}, {-180, -90}, {0, 90}, {-180, 25},
+		}
+		const k = 30
+		for _, target := range targets {
+			var expect []float64
+			for i, box := range boxes {
+				if !deleted[i] {
+					expect = append(expect, algo.BoxDistCalc(target, target,
+						box.min, box.max, true))
+				}
+			}
+			sort.Float64s(expect)
+			var got []float64
+			index.Nearby(algo.Box(target, target, true, nil),
+				func(min, max [2]float64, data interface{}, d float64) bool {
+					got = append(got, d)
+					return len(got) < k
+				},
+			)
+			if len(got) != k {
+				t.Fatalf("target %v: expected %d, got %d", target, k, len(got))
+			}
+			for i := range got {
+				if got[i] != expect[i] {
+					t.Fatalf("target %v: result %d: expected %v, got %v",
+						target, i, expect[i], got[i])
+				}
+			}
+		}
+	}
+	checkSearch()
+	checkNearby()
+	// on the other side of the antimeridian is a dist of zero
+	var sides int
+	index.Nearby(algo.Box([2]float64{-180, 0}, [2]float64{-180, 0}, true, nil),
+		func(min, max [2]float64, data interface{}, d float64) bool {
+			if d == 0 && (data == 0 || data == 1) {
+				sides++
+			}
+			return d == 0
+		},
+	)
+	if sides != 2 {
+		t.Fatalf("expected %d, got %d", 2, sides)
+	}
+	// delete the exact boxes, one at a time
+	for i := range exact {
+		index.Delete(boxes[i].min, boxes[i].max, data(i))
+		deleted[i] = true
+		if index.Len() != len(boxes)-len(deleted) {
+			t.Fatalf("expected %d, got %d", len(boxes)-len(deleted),
+				index.Len())
+		}
+		checkSearch()
+	}
+	checkNearby()
+}
+
+// operationsSeeds is the seed corpus of FuzzOperations
+var operationsSeeds = [][]byte{
+	{0, 1, 2, 3, 0, 4, 5, 6, 7, 3, 0, 0, 40, 40},
+	{0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 1, 0, 2, 7, 7, 7, 3, 0, 0, 63},
+	bytes.Repeat([]byte{0, 9, 17, 1, 2, 2, 5, 9, 3, 3, 3, 3, 0, 4, 1}, 40),
+}
+
+func fuzzOperations(f *testing.F, newTree func() Interface) {
+	for _, ops := range operationsSeeds {
+		f.Add(ops)
+	}
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		testOperations(t, newTree(), ops)
+	})
+}
+
+// testOperationsSeeds runs the seed corpus of FuzzOperations, for when
+// fuzzing isn't available, such as in geoindextest.Conformance.
+func testOperationsSeeds(t *testing.T, newTree func() Interface) {
+	for i, ops := range operationsSeeds {
+		t.Run(fmt.Sprintf("seed%d", i), func(t *testing.T) {
+			testOperations(t, newTree(), ops)
+		})
+	}
+}
+
+// testOperations runs the operations against the tree and against a model
+// of the live items, and checks that they have the same items.
+func testOperations(t *testing.T, tr Interface, ops []byte) {
+	// next returns the next byte of the operations, or zero when there
+	// are no more bytes
+	next := func() byte {
+		if len(ops) == 0 {
+			return 0
+		}
+		b := ops[0]
+		ops = ops[1:]
+		return b
+	}
+	// Items are placed on a small integer grid so that many of them
+	// share edges with each other and with their nodes.
+	newBox := func() tBox {
+		var box tBox
+		box.min = [2]float64{float64(next() % 64), float64(next() % 64)}
+		box.max = box.min
+		if size := next(); size%2 == 0 {
+			box.max[0] += float64(size / 2 % 4)
+			box.max[1] += float64(size / 8 % 4)
+		}
+		return box
+	}
+	index := Wrap(tr)
+	// the model is a slice of the live items
+	var items []Item
+	var nextID int
+	for i := 0; len(ops) > 0; i++ {
+		switch next() % 4 {
+		case 0:
+			box := newBox()
+			index.Insert(box.min, box.max, nextID)
+			items = append(items, Item{box.min, box.max, nextID})
+			nextID++
+		case 1:
+			if len(items) == 0 {
+				continue
+			}
+			j := int(next()) % len(items)
+			index.Delete(items[j].Min, items[j].Max, items[j].Data)
+			items[j] = items[len(items)-1]
+			items = items[:len(items)-1]
+		case 2:
+			if len(items) == 0 {
+				continue
+			}
+			j := int(next()) % len(items)
+			box := newBox()
+			index.Replace(items[j].Min, items[j].Max, items[j].Data,
+				box.min, box.max, items[j].Data)
+			items[j].Min, items[j].Max = box.min, box.max
+		case 3:
+			rect := newBox()
+			rect.max[0] += float64(next() % 16)
+			rect.max[1] += float64(next() % 16)
+			expect := make(map[interface{}]Item)
+			for _, item := range items {
+				if intersects(rect.min, rect.max, item.Min, item.Max) {
+					expect[item.Data] = item
+				}
+			}
+			got := make(map[interface{}]Item)
+			index.Search(rect.min, rect.max,
+				func(min, max [2]float64, data interface{}) bool {
+					if _, ok := got[data]; ok {
+						t.Fatalf("op %d: duplicate item %v", i, data)
+					}
+					got[data] = Item{min, max, data}
+					return true
+				},
+			)
+			if len(got) != len(expect) {
+				t.Fatalf("op %d: rect %v: expected %d, got %d",
+					i, rect, len(expect), len(got))
+			}
+			for data, item := range expect {
+				if got[data] != item {
+					t.Fatalf("op %d: rect %v: expected %v, got %v",
+						i, rect, item, got[data])
+				}
+			}
+		}
+		if index.Len() != len(items) {
+			t.Fatalf("op %d: expected %d items, got %d",
+				i, len(items), index.Len())
+		}
+	}
+	expect := make(map[interface{}]Item)
+	for _, item := range items {
+		expect[item.Data] = item
+	}
+	index.Scan(func(min, max [2]float64, data interface{}) bool {
+		if item, ok := expect[data]; !ok || item != (Item{min, max, data}) {
+			t.Fatalf("unexpected item %v", data)
+		}
+		delete(expect, data)
+		return true
+	})
+	if len(expect) != 0 {
+		t.Fatalf("expected %d more items", len(expect))
+	}
+}
+
+func testChildrenInvariants(t *testing.T, tr Interface, numRects int) {
+	index := Wrap(tr)
+	boxes := randBoxes(numRects)
+	check := func() {
+		t.Helper()
+		// the boxes of the children are in their parents, and the items are
+		// the same as from Scan
+		if err := index.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		leafDepth := -1
+		var count int
+		var walk func(parent interface{}, depth int)
+		walk = func(parent interface{}, depth int) {
+			// the reuse buffer is an empty length slice, which may or may
+			// not be used
+			reuse := make([]child.Child, 0, 4)
+			children := tr.Children(parent, reuse)
+			if len(children) == 0 && (parent != nil || tr.Len() > 0) {
+				t.Fatalf("depth %d: node without children", depth)
+			}
+			for _, c := range children {
+				if c.Item != children[0].Item {
+					t.Fatalf("depth %d: items and nodes in the same node",
+						depth)
+				}
+				if !c.Item {
+					walk(c.Data, depth+1)
+					continue
+				}
+				if leafDepth == -1 {
+					leafDepth = depth
+				} else if depth != leafDepth {
+					t.Fatalf("items at depth %d and %d", leafDepth, depth)
+				}
+				count++
+			}
+		}
+		walk(nil, 0)
+		if count != tr.Len() {
+			t.Fatalf("expected %d items, got %d", tr.Len(), count)
+		}
+	}
+	check()
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	check()
+	// deleting many items merges and reinserts the nodes
+	for i := 0; i < len(boxes); i += 2 {
+		index.Delete(boxes[i].min, boxes[i].max, i)
+	}
+	check()
+	for i := 1; i < len(boxes); i += 2 {
+		index.Delete(boxes[i].min, boxes[i].max, i)
+	}
+	check()
+}
+
+func testDuplicates(t *testing.T, tr Interface) {
+	index := Wrap(tr)
+	count := func(min, max [2]float64, data interface{}) int {
+		var n int
+		index.Search(min, max, func(_, _ [2]float64, d interface{}) bool {
+			if d == data {
+				n++
+			}
+			return true
+		})
+		return n
+	}
+	// the same box with the same data, many times, which is enough for the
+	// duplicates to be split across many nodes
+	p := [2]float64{10, 20}
+	const n = 1000
+	for i := 0; i < n; i++ {
+		index.Insert(p, p, "dup")
+	}
+	if index.Len() != n || count(p, p, "dup") != n {
+		t.Fatalf("expected %d, got %d", n, count(p, p, "dup"))
+	}
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// the same box with different data
+	for i := 0; i < 100; i++ {
+		index.Insert(p, p, i)
+	}
+	if !index.Delete(p, p, 50) || count(p, p, 50) != 0 ||
+		count(p, p, 49) != 1 || count(p, p, "dup") != n {
+		t.Fatal("expected only item 50 to be deleted")
+	}
+	if index.Delete(p, p, 50) {
+		t.Fatal("expected false for a deleted item")
+	}
+	if index.Delete(p, p, "missing") || index.Delete([2]float64{1, 1},
+		[2]float64{1, 1}, "dup") {
+		t.Fatal("expected false for a missing item")
+	}
+	// delete one of many at a time
+	for i := n; i > 0; i-- {
+		if !index.Delete(p, p, "dup") {
+			t.Fatalf("expected true with %d items", i)
+		}
+		if c := count(p, p, "dup"); c != i-1 {
+			t.Fatalf("expected %d, got %d", i-1, c)
+		}
+		if index.Len() != i-1+99 {
+			t.Fatalf("expected %d, got %d", i-1+99, index.Len())
+		}
+	}
+	if index.Delete(p, p, "dup") {
+		t.Fatal("expected false with no more items")
+	}
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func testRandomSVG(t *testing.T, tr Interface) {
 	index := Wrap(tr)
 	for i := 0; i < 10_000; i++ {
@@ -504,6 +1174,76 @@ func testRandomSVG(t *testing.T, tr Interface) {
 	}
 }
 
+// testConcurrentReadWrite uses a SafeIndex to search and scan the tree from
+// many goroutines while another goroutine inserts and deletes items.
+func testConcurrentReadWrite(t *testing.T, tr Interface, numItems int) {
+	index := SafeWrap(tr)
+	boxes := randBoxes(numItems)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i, box := range boxes {
+			index.Insert(box.min, box.max, i)
+			if i%3 == 0 {
+				// delete an earlier item
+				j := i / 3
+				index.Delete(boxes[j].min, boxes[j].max, j)
+			}
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for i := r; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				box := boxes[i%len(boxes)]
+				index.Search(box.min, box.max,
+					func(min, max [2]float64, data interface{}) bool {
+						if !intersects(min, max, box.min, box.max) {
+							t.Errorf("%v %v does not intersect %v %v",
+								min, max, box.min, box.max)
+						}
+						return true
+					},
+				)
+				if i%10 == 0 {
+					var n int
+					index.Scan(func(min, max [2]float64,
+						data interface{}) bool {
+						n++
+						return true
+					})
+					if n > len(boxes) {
+						t.Errorf("expected at most %d, got %d", len(boxes), n)
+					}
+				}
+				index.Len()
+			}
+		}(r)
+	}
+	wg.Wait()
+	expect := len(boxes) - (len(boxes)-1)/3 - 1
+	if len(boxes) == 0 {
+		expect = 0
+	}
+	var n int
+	index.Scan(func(min, max [2]float64, data interface{}) bool {
+		n++
+		return true
+	})
+	if n != expect || index.Len() != expect {
+		t.Fatalf("expected %d, got %d (len %d)", expect, n, index.Len())
+	}
+}
+
 type tBox struct {
 	min [2]float64
 	max [2]float64
@@ -522,3 +1262,124 @@ func randBoxes(N int) []tBox {
 	}
 	return boxes
 }
+
+// BenchmarkDatasets are the names of the datasets for the Tests benchmarks.
+// The "uniform" dataset has points that are uniformly distributed around the
+// world, the "clustered" dataset has points in dense clusters, the "roads"
+// dataset has the small boxes of winding road segments, and the "cities"
+// dataset has the locations of real world cities.
+var BenchmarkDatasets = []string{"uniform", "clustered", "roads", "cities"}
+
+// benchSize is the number of items that the benchmarks load before timing
+const benchSize = 10000
+
+// benchDataset returns n items of the named dataset
+func benchDataset(dataset string, n int) []Item {
+	switch dataset {
+	case "uniform":
+		return GenUniform(n, 0)
+	case "clustered":
+		return GenClustered(n, 50, 2)
+	case "roads":
+		return GenRoadsLike(n)
+	case "cities":
+		return GenCities(n)
+	}
+	panic("geoindex: unknown benchmark dataset: " + dataset)
+}
+
+// benchLoad inserts the items into the tree
+func benchLoad(tr Interface, items []Item) {
+	for _, item := range items {
+		tr.Insert(item.Min, item.Max, item.Data)
+	}
+}
+
+func benchmarkSearch(b *testing.B, tr Interface, dataset string) {
+	items := benchDataset(dataset, benchSize)
+	benchLoad(tr, items)
+	// windows of about a degree around the items, so that the number of
+	// results follows the density of the dataset
+	targets := benchDataset(dataset, b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := targets[i].Min
+		tr.Search([2]float64{p[0] - 0.5, p[1] - 0.5},
+			[2]float64{p[0] + 0.5, p[1] + 0.5},
+			func(min, max [2]float64, data interface{}) bool {
+				return true
+			},
+		)
+	}
+}
+
+func benchmarkNearby(b *testing.B, tr Interface, dataset string) {
+	items := benchDataset(dataset, benchSize)
+	benchLoad(tr, items)
+	index := Wrap(tr)
+	targets := benchDataset(dataset, b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := targets[i].Min
+		var count int
+		index.Nearby(algo.Box(p, p, false, nil),
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				count++
+				return count < 10
+			},
+		)
+	}
+}
+
+func benchmarkDelete(b *testing.B, tr Interface, dataset string) {
+	items := benchDataset(dataset, b.N)
+	benchLoad(tr, items)
+	b.ResetTimer()
+	for _, i := range rand.Perm(b.N) {
+		tr.Delete(items[i].Min, items[i].Max, i)
+	}
+}
+
+// benchmarkMixedWorkload times a mix of searches, kNN queries, inserts, and
+// deletes, in the ratio of 50:30:10:10.
+func benchmarkMixedWorkload(b *testing.B, tr Interface, dataset string) {
+	items := benchDataset(dataset, benchSize+b.N)
+	benchLoad(tr, items[:benchSize])
+	index := Wrap(tr)
+	targets := benchDataset(dataset, b.N)
+	ops := make([]int, b.N)
+	for i := range ops {
+		ops[i] = rand.Intn(10)
+	}
+	next := benchSize // the next item to insert
+	var deleted int   // the number of items that were deleted
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := targets[i].Min
+		switch {
+		case ops[i] < 5:
+			tr.Search([2]float64{p[0] - 0.5, p[1] - 0.5},
+				[2]float64{p[0] + 0.5, p[1] + 0.5},
+				func(min, max [2]float64, data interface{}) bool {
+					return true
+				},
+			)
+		case ops[i] < 8:
+			var count int
+			index.Nearby(algo.Box(p, p, false, nil),
+				func(min, max [2]float64, data interface{}, dist float64) bool {
+					count++
+					return count < 10
+				},
+			)
+		case ops[i] < 9:
+			tr.Insert(items[next].Min, items[next].Max, next)
+			next++
+		default:
+			if deleted < next {
+				tr.Delete(items[deleted].Min, items[deleted].Max, deleted)
+				deleted++
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package geoindex
+
+import "math"
+
+// tileSize is the number of pixels of the width and height of a tile
+const tileSize = 256
+
+// TileBounds returns the lon,lat bounds of the z,x,y tile of the Web Mercator
+// tiling scheme that's used by web maps and tile servers, where the zoom z
+// has 2^z by 2^z tiles and the tile 0,0 is at the top left. The bounds are
+// extended by buffer pixels of a 256 pixel tile on each side, which is
+// usually a few pixels, so that features near the edge of a tile are drawn
+// without seams. A buffer across the antimeridian returns a min longitude
+// that's greater than the max longitude, which is the form for
+// SearchWrapped. The latitudes are limited to the ±85.0511 degrees of Web
+// Mercator. Returns false when the tile doesn't exist.
+func TileBounds(z, x, y int, buffer float64) (min, max [2]float64, ok bool) {
+	if z < 0 || z > 30 {
+		return min, max, false
+	}
+	n := float64(int(1) << uint(z))
+	if x < 0 || y < 0 || float64(x) >= n || float64(y) >= n {
+		return min, max, false
+	}
+	b := buffer / tileSize
+	lon := func(x float64) float64 {
+		return x/n*360 - 180
+	}
+	lat := func(y float64) float64 {
+		y = math.Max(0, math.Min(n, y))
+		return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+	}
+	min = [2]float64{lon(float64(x) - b), lat(float64(y+1) + b)}
+	max = [2]float64{lon(float64(x+1) + b), lat(float64(y) - b)}
+	switch {
+	case max[0]-min[0] >= 360:
+		min[0], max[0] = -180, 180
+	case min[0] < -180:
+		min[0] += 360
+	case max[0] > 180:
+		max[0] -= 360
+	}
+	return min, max, true
+}
+
+// SearchTile searches the index for items that intersect the z,x,y tile,
+// such as for a tile server that renders the items of each tile. The tile
+// bounds are extended by buffer pixels. See TileBounds for the tiling scheme.
+// Nothing is returned for a tile that doesn't exist.
+func (index *Index) SearchTile(
+	z, x, y int, buffer float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	min, max, ok := TileBounds(z, x, y, buffer)
+	if !ok {
+		return
+	}
+	index.SearchWrapped(min, max, iter)
+}
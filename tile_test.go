@@ -0,0 +1,96 @@
+package geoindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestTileBounds(t *testing.T) {
+	const maxLat = 85.0511287798066
+	near := func(a, b [2]float64) bool {
+		return math.Abs(a[0]-b[0]) < 1e-9 && math.Abs(a[1]-b[1]) < 1e-9
+	}
+	for _, tt := range []struct {
+		z, x, y  int
+		buffer   float64
+		min, max [2]float64
+	}{
+		{0, 0, 0, 0, [2]float64{-180, -maxLat}, [2]float64{180, maxLat}},
+		{0, 0, 0, 64, [2]float64{-180, -maxLat}, [2]float64{180, maxLat}},
+		{1, 1, 0, 0, [2]float64{0, 0}, [2]float64{180, maxLat}},
+		{1, 0, 1, 0, [2]float64{-180, -maxLat}, [2]float64{0, 0}},
+		// a buffer of a quarter tile, which crosses the antimeridian
+		{1, 1, 1, 64, [2]float64{-45, -maxLat},
+			[2]float64{-135, math.Atan(math.Sinh(math.Pi/4)) * 180 / math.Pi}},
+	} {
+		min, max, ok := TileBounds(tt.z, tt.x, tt.y, tt.buffer)
+		if !ok {
+			t.Fatalf("%d/%d/%d: expected ok", tt.z, tt.x, tt.y)
+		}
+		if !near(min, tt.min) || !near(max, tt.max) {
+			t.Fatalf("%d/%d/%d: expected %v %v, got %v %v",
+				tt.z, tt.x, tt.y, tt.min, tt.max, min, max)
+		}
+	}
+	for _, tile := range [][3]int{{-1, 0, 0}, {31, 0, 0}, {2, 4, 0},
+		{2, 0, 4}, {2, -1, 0}} {
+		if _, _, ok := TileBounds(tile[0], tile[1], tile[2], 0); ok {
+			t.Fatalf("%v: expected not ok", tile)
+		}
+	}
+}
+
+func TestSearchTile(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randPoints(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	// every point in the Web Mercator range is in exactly one tile
+	const z = 3
+	count := make(map[interface{}]int)
+	for x := 0; x < 1<<z; x++ {
+		for y := 0; y < 1<<z; y++ {
+			min, max, _ := TileBounds(z, x, y, 0)
+			index.SearchTile(z, x, y, 0,
+				func(pmin, pmax [2]float64, data interface{}) bool {
+					if !intersects(min, max, pmin, pmax) {
+						t.Fatalf("%v is outside of %v %v", pmin, min, max)
+					}
+					// ignore the points on the shared edges
+					if pmin[0] != min[0] && pmin[1] != min[1] {
+						count[data]++
+					}
+					return true
+				},
+			)
+		}
+	}
+	for i, box := range boxes {
+		if math.Abs(box.min[1]) < 85 && count[i] != 1 {
+			t.Fatalf("%v: expected %d, got %d", box.min, 1, count[i])
+		}
+	}
+	// a buffered tile at the antimeridian finds the points on both sides
+	index = Wrap(&internal.RTree{})
+	index.Insert([2]float64{179, 1}, [2]float64{179, 1}, "east")
+	index.Insert([2]float64{-179, 1}, [2]float64{-179, 1}, "west")
+	var found []interface{}
+	index.SearchTile(4, 0, 7, 64,
+		func(min, max [2]float64, data interface{}) bool {
+			found = append(found, data)
+			return true
+		},
+	)
+	if len(found) != 2 {
+		t.Fatalf("expected %d, got %v", 2, found)
+	}
+	index.SearchTile(4, 16, 0, 0,
+		func(min, max [2]float64, data interface{}) bool {
+			t.Fatal("unexpected item")
+			return false
+		},
+	)
+}
@@ -0,0 +1,80 @@
+package geoindex
+
+import "github.com/tidwall/geoindex/child"
+
+// NearbyTracker performs repeated kNN searches for a target that moves, such
+// as the location of a user that's being followed on a map. Rather than
+// starting each search from the root, the tracker keeps the nodes and items
+// that were queued during the previous search and rescores them for the new
+// target. When the target has only moved slightly this saves most of the
+// traversal.
+//
+// The tracker always uses the Children of the wrapped tree. It must be Reset
+// after the index has been modified.
+type NearbyTracker struct {
+	index   *Index
+	q       queue
+	started bool
+	popped  []child.Child
+}
+
+// NearbyTracker returns a new tracker for the index.
+func (index *Index) NearbyTracker() *NearbyTracker {
+	return &NearbyTracker{index: index}
+}
+
+// Reset discards the queued nodes, causing the next search to start from the
+// root.
+func (tr *NearbyTracker) Reset() {
+	tr.q.Reset()
+	tr.popped = tr.popped[:0]
+	tr.started = false
+}
+
+// Nearby performs a kNN-type operation on the index for the current position
+// of the target, which is represented by the algo function. See Index.Nearby.
+func (tr *NearbyTracker) Nearby(
+	algo func(min, max [2]float64, data interface{}, item bool) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	var children []child.Child
+	if !tr.started {
+		children = tr.index.tree.Children(nil, nil)
+		tr.started = true
+	} else {
+		// The items that were returned by the previous search are put back
+		// so that the queue covers the whole tree again.
+		children = tr.popped
+		tr.q.Rescore(func(node qnode, dist float64) float64 {
+			return algo(node.child.Min, node.child.Max, node.child.Data,
+				node.child.Item)
+		})
+	}
+	tr.popped = nil
+	for {
+		for _, child := range children {
+			tr.q.push(qnode{
+				dist:  algo(child.Min, child.Max, child.Data, child.Item),
+				child: child,
+			})
+		}
+		children = children[:0]
+		for len(children) == 0 {
+			node, ok := tr.q.pop()
+			if !ok {
+				// nothing left in queue
+				return
+			}
+			if node.child.Item {
+				tr.popped = append(tr.popped, node.child)
+				if !iter(node.child.Min, node.child.Max, node.child.Data,
+					node.dist) {
+					return
+				}
+			} else {
+				// gather more children
+				children = tr.index.tree.Children(node.child.Data, children)
+			}
+		}
+	}
+}
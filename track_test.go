@@ -0,0 +1,57 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestNearbyTracker(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := randBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	knn := func(nearby func(
+		algo func(min, max [2]float64, data interface{}, item bool) float64,
+		iter func(min, max [2]float64, data interface{}, dist float64) bool,
+	), target [2]float64, k int) []float64 {
+		var dists []float64
+		nearby(algo.Box(target, target, false, nil),
+			func(min, max [2]float64, data interface{}, dist float64) bool {
+				dists = append(dists, dist)
+				return len(dists) < k
+			},
+		)
+		return dists
+	}
+	tr := index.NearbyTracker()
+	target := [2]float64{-50, 20}
+	for i := 0; i < 100; i++ {
+		k := 1 + i%20
+		if i == 50 {
+			tr.Reset()
+		}
+		expect := knn(index.Nearby, target, k)
+		got := knn(tr.Nearby, target, k)
+		if len(got) != len(expect) {
+			t.Fatalf("step %d: expected %d, got %d", i, len(expect), len(got))
+		}
+		for j := range got {
+			if got[j] != expect[j] {
+				t.Fatalf("step %d: result %d: expected %v, got %v",
+					i, j, expect[j], got[j])
+			}
+		}
+		target[0] += 0.5
+		target[1] -= 0.25
+	}
+	// everything
+	if n := len(knn(tr.Nearby, target, len(boxes)+1)); n != len(boxes) {
+		t.Fatalf("expected %d, got %d", len(boxes), n)
+	}
+	if n := len(knn(tr.Nearby, target, len(boxes)+1)); n != len(boxes) {
+		t.Fatalf("expected %d, got %d", len(boxes), n)
+	}
+}
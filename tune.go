@@ -0,0 +1,67 @@
+package geoindex
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TuneConfig is a configuration of a tree for Tune. The meaning of the
+// Policy, such as a split policy, is up to the tree.
+type TuneConfig struct {
+	NodeSize int
+	Policy   string
+}
+
+func (c TuneConfig) String() string {
+	if c.Policy == "" {
+		return fmt.Sprintf("nodesize=%d", c.NodeSize)
+	}
+	return fmt.Sprintf("nodesize=%d policy=%s", c.NodeSize, c.Policy)
+}
+
+// TuneResult is the measurement of one configuration.
+type TuneResult struct {
+	Config TuneConfig
+	// Load is the time that it took to create and load the tree.
+	Load time.Duration
+	// Query is the average time of one run of the workload.
+	Query time.Duration
+}
+
+func (r TuneResult) String() string {
+	return fmt.Sprintf("%s: load %s, query %s", r.Config, r.Load, r.Query)
+}
+
+// Tune measures the query latency of a tree for each of the configurations,
+// using a sample of real data and a workload. The load function must create a
+// new tree for the config and load it with the items, and the workload is run
+// against the loaded tree the provided number of times. Returns the results
+// ordered by the fastest query time first, so the best configuration is the
+// first result.
+func Tune(items []Item, configs []TuneConfig,
+	load func(config TuneConfig, items []Item) Interface,
+	workload func(index *Index), runs int,
+) []TuneResult {
+	if runs < 1 {
+		runs = 1
+	}
+	results := make([]TuneResult, 0, len(configs))
+	for _, config := range configs {
+		start := time.Now()
+		index := Wrap(load(config, items))
+		r := TuneResult{Config: config, Load: time.Since(start)}
+		// warm up
+		workload(index)
+		start = time.Now()
+		for i := 0; i < runs; i++ {
+			workload(index)
+		}
+		r.Query = time.Since(start) / time.Duration(runs)
+		results = append(results, r)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Query < results[j].Query
+	})
+	return results
+}
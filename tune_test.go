@@ -0,0 +1,74 @@
+package geoindex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestTune(t *testing.T) {
+	var items []Item
+	for i, box := range randPoints(10000) {
+		items = append(items, Item{box.min, box.max, i})
+	}
+	configs := []TuneConfig{{NodeSize: 4}, {NodeSize: 64}, {NodeSize: 1024},
+		{Policy: "rtree"}}
+	loaded := make(map[TuneConfig]bool)
+	load := func(config TuneConfig, items []Item) Interface {
+		loaded[config] = true
+		if config.Policy == "rtree" {
+			tr := &internal.RTree{}
+			for _, item := range items {
+				tr.Insert(item.Min, item.Max, item.Data)
+			}
+			return tr
+		}
+		points := make([][2]float64, len(items))
+		for i, item := range items {
+			points[i] = item.Min
+		}
+		return BuildKDBush(points, config.NodeSize, nil)
+	}
+	var runs int
+	workload := func(index *Index) {
+		runs++
+		for i := 0; i < 10; i++ {
+			min := [2]float64{float64(i*30 - 150), float64(i*10 - 50)}
+			max := [2]float64{min[0] + 10, min[1] + 10}
+			index.Search(min, max,
+				func(min, max [2]float64, data interface{}) bool {
+					return true
+				},
+			)
+			var n int
+			index.Nearby(algo.Box(min, min, false, nil),
+				func(min, max [2]float64, data interface{}, dist float64,
+				) bool {
+					n++
+					return n < 10
+				},
+			)
+		}
+	}
+	results := Tune(items, configs, load, workload, 5)
+	if len(results) != len(configs) || len(loaded) != len(configs) {
+		t.Fatalf("expected %d, got %d", len(configs), len(results))
+	}
+	if runs != len(configs)*6 {
+		t.Fatalf("expected %d, got %d", len(configs)*6, runs)
+	}
+	for i, r := range results {
+		if r.Query < 0 || r.Load < 0 {
+			t.Fatalf("unexpected result %s", r)
+		}
+		if i > 0 && r.Query < results[i-1].Query {
+			t.Fatal("results out of order")
+		}
+	}
+	if s := (TuneResult{Config: TuneConfig{NodeSize: 4, Policy: "x"}}).
+		String(); !strings.HasPrefix(s, "nodesize=4 policy=x:") {
+		t.Fatalf("unexpected %q", s)
+	}
+}
@@ -0,0 +1,29 @@
+package geoindex
+
+// Upsert replaces the first item that intersects the rect param and for
+// which the key function returns true with a new item that has the rect and
+// the data, or inserts the new item when there's no such item. Returns true
+// when an item was replaced. This is the "update this ID" pattern, such as
+// for a tracking server that receives a new position for a vehicle whose
+// last known position is within the rect.
+func (index *Index) Upsert(
+	min, max [2]float64,
+	key func(data interface{}) bool,
+	data interface{},
+) (replaced bool) {
+	var old Item
+	var found bool
+	index.Search(min, max, func(omin, omax [2]float64, odata interface{}) bool {
+		if key(odata) {
+			old, found = Item{omin, omax, odata}, true
+			return false
+		}
+		return true
+	})
+	if !found {
+		index.Insert(min, max, data)
+		return false
+	}
+	index.Replace(old.Min, old.Max, old.Data, min, max, data)
+	return true
+}
@@ -0,0 +1,50 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+type upsertVehicle struct {
+	id  int
+	pos [2]float64
+}
+
+func TestUpsert(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	byID := func(id int) func(data interface{}) bool {
+		return func(data interface{}) bool {
+			return data.(*upsertVehicle).id == id
+		}
+	}
+	for i := 0; i < 100; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		if index.Upsert(p, p, byID(i), &upsertVehicle{i, p}) {
+			t.Fatalf("vehicle %d: expected an insert", i)
+		}
+	}
+	// the same vehicle reports again, with a box that covers its last
+	// position and the new position
+	min, max := [2]float64{10, 10}, [2]float64{10.5, 10.5}
+	v := &upsertVehicle{10, max}
+	if !index.Upsert(min, max, byID(10), v) {
+		t.Fatal("expected a replace")
+	}
+	if index.Len() != 100 {
+		t.Fatalf("expected %d, got %d", 100, index.Len())
+	}
+	var found []interface{}
+	index.Search(min, max, func(_, _ [2]float64, data interface{}) bool {
+		found = append(found, data)
+		return true
+	})
+	if len(found) != 1 || found[0] != v {
+		t.Fatalf("unexpected %v", found)
+	}
+	// a vehicle that's not in the rect is inserted
+	if index.Upsert(min, max, byID(50), &upsertVehicle{50, min}) ||
+		index.Len() != 101 {
+		t.Fatal("expected an insert")
+	}
+}
@@ -0,0 +1,104 @@
+package geoindex
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+type validateItem struct {
+	min, max [2]float64
+	data     interface{}
+}
+
+// Validate checks that the wrapped tree conforms to the contract that the
+// Index relies on. It walks the Children hierarchy and returns an error when
+// a child box is not contained by its parent box, when the root box does not
+// match the Bounds, or when the items found in the hierarchy are not the same
+// as the items from Scan and Len.
+func (index *Index) Validate() (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic while walking children: %v", v)
+		}
+	}()
+	var leaves []validateItem
+	var walk func(parent child.Child, depth int) error
+	walk = func(parent child.Child, depth int) error {
+		for _, c := range index.tree.Children(parent.Data, nil) {
+			if !contains(parent.Min, parent.Max, c.Min, c.Max) {
+				return fmt.Errorf("child %v %v at depth %d is not "+
+					"contained by parent %v %v",
+					c.Min, c.Max, depth, parent.Min, parent.Max)
+			}
+			if c.Item {
+				leaves = append(leaves, validateItem{c.Min, c.Max, c.Data})
+			} else if err := walk(c, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	roots := index.tree.Children(nil, nil)
+	if len(roots) > 0 {
+		min, max := roots[0].Min, roots[0].Max
+		for _, c := range roots[1:] {
+			min, max = expand(min, max, c.Min, c.Max)
+		}
+		bmin, bmax := index.tree.Bounds()
+		if min != bmin || max != bmax {
+			return fmt.Errorf("root bounds %v %v do not match Bounds %v %v",
+				min, max, bmin, bmax)
+		}
+	}
+	for _, c := range roots {
+		if c.Item {
+			leaves = append(leaves, validateItem{c.Min, c.Max, c.Data})
+		} else if err := walk(c, 1); err != nil {
+			return err
+		}
+	}
+	if len(leaves) != index.tree.Len() {
+		return fmt.Errorf("found %d items in children, but Len is %d",
+			len(leaves), index.tree.Len())
+	}
+	counts := make(map[validateItem]int)
+	index.tree.Scan(func(min, max [2]float64, data interface{}) bool {
+		if data != nil && !reflect.TypeOf(data).Comparable() {
+			// cannot compare the data, only the boxes
+			data = nil
+		}
+		counts[validateItem{min, max, data}]++
+		return true
+	})
+	for _, item := range leaves {
+		if item.data != nil && !reflect.TypeOf(item.data).Comparable() {
+			item.data = nil
+		}
+		if counts[item] == 0 {
+			return fmt.Errorf("item %v %v %v found in children but not scan",
+				item.min, item.max, item.data)
+		}
+		counts[item]--
+	}
+	return nil
+}
+
+func contains(aMin, aMax, bMin, bMax [2]float64) bool {
+	return bMin[0] >= aMin[0] && bMax[0] <= aMax[0] &&
+		bMin[1] >= aMin[1] && bMax[1] <= aMax[1]
+}
+
+func expand(aMin, aMax, bMin, bMax [2]float64) (min, max [2]float64) {
+	min, max = aMin, aMax
+	for i := 0; i < 2; i++ {
+		if bMin[i] < min[i] {
+			min[i] = bMin[i]
+		}
+		if bMax[i] > max[i] {
+			max[i] = bMax[i]
+		}
+	}
+	return min, max
+}
@@ -0,0 +1,31 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestValidate(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	boxes := randBoxes(10000)
+	for _, box := range boxes {
+		index.Insert(box.min, box.max, box)
+	}
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, box := range boxes[:5000] {
+		index.Delete(box.min, box.max, box)
+	}
+	if err := index.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	kd := Wrap(BuildKDBush([][2]float64{{1, 2}, {3, 4}, {5, 6}}, 1, nil))
+	if err := kd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
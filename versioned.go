@@ -0,0 +1,77 @@
+package geoindex
+
+import "errors"
+
+// ErrConcurrentModification is returned by SearchConsistent and
+// ScanConsistent when the tree was modified during the operation.
+var ErrConcurrentModification = errors.New("geoindex: concurrent modification")
+
+// Versioned is an optional interface for a tree that has a version number,
+// which changes each time that the tree is modified. It allows for the Index
+// to detect a tree that is modified in the middle of an operation, which
+// may otherwise silently skip or repeat items.
+type Versioned interface {
+	// Version returns a number that changes each time the tree is
+	// modified.
+	Version() uint64
+}
+
+// Version returns the version of the wrapped tree, or false when the tree is
+// not Versioned.
+func (index *Index) Version() (version uint64, ok bool) {
+	if tr, ok := index.tree.(Versioned); ok {
+		return tr.Version(), true
+	}
+	return 0, false
+}
+
+// versionCheck returns a function that returns true when the version of the
+// tree has changed since versionCheck was called.
+func (index *Index) versionCheck() func() bool {
+	tr, ok := index.tree.(Versioned)
+	if !ok {
+		return func() bool { return false }
+	}
+	version := tr.Version()
+	return func() bool { return tr.Version() != version }
+}
+
+// SearchConsistent is like Search but stops with ErrConcurrentModification
+// when the tree is modified before all items have been returned, such as by
+// the iter function, so that the caller can restart the search rather than
+// using results with skipped or repeated items. The version is checked before
+// each item is passed to iter. When the wrapped tree is not Versioned then
+// this is the same as Search and returns nil.
+func (index *Index) SearchConsistent(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) error {
+	changed := index.versionCheck()
+	var err error
+	index.Search(min, max, func(min, max [2]float64, data interface{}) bool {
+		if changed() {
+			err = ErrConcurrentModification
+			return false
+		}
+		return iter(min, max, data)
+	})
+	return err
+}
+
+// ScanConsistent is like Scan but stops with ErrConcurrentModification when
+// the tree is modified before all items have been returned. See
+// SearchConsistent.
+func (index *Index) ScanConsistent(
+	iter func(min, max [2]float64, data interface{}) bool,
+) error {
+	changed := index.versionCheck()
+	var err error
+	index.Scan(func(min, max [2]float64, data interface{}) bool {
+		if changed() {
+			err = ErrConcurrentModification
+			return false
+		}
+		return iter(min, max, data)
+	})
+	return err
+}
@@ -0,0 +1,68 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestConsistent(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	for i, box := range randBoxes(1000) {
+		index.Insert(box.min, box.max, i)
+	}
+	v1, ok := index.Version()
+	if !ok {
+		t.Fatal("expected a versioned tree")
+	}
+	min, max := [2]float64{-180, -90}, [2]float64{180, 90}
+	var count int
+	if err := index.SearchConsistent(min, max,
+		func(min, max [2]float64, data interface{}) bool {
+			count++
+			return true
+		},
+	); err != nil || count != 1000 {
+		t.Fatalf("expected %d, got %d, %v", 1000, count, err)
+	}
+	// modify the tree in the middle of the operations
+	for _, op := range []func(iter func(min, max [2]float64,
+		data interface{}) bool) error{
+		func(iter func(min, max [2]float64, data interface{}) bool) error {
+			return index.SearchConsistent(min, max, iter)
+		},
+		index.ScanConsistent,
+	} {
+		count = 0
+		err := op(func(min, max [2]float64, data interface{}) bool {
+			count++
+			if count == 10 {
+				index.Delete(min, max, data)
+			}
+			return true
+		})
+		if err != ErrConcurrentModification || count != 10 {
+			t.Fatalf("expected %v after %d, got %v after %d",
+				ErrConcurrentModification, 10, err, count)
+		}
+	}
+	if v2, _ := index.Version(); v2 == v1 {
+		t.Fatal("expected a new version")
+	}
+	// a tree that's not versioned, which only has the Interface methods
+	index = Wrap(struct{ Interface }{&internal.RTree{}})
+	if _, ok := index.Version(); ok {
+		t.Fatal("expected a tree that's not versioned")
+	}
+	index.Insert(min, min, 1)
+	index.Insert(max, max, 2)
+	count = 0
+	if err := index.ScanConsistent(
+		func(min, max [2]float64, data interface{}) bool {
+			count++
+			return true
+		},
+	); err != nil || count != 2 {
+		t.Fatalf("expected %d, got %d, %v", 2, count, err)
+	}
+}
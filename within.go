@@ -0,0 +1,46 @@
+package geoindex
+
+// Within searches the index for items that are within a distance of a center
+// point, using the Dist of the CRS of the index to the nearest point of the
+// item box. For the default Degrees, the center is a lon,lat point and the
+// distance is the great-circle distance in meters. The dist that's passed to
+// iter is this distance. The items are returned in no specified order.
+//
+// The index is searched with the boxes of the Circle of the CRS, which for
+// Degrees is the bounding box of the circle, split in two when it crosses
+// the antimeridian and spanning all longitudes when it's over a pole.
+func (index *Index) Within(
+	center [2]float64, meters float64,
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	if meters < 0 {
+		return
+	}
+	crs := index.CRS()
+	rects := crs.Circle(center, meters)
+	for i, rect := range rects {
+		done := false
+		index.Search(rect[0], rect[1],
+			func(min, max [2]float64, data interface{}) bool {
+				for _, prev := range rects[:i] {
+					if intersects(prev[0], prev[1], min, max) {
+						// already seen in an earlier rect
+						return true
+					}
+				}
+				dist := crs.Dist(center, min, max)
+				if dist > meters {
+					return true
+				}
+				if !iter(min, max, data, dist) {
+					done = true
+					return false
+				}
+				return true
+			},
+		)
+		if done {
+			return
+		}
+	}
+}
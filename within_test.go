@@ -0,0 +1,63 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/algo"
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestWithin(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := append(randBoxes(5000), testEdgeBoxes(5000)...)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	centers := [][2]float64{
+		{0, 0}, {179.9, 10}, {-179.9, -10}, {10, 89.5}, {-120, -89.5},
+		{-112.07, 33.45},
+	}
+	for _, center := range centers {
+		for _, meters := range []float64{0, 1e3, 1e5, 1e6, 5e6, 3e7} {
+			expect := make(map[interface{}]bool)
+			for i, box := range boxes {
+				if algo.HaversineBox(center, box.min, box.max) <= meters {
+					expect[i] = true
+				}
+			}
+			got := make(map[interface{}]bool)
+			index.Within(center, meters,
+				func(min, max [2]float64, data interface{},
+					dist float64) bool {
+					if got[data] {
+						t.Fatalf("duplicate %v", data)
+					}
+					if dist > meters {
+						t.Fatalf("%v is not within %v", dist, meters)
+					}
+					got[data] = true
+					return true
+				},
+			)
+			if len(got) != len(expect) {
+				t.Fatalf("%v %v: expected %d, got %d",
+					center, meters, len(expect), len(got))
+			}
+			for data := range expect {
+				if !got[data] {
+					t.Fatalf("%v %v: missing %v", center, meters, data)
+				}
+			}
+		}
+	}
+	var count int
+	index.Within([2]float64{0, 0}, 3e7,
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			count++
+			return count < 10
+		},
+	)
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+}
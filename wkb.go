@@ -0,0 +1,226 @@
+package geoindex
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+// ErrBadWKB is returned by LoadWKB when a geometry is not valid WKB.
+var ErrBadWKB = errors.New("geoindex: bad wkb")
+
+// LoadWKB reads geometries in the Well-Known Binary format and loads their
+// bounding boxes into the tree, with the bytes of each geometry as the string
+// data of the item, so that the items can be compared for Delete and Replace.
+// The reader may have many binary geometries one after another, or may have
+// hex encoded geometries, one per line, such as the output of a PostGIS
+// query. Both the ISO and the PostGIS extended (EWKB) formats are supported,
+// including Z and M coordinates, which are ignored, and SRIDs. Empty
+// geometries are skipped. Returns the number of items that were loaded.
+// Nothing is loaded when there's an error.
+func LoadWKB(r io.Reader, tr Interface) (int, error) {
+	br := bufio.NewReader(r)
+	var items []Item
+	var err error
+	if b, _ := br.Peek(1); len(b) == 1 && b[0] != 0 && b[0] != 1 {
+		// the byte order of binary geometries is 0 or 1, so anything else
+		// is text
+		items, err = loadWKBHex(br)
+	} else {
+		items, err = loadWKBBinary(br)
+	}
+	if err != nil {
+		return 0, err
+	}
+	loadItems(tr, items)
+	return len(items), nil
+}
+
+func loadWKBBinary(br *bufio.Reader) ([]Item, error) {
+	var items []Item
+	for {
+		if _, err := br.Peek(1); err == io.EOF {
+			return items, nil
+		}
+		p := &wkbParser{r: br}
+		var e extent
+		if !p.geometry(&e) {
+			if p.err != nil && p.err != io.EOF &&
+				p.err != io.ErrUnexpectedEOF {
+				return nil, p.err
+			}
+			return nil, ErrBadWKB
+		}
+		if e.ok {
+			items = append(items, Item{e.min, e.max, string(p.raw)})
+		}
+	}
+}
+
+func loadWKBHex(br *bufio.Reader) ([]Item, error) {
+	var items []Item
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if text := strings.TrimSpace(line); text != "" {
+			b, herr := hex.DecodeString(text)
+			if herr != nil {
+				return nil, ErrBadWKB
+			}
+			p := &wkbParser{data: b}
+			var e extent
+			if !p.geometry(&e) || p.pos != len(b) {
+				return nil, ErrBadWKB
+			}
+			if e.ok {
+				items = append(items, Item{e.min, e.max, string(b)})
+			}
+		}
+		if err == io.EOF {
+			return items, nil
+		}
+	}
+}
+
+// wkbParser reads a geometry from either a reader, keeping the bytes that it
+// read in raw, or from a slice of bytes.
+type wkbParser struct {
+	r     *bufio.Reader
+	raw   []byte
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+	err   error
+}
+
+func (p *wkbParser) read(n int) []byte {
+	if p.err != nil {
+		return nil
+	}
+	if p.r == nil {
+		if len(p.data)-p.pos < n {
+			p.err = io.ErrUnexpectedEOF
+			return nil
+		}
+		b := p.data[p.pos : p.pos+n]
+		p.pos += n
+		return b
+	}
+	start := len(p.raw)
+	for i := 0; i < n; i++ {
+		c, err := p.r.ReadByte()
+		if err != nil {
+			p.err = err
+			return nil
+		}
+		p.raw = append(p.raw, c)
+	}
+	return p.raw[start:]
+}
+
+func (p *wkbParser) uint32() uint32 {
+	b := p.read(4)
+	if b == nil {
+		return 0
+	}
+	return p.order.Uint32(b)
+}
+
+func (p *wkbParser) float64() float64 {
+	b := p.read(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(p.order.Uint64(b))
+}
+
+func (p *wkbParser) geometry(e *extent) bool {
+	b := p.read(1)
+	if b == nil {
+		return false
+	}
+	switch b[0] {
+	case 0:
+		p.order = binary.BigEndian
+	case 1:
+		p.order = binary.LittleEndian
+	default:
+		return false
+	}
+	typ := p.uint32()
+	dims := 2
+	// the PostGIS extended flags
+	if typ&0x80000000 != 0 {
+		dims++
+	}
+	if typ&0x40000000 != 0 {
+		dims++
+	}
+	if typ&0x20000000 != 0 {
+		p.uint32() // srid
+	}
+	typ &= 0x0FFFFFFF
+	// the ISO dimensions
+	switch typ / 1000 {
+	case 1, 2:
+		dims++
+	case 3:
+		dims += 2
+	}
+	typ %= 1000
+	if p.err != nil || dims > 4 {
+		return false
+	}
+	switch typ {
+	case 1: // point
+		var pt extent
+		if !p.coords(&pt, 1, dims) {
+			return false
+		}
+		// an empty point has NaN coordinates
+		if pt.ok && !math.IsNaN(pt.min[0]) && !math.IsNaN(pt.min[1]) {
+			e.add(pt.min)
+		}
+		return true
+	case 2: // linestring
+		return p.coords(e, int(p.uint32()), dims)
+	case 3: // polygon
+		for n := p.uint32(); n > 0; n-- {
+			if !p.coords(e, int(p.uint32()), dims) {
+				return false
+			}
+		}
+		return p.err == nil
+	case 4, 5, 6, 7: // multi geometries and collections
+		order := p.order
+		for n := p.uint32(); n > 0; n-- {
+			if !p.geometry(e) {
+				return false
+			}
+		}
+		p.order = order
+		return p.err == nil
+	}
+	return false
+}
+
+// coords reads n coordinates
+func (p *wkbParser) coords(e *extent, n, dims int) bool {
+	for i := 0; i < n; i++ {
+		pt := [2]float64{p.float64(), p.float64()}
+		for j := 2; j < dims; j++ {
+			p.float64()
+		}
+		if p.err != nil {
+			return false
+		}
+		e.add(pt)
+	}
+	return p.err == nil
+}
@@ -0,0 +1,129 @@
+package geoindex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+// wkb encodes a geometry, where the values are written in order, using the
+// byte order of the first value.
+type wkb struct {
+	order binary.ByteOrder
+	buf   bytes.Buffer
+}
+
+func (w *wkb) begin(littleEndian bool, typ uint32) *wkb {
+	if littleEndian {
+		w.order = binary.LittleEndian
+		w.buf.WriteByte(1)
+	} else {
+		w.order = binary.BigEndian
+		w.buf.WriteByte(0)
+	}
+	return w.u32(typ)
+}
+
+func (w *wkb) u32(v uint32) *wkb {
+	var b [4]byte
+	w.order.PutUint32(b[:], v)
+	w.buf.Write(b[:])
+	return w
+}
+
+func (w *wkb) f64s(v ...float64) *wkb {
+	for _, f := range v {
+		var b [8]byte
+		w.order.PutUint64(b[:], math.Float64bits(f))
+		w.buf.Write(b[:])
+	}
+	return w
+}
+
+func TestLoadWKB(t *testing.T) {
+	tests := []struct {
+		wkb      []byte
+		min, max [2]float64
+	}{
+		{new(wkb).begin(true, 1).f64s(1, 2).buf.Bytes(),
+			[2]float64{1, 2}, [2]float64{1, 2}},
+		// ISO point Z
+		{new(wkb).begin(false, 1001).f64s(1, 2, 3).buf.Bytes(),
+			[2]float64{1, 2}, [2]float64{1, 2}},
+		// EWKB point ZM with a SRID
+		{new(wkb).begin(true, 0xE0000001).u32(4326).f64s(-1, 5, 3, 4).buf.Bytes(),
+			[2]float64{-1, 5}, [2]float64{-1, 5}},
+		{new(wkb).begin(true, 2).u32(3).f64s(0, 0, 10, 5, -3, 2).buf.Bytes(),
+			[2]float64{-3, 0}, [2]float64{10, 5}},
+		{new(wkb).begin(false, 3).u32(2).
+			u32(4).f64s(0, 0, 4, 0, 4, 4, 0, 0).
+			u32(4).f64s(1, 1, 2, 1, 2, 2, 1, 1).buf.Bytes(),
+			[2]float64{0, 0}, [2]float64{4, 4}},
+		// a collection of a big endian point and a little endian multipoint
+		{func() []byte {
+			w := new(wkb).begin(true, 7).u32(2)
+			w.begin(false, 1).f64s(8, 9)
+			w.begin(true, 4).u32(2)
+			w.begin(true, 1).f64s(0, 0)
+			w.begin(true, 1).f64s(math.NaN(), math.NaN())
+			return w.buf.Bytes()
+		}(), [2]float64{0, 0}, [2]float64{8, 9}},
+	}
+	var stream []byte
+	var lines []string
+	for _, tt := range tests {
+		stream = append(stream, tt.wkb...)
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(tt.wkb)))
+	}
+	// empty geometries are skipped
+	empty := new(wkb).begin(true, 1).f64s(math.NaN(), math.NaN()).buf.Bytes()
+	stream = append(stream, empty...)
+	lines = append(lines, hex.EncodeToString(empty), "")
+	for _, input := range [][]byte{stream, []byte(strings.Join(lines, "\n"))} {
+		tr := &internal.RTree{}
+		n, err := LoadWKB(bytes.NewReader(input), tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(tests) || tr.Len() != len(tests) {
+			t.Fatalf("expected %d, got %d", len(tests), n)
+		}
+		tr.Scan(func(min, max [2]float64, data interface{}) bool {
+			for _, tt := range tests {
+				if string(tt.wkb) == data.(string) {
+					if min != tt.min || max != tt.max {
+						t.Fatalf("expected %v %v, got %v %v",
+							tt.min, tt.max, min, max)
+					}
+					return true
+				}
+			}
+			t.Fatalf("unexpected data %x", data)
+			return false
+		})
+		// the items can be deleted
+		index := Wrap(tr)
+		if !index.Delete(tests[0].min, tests[0].max, string(tests[0].wkb)) ||
+			tr.Len() != len(tests)-1 {
+			t.Fatalf("expected %d, got %d", len(tests)-1, tr.Len())
+		}
+	}
+	for _, bad := range [][]byte{
+		tests[0].wkb[:10],
+		new(wkb).begin(true, 8).f64s(1, 2).buf.Bytes(),
+		new(wkb).begin(true, 2).u32(1000).f64s(1, 2).buf.Bytes(),
+		[]byte("01zz\n"),
+		[]byte(hex.EncodeToString(append(tests[0].wkb, 0)) + "\n"),
+	} {
+		tr := &internal.RTree{}
+		if n, err := LoadWKB(bytes.NewReader(bad), tr); err != ErrBadWKB ||
+			n != 0 || tr.Len() != 0 {
+			t.Fatalf("%x: expected %v, got %v", bad, ErrBadWKB, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package geoindex
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrBadWKT is returned by LoadWKT when a geometry is not valid WKT.
+var ErrBadWKT = errors.New("geoindex: bad wkt")
+
+// LoadWKT reads geometries in the Well-Known Text format, one per line, and
+// loads their bounding boxes into the tree, with the text of each geometry as
+// its data. The geometries may have Z and M coordinates, which are ignored,
+// and may start with an EWKT "SRID=4326;" prefix. Blank lines and empty
+// geometries are skipped. Returns the number of items that were loaded.
+// Nothing is loaded when there's an error.
+func LoadWKT(r io.Reader, tr Interface) (int, error) {
+	var items []Item
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if text := strings.TrimSpace(line); text != "" {
+			e, ok := wktExtent(text)
+			if !ok {
+				return 0, ErrBadWKT
+			}
+			if e.ok {
+				items = append(items, Item{e.min, e.max, text})
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	loadItems(tr, items)
+	return len(items), nil
+}
+
+// wktExtent returns the extent of a WKT geometry
+func wktExtent(text string) (e extent, ok bool) {
+	if strings.HasPrefix(strings.ToUpper(text), "SRID=") {
+		i := strings.IndexByte(text, ';')
+		if i == -1 {
+			return e, false
+		}
+		text = text[i+1:]
+	}
+	p := &wktParser{text: text}
+	if !p.geometry(&e) {
+		return e, false
+	}
+	p.space()
+	return e, p.pos == len(p.text)
+}
+
+type wktParser struct {
+	text string
+	pos  int
+}
+
+func (p *wktParser) space() {
+	for p.pos < len(p.text) {
+		switch p.text[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next non-space byte, or zero at the end of the text
+func (p *wktParser) peek() byte {
+	p.space()
+	if p.pos == len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+// word returns the next word in upper case
+func (p *wktParser) word() string {
+	p.space()
+	start := p.pos
+	for p.pos < len(p.text) {
+		c := p.text[p.pos] | 0x20
+		if c < 'a' || c > 'z' {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.text[start:p.pos])
+}
+
+func (p *wktParser) number() (float64, bool) {
+	p.space()
+	start := p.pos
+	for p.pos < len(p.text) &&
+		strings.IndexByte("+-.0123456789eE", p.text[p.pos]) != -1 {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.text[start:p.pos], 64)
+	return f, err == nil
+}
+
+func (p *wktParser) geometry(e *extent) bool {
+	typ := p.word()
+	switch typ {
+	case "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING",
+		"MULTIPOLYGON", "GEOMETRYCOLLECTION":
+	default:
+		return false
+	}
+	mark := p.pos
+	switch p.word() {
+	case "Z", "M", "ZM":
+	default:
+		p.pos = mark
+	}
+	if p.peek() != '(' {
+		return p.word() == "EMPTY"
+	}
+	if typ != "GEOMETRYCOLLECTION" {
+		return p.list(e)
+	}
+	p.pos++
+	for {
+		if !p.geometry(e) {
+			return false
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// list parses a list of coordinates or of nested lists, such as the rings
+// of a polygon
+func (p *wktParser) list(e *extent) bool {
+	if p.peek() != '(' {
+		return false
+	}
+	p.pos++
+	for {
+		switch c := p.peek(); {
+		case c == '(':
+			if !p.list(e) {
+				return false
+			}
+		case c|0x20 == 'e':
+			if p.word() != "EMPTY" {
+				return false
+			}
+		default:
+			if !p.coord(e) {
+				return false
+			}
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// coord parses a coordinate of two to four numbers
+func (p *wktParser) coord(e *extent) bool {
+	var pt [2]float64
+	var n int
+	for n < 4 {
+		c := p.peek()
+		if c == ',' || c == ')' {
+			break
+		}
+		f, ok := p.number()
+		if !ok {
+			return false
+		}
+		if n < 2 {
+			pt[n] = f
+		}
+		n++
+	}
+	if n < 2 {
+		return false
+	}
+	e.add(pt)
+	return true
+}
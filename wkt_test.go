@@ -0,0 +1,70 @@
+package geoindex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestLoadWKT(t *testing.T) {
+	tests := []struct {
+		wkt      string
+		min, max [2]float64
+	}{
+		{"POINT (1 2)", [2]float64{1, 2}, [2]float64{1, 2}},
+		{"point z(1 2 3)", [2]float64{1, 2}, [2]float64{1, 2}},
+		{"SRID=4326;POINT(-1.5 2e1)", [2]float64{-1.5, 20}, [2]float64{-1.5, 20}},
+		{"LINESTRING (0 0, 10 5, -3 2)", [2]float64{-3, 0}, [2]float64{10, 5}},
+		{"POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 2 1, 2 2, 1 1))",
+			[2]float64{0, 0}, [2]float64{4, 4}},
+		{"MULTIPOINT (1 1, 5 -5)", [2]float64{1, -5}, [2]float64{5, 1}},
+		{"MULTIPOINT ((1 1), EMPTY, (5 -5))", [2]float64{1, -5}, [2]float64{5, 1}},
+		{"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((10 10, 11 10, 11 11, 10 10)))",
+			[2]float64{0, 0}, [2]float64{11, 11}},
+		{"MULTILINESTRING ZM ((0 0 1 2, 3 3 4 5))",
+			[2]float64{0, 0}, [2]float64{3, 3}},
+		{"GEOMETRYCOLLECTION (POINT (8 9), LINESTRING EMPTY, " +
+			"POLYGON ((0 0, 1 0, 1 1, 0 0)))",
+			[2]float64{0, 0}, [2]float64{8, 9}},
+	}
+	var lines []string
+	for _, tt := range tests {
+		lines = append(lines, tt.wkt)
+	}
+	// blank lines and empty geometries are skipped
+	lines = append(lines, "", "  ", "POINT EMPTY", "GEOMETRYCOLLECTION EMPTY")
+	tr := &internal.RTree{}
+	n, err := LoadWKT(strings.NewReader(strings.Join(lines, "\r\n")), tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(tests) || tr.Len() != len(tests) {
+		t.Fatalf("expected %d, got %d", len(tests), n)
+	}
+	tr.Scan(func(min, max [2]float64, data interface{}) bool {
+		for _, tt := range tests {
+			if tt.wkt == data {
+				if min != tt.min || max != tt.max {
+					t.Fatalf("%s: expected %v %v, got %v %v",
+						data, tt.min, tt.max, min, max)
+				}
+				return true
+			}
+		}
+		t.Fatalf("unexpected data %v", data)
+		return false
+	})
+	for _, bad := range []string{
+		"POINT", "POINT (1)", "POINT (1 2", "POINT (1 2))", "CIRCLE (1 2)",
+		"LINESTRING (0 0, x 1)", "POINT (1 2 3 4 5)", "SRID=4326 POINT (1 2)",
+		"MULTIPOINT (1 1, EMPTIES)", "GEOMETRYCOLLECTION (POINT (1 2) POINT (3 4))",
+	} {
+		tr := &internal.RTree{}
+		input := "POINT (0 0)\n" + bad
+		if n, err := LoadWKT(strings.NewReader(input), tr); err != ErrBadWKT ||
+			n != 0 || tr.Len() != 0 {
+			t.Fatalf("%s: expected %v, got %v", bad, ErrBadWKT, err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package geoindex
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/tidwall/geoindex/child"
+)
+
+// Numeric is a constraint for the coordinate types of generic trees.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// TreeGN is a generic tree-like structure with typed coordinates and data,
+// such as the RTreeGN type from the github.com/tidwall/rtree package.
+type TreeGN[N Numeric, T any] interface {
+	Insert(min, max [2]N, data T)
+	Delete(min, max [2]N, data T)
+	Replace(
+		oldMin, oldMax [2]N, oldData T,
+		newMin, newMax [2]N, newData T,
+	)
+	Search(min, max [2]N, iter func(min, max [2]N, data T) bool)
+	Scan(iter func(min, max [2]N, data T) bool)
+	Len() int
+	Bounds() (min, max [2]N)
+}
+
+// TreeG is a generic tree-like structure with typed data, such as the RTreeG
+// type from the github.com/tidwall/rtree package.
+type TreeG[T any] interface {
+	TreeGN[float64, T]
+}
+
+type treeNearbyGN[N Numeric, T any] interface {
+	Nearby(
+		algo func(min, max [2]N, data T, item bool) (dist N),
+		iter func(min, max [2]N, data T, dist N) bool,
+	)
+}
+
+type treeChildren interface {
+	Children(parent interface{}, reuse []child.Child) []child.Child
+}
+
+// FromGN returns an Interface for a generic tree.
+// When the tree provides a Nearby function with floating point coordinates
+// then it's used by Index.Nearby.
+// When the tree provides a Children function with float64 coordinates then
+// it's used as is, otherwise the children are synthesized as a single root
+// node containing all items, which works with all Index features but does
+// not show the internal structure of the tree.
+func FromGN[N Numeric, T any](tree TreeGN[N, T]) Interface {
+	return &adapterGN[N, T]{tree: tree}
+}
+
+// FromG returns an Interface for a generic tree. See FromGN.
+func FromG[T any](tree TreeG[T]) Interface {
+	return FromGN[float64, T](tree)
+}
+
+// WrapGN wraps a generic tree with an Index.
+func WrapGN[N Numeric, T any](tree TreeGN[N, T]) *Index {
+	return Wrap(FromGN(tree))
+}
+
+// WrapG wraps a generic tree with an Index.
+func WrapG[T any](tree TreeG[T]) *Index {
+	return Wrap(FromG(tree))
+}
+
+type adapterGN[N Numeric, T any] struct {
+	tree TreeGN[N, T]
+}
+
+// flatRoot is the synthesized root node for trees without Children
+type flatRoot struct{}
+
+func toN[N Numeric](p [2]float64) [2]N {
+	return [2]N{N(p[0]), N(p[1])}
+}
+
+func fromN[N Numeric](p [2]N) [2]float64 {
+	return [2]float64{float64(p[0]), float64(p[1])}
+}
+
+// isFloat returns true when N is a floating point type
+func isFloat[N Numeric]() bool {
+	half := 0.5
+	return float64(N(half)) == half
+}
+
+// toNRect converts a float64 rect to N, rounding outwards so that searches on
+// integer coordinates include everything that touches the rect.
+func toNRect[N Numeric](min, max [2]float64) (nmin, nmax [2]N) {
+	if isFloat[N]() {
+		return toN[N](min), toN[N](max)
+	}
+	for i := 0; i < 2; i++ {
+		nmin[i] = N(math.Floor(min[i]))
+		nmax[i] = N(math.Ceil(max[i]))
+	}
+	return nmin, nmax
+}
+
+// toT returns the data as a T, and panics when it's not a T. A nil data is
+// the zero T for the types that can be nil.
+func toT[T any](data interface{}) T {
+	if v, ok := data.(T); ok {
+		return v
+	}
+	var v T
+	if data == nil {
+		switch reflect.TypeOf(&v).Elem().Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice,
+			reflect.Func, reflect.Chan:
+			return v
+		}
+	}
+	panic(fmt.Sprintf("geoindex: data is %T, not %T", data, v))
+}
+
+func (a *adapterGN[N, T]) Insert(min, max [2]float64, data interface{}) {
+	a.tree.Insert(toN[N](min), toN[N](max), toT[T](data))
+}
+
+func (a *adapterGN[N, T]) Delete(min, max [2]float64, data interface{}) {
+	a.tree.Delete(toN[N](min), toN[N](max), toT[T](data))
+}
+
+func (a *adapterGN[N, T]) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	a.tree.Replace(
+		toN[N](oldMin), toN[N](oldMax), toT[T](oldData),
+		toN[N](newMin), toN[N](newMax), toT[T](newData),
+	)
+}
+
+func (a *adapterGN[N, T]) Search(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	// the rounded rect may include items that are outside of the rect
+	fmin, fmax := min, max
+	nmin, nmax := toNRect[N](min, max)
+	a.tree.Search(nmin, nmax, func(min, max [2]N, data T) bool {
+		imin, imax := fromN(min), fromN(max)
+		if !intersects(imin, imax, fmin, fmax) {
+			return true
+		}
+		return iter(imin, imax, data)
+	})
+}
+
+func (a *adapterGN[N, T]) Scan(
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	a.tree.Scan(func(min, max [2]N, data T) bool {
+		return iter(fromN(min), fromN(max), data)
+	})
+}
+
+func (a *adapterGN[N, T]) Len() int {
+	return a.tree.Len()
+}
+
+func (a *adapterGN[N, T]) Bounds() (min, max [2]float64) {
+	nmin, nmax := a.tree.Bounds()
+	return fromN(nmin), fromN(nmax)
+}
+
+func (a *adapterGN[N, T]) Children(parent interface{}, reuse []child.Child,
+) []child.Child {
+	if tr, ok := a.tree.(treeChildren); ok {
+		return tr.Children(parent, reuse)
+	}
+	children := reuse
+	if parent == nil {
+		if a.tree.Len() > 0 {
+			min, max := a.Bounds()
+			children = append(children, child.Child{
+				Min: min, Max: max, Data: flatRoot{},
+			})
+		}
+	} else {
+		a.Scan(func(min, max [2]float64, data interface{}) bool {
+			children = append(children, child.Child{
+				Min: min, Max: max, Data: data, Item: true,
+			})
+			return true
+		})
+	}
+	return children
+}
+
+func (a *adapterGN[N, T]) Nearby(
+	algo func(min, max [2]float64, data interface{}, item bool,
+	) (dist float64),
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	// The dist of a native Nearby is an N, which would truncate the dist of
+	// the algo for integer coordinates.
+	if tr, ok := a.tree.(treeNearbyGN[N, T]); ok && isFloat[N]() {
+		tr.Nearby(
+			func(min, max [2]N, data T, item bool) N {
+				var v interface{}
+				if item {
+					v = data
+				}
+				return N(algo(fromN(min), fromN(max), v, item))
+			},
+			func(min, max [2]N, data T, dist N) bool {
+				return iter(fromN(min), fromN(max), data, float64(dist))
+			},
+		)
+		return
+	}
+	// the tree does not have a native Nearby, use the Children of the
+	// adapter instead.
+	Wrap(&childrenOnly{a}).Nearby(algo, iter)
+}
+
+// childrenOnly hides the Nearby function of an Interface
+type childrenOnly struct {
+	Interface
+}
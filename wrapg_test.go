@@ -0,0 +1,42 @@
+package geoindex
+
+import "testing"
+
+// The WrapG and WrapGN tests with the generic trees of the
+// github.com/tidwall/rtree package are in the rtreegindex module.
+
+func TestToNRect(t *testing.T) {
+	min, max := toNRect[int32]([2]float64{9.5, -9.5}, [2]float64{19.5, 20})
+	if min != [2]int32{9, -10} || max != [2]int32{20, 20} {
+		t.Fatalf("unexpected rect %v %v", min, max)
+	}
+	fmin, fmax := toNRect[float32]([2]float64{9.5, -9.5}, [2]float64{19.5, 20})
+	if fmin != [2]float32{9.5, -9.5} || fmax != [2]float32{19.5, 20} {
+		t.Fatalf("unexpected rect %v %v", fmin, fmax)
+	}
+	if isFloat[uint8]() || !isFloat[float64]() {
+		t.Fatal("unexpected isFloat")
+	}
+}
+
+func TestToT(t *testing.T) {
+	if v := toT[int](10); v != 10 {
+		t.Fatalf("expected %d, got %d", 10, v)
+	}
+	if v := toT[*int](nil); v != nil {
+		t.Fatalf("expected nil, got %v", v)
+	}
+	if v := toT[interface{}](nil); v != nil {
+		t.Fatalf("expected nil, got %v", v)
+	}
+	for _, data := range []interface{}{"10", nil} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%v: expected a panic", data)
+				}
+			}()
+			toT[int](data)
+		}()
+	}
+}
@@ -0,0 +1,49 @@
+package geoindex
+
+// SearchWrapped searches the index for items that intersects the rect param,
+// where the rect may cross the antimeridian. Such a rect has a min longitude
+// that's greater than its max longitude, such as [170,-10],[-170,10], and
+// it's split into two searches, one for each side of the antimeridian. The
+// items that are found by both searches are only returned once.
+// Otherwise this is the same as Search.
+func (index *Index) SearchWrapped(
+	min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	if min[0] <= max[0] {
+		index.Search(min, max, iter)
+		return
+	}
+	east := [2][2]float64{min, {180, max[1]}}
+	done := false
+	index.Search(east[0], east[1], func(min, max [2]float64,
+		data interface{}) bool {
+		if !iter(min, max, data) {
+			done = true
+			return false
+		}
+		return true
+	})
+	if done {
+		return
+	}
+	index.Search([2]float64{-180, min[1]}, max,
+		func(min, max [2]float64, data interface{}) bool {
+			if intersects(east[0], east[1], min, max) {
+				// already seen in the first search
+				return true
+			}
+			return iter(min, max, data)
+		},
+	)
+}
+
+// wrappedIntersects returns true when the item box intersects the rect,
+// where the rect may cross the antimeridian in the way of SearchWrapped.
+func wrappedIntersects(min, max, imin, imax [2]float64) bool {
+	if min[0] <= max[0] {
+		return intersects(min, max, imin, imax)
+	}
+	return intersects(min, [2]float64{180, max[1]}, imin, imax) ||
+		intersects([2]float64{-180, min[1]}, max, imin, imax)
+}
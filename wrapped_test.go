@@ -0,0 +1,50 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/tidwall/geoindex/internal"
+)
+
+func TestSearchWrapped(t *testing.T) {
+	index := Wrap(&internal.RTree{})
+	boxes := testEdgeBoxes(10000)
+	for i, box := range boxes {
+		index.Insert(box.min, box.max, i)
+	}
+	for _, rect := range [][2][2]float64{
+		{{170, -20}, {-170, 20}},
+		{{-10, -20}, {10, 20}},
+		{{180, -90}, {-180, 90}},
+	} {
+		var expect int
+		for _, box := range boxes {
+			if testWrappedIntersects(rect[0], rect[1], box.min, box.max) {
+				expect++
+			}
+		}
+		seen := make(map[interface{}]bool)
+		index.SearchWrapped(rect[0], rect[1],
+			func(min, max [2]float64, data interface{}) bool {
+				if seen[data] {
+					t.Fatalf("%v: duplicate %v", rect, data)
+				}
+				seen[data] = true
+				return true
+			},
+		)
+		if len(seen) != expect {
+			t.Fatalf("%v: expected %d, got %d", rect, expect, len(seen))
+		}
+	}
+	var count int
+	index.SearchWrapped([2]float64{170, -90}, [2]float64{-170, 90},
+		func(min, max [2]float64, data interface{}) bool {
+			count++
+			return false
+		},
+	)
+	if count != 1 {
+		t.Fatalf("expected %d, got %d", 1, count)
+	}
+}